@@ -17,6 +17,7 @@ import (
 	"github.com/xcherryio/xcherry/common/log"
 	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence/archival"
 	"github.com/xcherryio/xcherry/persistence/process"
 	"github.com/xcherryio/xcherry/service/api"
 	"github.com/xcherryio/xcherry/service/async"
@@ -71,7 +72,7 @@ func StartXCherryServer(rootCtx context.Context, cfg *config.Config, services ma
 		logger.Fatal("config is invalid", tag.Error(err))
 	}
 
-	processStore, err := process.NewSQLProcessStore(*cfg.Database.ProcessStoreConfig, logger)
+	processStore, err := process.NewSQLProcessStore(*cfg.Database.ProcessStoreConfig, cfg.Database.Retention, logger)
 	if err != nil {
 		logger.Fatal("error on persistence setup", tag.Error(err))
 	}
@@ -81,10 +82,15 @@ func StartXCherryServer(rootCtx context.Context, cfg *config.Config, services ma
 		logger.Fatal("error on visibility setup", tag.Error(err))
 	}
 
+	archiver, err := archival.NewArchiver(cfg.Database.Archival, logger)
+	if err != nil {
+		logger.Fatal("error on archival setup", tag.Error(err))
+	}
+
 	var apiServer api.Server
 	if services[ApiServiceName] {
 		apiServer = api.NewDefaultAPIServerWithGin(
-			rootCtx, *cfg, processStore, visibilityStore, logger.WithTags(tag.Service(ApiServiceName)))
+			rootCtx, *cfg, processStore, visibilityStore, archiver, logger.WithTags(tag.Service(ApiServiceName)))
 		err = apiServer.Start()
 		if err != nil {
 			logger.Fatal("Failed to start api server", tag.Error(err))
@@ -94,7 +100,7 @@ func StartXCherryServer(rootCtx context.Context, cfg *config.Config, services ma
 	var asyncServer async.Server
 	if services[AsyncServiceName] {
 		asyncServer = async.NewDefaultAsyncServerWithGin(
-			rootCtx, *cfg, processStore, visibilityStore, logger.WithTags(tag.Service(AsyncServiceName)))
+			rootCtx, *cfg, processStore, visibilityStore, archiver, logger.WithTags(tag.Service(AsyncServiceName)))
 		err = asyncServer.Start()
 		if err != nil {
 			logger.Fatal("Failed to start async server", tag.Error(err))
@@ -0,0 +1,130 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+const DefaultAddress = "http://localhost:8801"
+const DefaultNamespace = "default"
+const DefaultListPageSize = 20
+
+const (
+	flagAddress   = "address"
+	flagOutput    = "output"
+	flagNamespace = "namespace"
+
+	flagRequestFile = "request-file"
+	flagEncoding    = "encoding"
+	flagData        = "data"
+	flagDataFile    = "data-file"
+	flagRequestId   = "request-id"
+
+	flagProcessId          = "process-id"
+	flagProcessExecutionId = "process-execution-id"
+	flagProcessType        = "process-type"
+	flagWorkerUrl          = "worker-url"
+	flagStartStateId       = "start-state-id"
+	flagTimeoutSeconds     = "timeout-seconds"
+	flagIdReusePolicy      = "id-reuse-policy"
+	flagSearchAttribute    = "search-attribute"
+	flagStartDelaySeconds  = "start-delay-seconds"
+
+	flagStopType = "stop-type"
+	flagReason   = "reason"
+
+	flagQuery                 = "query"
+	flagStatus                = "status"
+	flagStartTimeEarliest     = "start-time-earliest"
+	flagStartTimeLatest       = "start-time-latest"
+	flagCloseTimeEarliest     = "close-time-earliest"
+	flagCloseTimeLatest       = "close-time-latest"
+	flagSortBy                = "sort-by"
+	flagSortBySearchAttribute = "sort-by-search-attribute"
+	flagDescending            = "descending"
+	flagPageSize              = "page-size"
+	flagNextPageToken         = "next-page-token"
+
+	flagQueueName = "queue-name"
+	flagDedupId   = "dedup-id"
+	flagRpcName   = "rpc-name"
+)
+
+// BuildCLIOptions builds the options for cli
+func BuildCLIOptions() *cli.App {
+	app := cli.NewApp()
+
+	app.Name = "xcherry cli"
+	app.Usage = "tool for operating the process executions through the xCherry API service"
+
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagAddress,
+			Aliases: []string{"ad"},
+			Value:   DefaultAddress,
+			EnvVars: []string{"XCHERRY_ADDRESS"},
+			Usage:   "address of the xCherry API service",
+		},
+		&cli.StringFlag{
+			Name:    flagNamespace,
+			Aliases: []string{"ns"},
+			Value:   DefaultNamespace,
+			EnvVars: []string{"XCHERRY_NAMESPACE"},
+			Usage:   "namespace of the process executions",
+		},
+		&cli.StringFlag{
+			Name:    flagOutput,
+			Aliases: []string{"o"},
+			Value:   outputTable,
+			Usage:   "output format, either table or json",
+		},
+	}
+
+	app.Commands = []*cli.Command{
+		buildStartCommand(),
+		buildStopCommand(),
+		buildDescribeCommand(),
+		buildListCommand(),
+		buildPublishToLocalQueueCommand(),
+		buildRpcCommand(),
+		buildWaitForCompletionCommand(),
+	}
+
+	return app
+}
+
+func requestFileFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    flagRequestFile,
+		Aliases: []string{"f"},
+		Usage:   "file path of the JSON request body, the other flags take precedence over it",
+	}
+}
+
+func processIdFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    flagProcessId,
+		Aliases: []string{"pid"},
+		Usage:   "processId of the process execution",
+	}
+}
+
+// encodedObjectFlags are the flags for readEncodedObject
+func encodedObjectFlags(name string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  flagEncoding,
+			Usage: "encoding of the " + name,
+		},
+		&cli.StringFlag{
+			Name:  flagData,
+			Usage: "data of the " + name,
+		},
+		&cli.StringFlag{
+			Name:  flagDataFile,
+			Usage: "file path to read the data of the " + name + " from, cannot be used together with --" + flagData,
+		},
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// apiClient calls the APIs of the API service with plain JSON, instead of the generated xcapi client,
+// so that the extra fields of the options, which are not yet defined in the xcapi IDL, can be sent and received
+type apiClient struct {
+	address    string
+	httpClient *http.Client
+}
+
+func newAPIClient(c *cli.Context) *apiClient {
+	address := strings.TrimSuffix(c.String(flagAddress), "/")
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return &apiClient{
+		address: address,
+		// no timeout for the client, as WaitForProcessCompletion can take minutes. The timeouts are done by the server.
+		httpClient: &http.Client{},
+	}
+}
+
+// call posts the request objects merged into one JSON body, e.g. the xcapi request and its options,
+// and returns the response body
+func (a *apiClient) call(ctx context.Context, path string, requests ...interface{}) ([]byte, error) {
+	body, err := mergeJSON(requests...)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.address+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var errResp xcapi.ApiErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Details != nil {
+			return nil, fmt.Errorf("%v: %v", httpResp.Status, *errResp.Details)
+		}
+		return nil, fmt.Errorf("%v: %v", httpResp.Status, string(respBody))
+	}
+	return respBody, nil
+}
+
+// mergeJSON merges the top level fields of the objects into one JSON object, the later ones take precedence
+func mergeJSON(objs ...interface{}) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	for _, obj := range objs {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		// keep the numbers as they are
+		decoder.UseNumber()
+		var fields map[string]interface{}
+		if err := decoder.Decode(&fields); err != nil {
+			return nil, err
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
+
+// decodeResponse decodes the same response body into each of the objs, e.g. the xcapi response and its options
+func decodeResponse(body []byte, objs ...interface{}) error {
+	for _, obj := range objs {
+		if err := json.Unmarshal(body, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
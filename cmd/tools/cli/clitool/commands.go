@@ -0,0 +1,665 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"github.com/xcherryio/xcherry/service/api"
+)
+
+func buildStartCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "start",
+		Usage: "starts a process execution",
+		Flags: append([]cli.Flag{
+			requestFileFlag(),
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagProcessType,
+				Usage: "process type for the worker to lookup the process definition",
+			},
+			&cli.StringFlag{
+				Name:  flagWorkerUrl,
+				Usage: "URL of the worker to execute the states",
+			},
+			&cli.StringFlag{
+				Name:  flagStartStateId,
+				Usage: "stateId of the start state, the process execution starts without any state if not provided",
+			},
+			&cli.IntFlag{
+				Name:  flagTimeoutSeconds,
+				Usage: "timeout of the process execution, 0 means no timeout",
+			},
+			&cli.StringFlag{
+				Name: flagIdReusePolicy,
+				Usage: "one of ALLOW_IF_PREVIOUS_EXIT_ABNORMALLY, ALLOW_IF_NO_RUNNING, DISALLOW_REUSE " +
+					"and TERMINATE_IF_RUNNING",
+			},
+			&cli.IntFlag{
+				Name:  flagStartDelaySeconds,
+				Usage: "delay of running the start state",
+			},
+			&cli.StringSliceFlag{
+				Name:  flagSearchAttribute,
+				Usage: "initial search attribute as name=value, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  flagRequestId,
+				Usage: "request id to deduplicate the retries",
+			},
+		}, encodedObjectFlags("start state input")...),
+		Action: func(c *cli.Context) error {
+			var req xcapi.ProcessExecutionStartRequest
+			var options api.ProcessExecutionStartOptions
+			if err := readRequestFile(c, &req, &options); err != nil {
+				return err
+			}
+			req.Namespace = namespace(c, req.Namespace)
+			applyStringFlag(c, flagProcessId, &req.ProcessId)
+			applyStringFlag(c, flagProcessType, &req.ProcessType)
+			applyStringFlag(c, flagWorkerUrl, &req.WorkerUrl)
+			if c.IsSet(flagStartStateId) {
+				req.StartStateId = ptr.Any(c.String(flagStartStateId))
+			}
+			input, err := readEncodedObject(c)
+			if err != nil {
+				return err
+			}
+			if input != nil {
+				req.StartStateInput = input
+			}
+			if c.IsSet(flagTimeoutSeconds) || c.IsSet(flagIdReusePolicy) {
+				if req.ProcessStartConfig == nil {
+					req.ProcessStartConfig = &xcapi.ProcessStartConfig{}
+				}
+				if c.IsSet(flagTimeoutSeconds) {
+					req.ProcessStartConfig.TimeoutSeconds = ptr.Any(int32(c.Int(flagTimeoutSeconds)))
+				}
+				if c.IsSet(flagIdReusePolicy) {
+					req.ProcessStartConfig.IdReusePolicy = xcapi.ProcessIdReusePolicy(c.String(flagIdReusePolicy)).Ptr()
+				}
+			}
+			if c.IsSet(flagStartDelaySeconds) {
+				options.StartDelaySeconds = ptr.Any(int32(c.Int(flagStartDelaySeconds)))
+			}
+			searchAttributes, err := parseSearchAttributes(c.StringSlice(flagSearchAttribute))
+			if err != nil {
+				return err
+			}
+			for name, value := range searchAttributes {
+				if options.SearchAttributes == nil {
+					options.SearchAttributes = map[string]interface{}{}
+				}
+				options.SearchAttributes[name] = value
+			}
+			if c.IsSet(flagRequestId) {
+				options.RequestId = ptr.Any(c.String(flagRequestId))
+			}
+			if err := requireValues(map[string]string{
+				flagProcessId:   req.ProcessId,
+				flagProcessType: req.ProcessType,
+				flagWorkerUrl:   req.WorkerUrl,
+			}); err != nil {
+				return err
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathStartProcessExecution, req, options)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			var resp xcapi.ProcessExecutionStartResponse
+			if err := decodeResponse(body, &resp); err != nil {
+				return err
+			}
+			return p.printFields([][2]string{
+				{"Process Execution Id", resp.ProcessExecutionId},
+			})
+		},
+	}
+}
+
+func buildStopCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stop",
+		Usage: "stops the latest process execution of a processId",
+		Flags: []cli.Flag{
+			requestFileFlag(),
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagStopType,
+				Value: string(xcapi.TERMINATE),
+				Usage: "either TERMINATE or FAIL",
+			},
+			&cli.StringFlag{
+				Name:  flagReason,
+				Usage: "reason recorded in the close record of the process execution",
+			},
+			&cli.StringFlag{
+				Name:  flagRequestId,
+				Usage: "request id to deduplicate the retries",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var req xcapi.ProcessExecutionStopRequest
+			var options api.ProcessExecutionStopOptions
+			if err := readRequestFile(c, &req, &options); err != nil {
+				return err
+			}
+			req.Namespace = namespace(c, req.Namespace)
+			applyStringFlag(c, flagProcessId, &req.ProcessId)
+			if c.IsSet(flagStopType) || req.StopType == nil {
+				req.StopType = xcapi.ProcessExecutionStopType(c.String(flagStopType)).Ptr()
+			}
+			if c.IsSet(flagReason) {
+				options.Reason = ptr.Any(c.String(flagReason))
+			}
+			if c.IsSet(flagRequestId) {
+				options.RequestId = ptr.Any(c.String(flagRequestId))
+			}
+			if err := requireValues(map[string]string{flagProcessId: req.ProcessId}); err != nil {
+				return err
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathStopProcessExecution, req, options)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			return p.printFields([][2]string{
+				{"Stopped", req.ProcessId},
+			})
+		},
+	}
+}
+
+func buildDescribeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "describe",
+		Usage: "describes the latest process execution of a processId, or a process execution by its id",
+		Flags: []cli.Flag{
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagProcessExecutionId,
+				Usage: "id of the process execution to describe, instead of the latest one of the processId",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			path := api.PathDescribeProcessExecution
+			var req interface{} = xcapi.ProcessExecutionDescribeRequest{
+				Namespace: c.String(flagNamespace),
+				ProcessId: c.String(flagProcessId),
+			}
+			if c.IsSet(flagProcessExecutionId) {
+				path = api.PathDescribeProcessExecutionById
+				req = api.ProcessExecutionDescribeByIdRequest{
+					Namespace:          c.String(flagNamespace),
+					ProcessExecutionId: c.String(flagProcessExecutionId),
+				}
+			} else if err := requireValues(map[string]string{flagProcessId: c.String(flagProcessId)}); err != nil {
+				return err
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, path, req)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			var resp xcapi.ProcessExecutionDescribeResponse
+			var options api.ProcessExecutionDescribeOptions
+			if err := decodeResponse(body, &resp, &options); err != nil {
+				return err
+			}
+			fields := [][2]string{
+				{"Process Id", options.ProcessId},
+				{"Process Execution Id", resp.GetProcessExecutionId()},
+				{"Process Type", resp.GetProcessType()},
+				{"Worker Url", resp.GetWorkerUrl()},
+				{"Status", string(resp.GetStatus())},
+				{"Start Time", formatTimestamp(int64(resp.GetStartTimestamp()))},
+				{"Close Time", formatTimestamp(deref(options.CloseTimestamp))},
+				{"Paused", strconv.FormatBool(options.Paused)},
+				{"Continued From", options.ContinuedFromProcessExecutionId},
+				{"Continued As New", options.ContinuedAsNewProcessExecutionId},
+			}
+			return p.printFields(append(fields, closeRecordFields(options.CloseRecord)...))
+		},
+	}
+}
+
+func buildListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "lists the process executions with the visibility filters, all time is listed if no time filter or query",
+		Flags: []cli.Flag{
+			requestFileFlag(),
+			&cli.StringFlag{
+				Name:    flagQuery,
+				Aliases: []string{"q"},
+				Usage:   "visibility query, e.g. \"ProcessType = 'order' AND Status = 'FAILED' ORDER BY StartTime DESC\"",
+			},
+			&cli.StringFlag{
+				Name:  flagStatus,
+				Usage: "one of RUNNING, COMPLETED, FAILED, TIMEOUT and TERMINATED",
+			},
+			&cli.StringFlag{
+				Name:  flagProcessType,
+				Usage: "process type to filter by",
+			},
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagStartTimeEarliest,
+				Usage: "earliest start time in unix seconds or RFC3339, default to 0 if only the latest time is set",
+			},
+			&cli.StringFlag{
+				Name:  flagStartTimeLatest,
+				Usage: "latest start time in unix seconds or RFC3339, default to now if only the earliest time is set",
+			},
+			&cli.StringFlag{
+				Name:  flagCloseTimeEarliest,
+				Usage: "earliest close time in unix seconds or RFC3339, default to 0 if only the latest time is set",
+			},
+			&cli.StringFlag{
+				Name:  flagCloseTimeLatest,
+				Usage: "latest close time in unix seconds or RFC3339, default to now if only the earliest time is set",
+			},
+			&cli.StringSliceFlag{
+				Name:  flagSearchAttribute,
+				Usage: "search attribute to filter by as name=value, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  flagSortBy,
+				Usage: "either START_TIME or CLOSE_TIME",
+			},
+			&cli.StringFlag{
+				Name:  flagSortBySearchAttribute,
+				Usage: "search attribute to sort by",
+			},
+			&cli.BoolFlag{
+				Name:  flagDescending,
+				Usage: "sort in descending order",
+			},
+			&cli.IntFlag{
+				Name:  flagPageSize,
+				Value: DefaultListPageSize,
+				Usage: "max number of process executions to return",
+			},
+			&cli.StringFlag{
+				Name:  flagNextPageToken,
+				Usage: "token from the previous page to continue the listing",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var req xcapi.ListProcessExecutionsRequest
+			var options api.ListProcessExecutionsOptions
+			if err := readRequestFile(c, &req, &options); err != nil {
+				return err
+			}
+			req.Namespace = namespace(c, req.Namespace)
+			if c.IsSet(flagPageSize) || req.PageSize == 0 {
+				req.PageSize = int32(c.Int(flagPageSize))
+			}
+			if c.IsSet(flagNextPageToken) {
+				req.NextPageToken = ptr.Any(c.String(flagNextPageToken))
+			}
+			if c.IsSet(flagQuery) {
+				options.Query = ptr.Any(c.String(flagQuery))
+			}
+			if c.IsSet(flagStatus) {
+				req.StatusFilter = xcapi.ProcessStatus(c.String(flagStatus)).Ptr()
+			}
+			if c.IsSet(flagProcessType) {
+				req.ProcessTypeFilter = &xcapi.ProcessTypeFilter{ProcessType: c.String(flagProcessType)}
+			}
+			if c.IsSet(flagProcessId) {
+				req.ProcessIdFilter = &xcapi.ProcessIdFilter{ProcessId: ptr.Any(c.String(flagProcessId))}
+			}
+			startTimeFilter, err := parseTimeRangeFilter(c, flagStartTimeEarliest, flagStartTimeLatest)
+			if err != nil {
+				return err
+			}
+			if startTimeFilter != nil {
+				req.StartTimeFilter = startTimeFilter
+			}
+			closeTimeFilter, err := parseTimeRangeFilter(c, flagCloseTimeEarliest, flagCloseTimeLatest)
+			if err != nil {
+				return err
+			}
+			if closeTimeFilter != nil {
+				options.CloseTimeFilter = closeTimeFilter
+			}
+			searchAttributes, err := parseSearchAttributes(c.StringSlice(flagSearchAttribute))
+			if err != nil {
+				return err
+			}
+			for name, value := range searchAttributes {
+				if options.SearchAttributesFilter == nil {
+					options.SearchAttributesFilter = map[string]interface{}{}
+				}
+				options.SearchAttributesFilter[name] = value
+			}
+			if c.IsSet(flagSortBy) || c.IsSet(flagSortBySearchAttribute) || c.IsSet(flagDescending) {
+				if options.SortBy == nil {
+					options.SortBy = &api.ProcessExecutionsSortBy{}
+				}
+				if c.IsSet(flagSortBy) {
+					options.SortBy.Field = ptr.Any(c.String(flagSortBy))
+				}
+				applyStringFlag(c, flagSortBySearchAttribute, &options.SortBy.SearchAttribute)
+				if c.IsSet(flagDescending) {
+					options.SortBy.Descending = ptr.Any(c.Bool(flagDescending))
+				}
+			}
+			if req.StartTimeFilter == nil && options.Query == nil && options.CloseTimeFilter == nil {
+				// the API requires at least one of them, so list all time by default
+				req.StartTimeFilter = &xcapi.TimeRangeFilter{
+					EarliestTime: ptr.Any(int64(0)),
+					LatestTime:   ptr.Any(time.Now().Unix()),
+				}
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathListProcessExecutions, req, options)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			var resp xcapi.ListProcessExecutionsResponse
+			var infoOptions struct {
+				ProcessExecutions []api.ProcessExecutionListInfoOptions `json:"processExecutions"`
+			}
+			if err := decodeResponse(body, &resp, &infoOptions); err != nil {
+				return err
+			}
+			var rows [][]string
+			for i, exe := range resp.ProcessExecutions {
+				closeReason := ""
+				if i < len(infoOptions.ProcessExecutions) && infoOptions.ProcessExecutions[i].CloseRecord != nil {
+					closeReason = string(infoOptions.ProcessExecutions[i].CloseRecord.ReasonType)
+				}
+				rows = append(rows, []string{
+					exe.GetProcessId(),
+					exe.GetProcessType(),
+					exe.GetProcessExecutionId(),
+					string(exe.GetStatus()),
+					formatTimestamp(exe.GetStartTimestamp()),
+					formatTimestamp(exe.GetCloseTimestamp()),
+					closeReason,
+				})
+			}
+			err = p.printTable([]string{
+				"PROCESS ID", "PROCESS TYPE", "PROCESS EXECUTION ID", "STATUS", "START TIME", "CLOSE TIME", "CLOSE REASON",
+			}, rows)
+			if err != nil {
+				return err
+			}
+			if resp.NextPageToken != nil {
+				_, err = fmt.Fprintf(p.w, "\nmore results with --%v %v\n", flagNextPageToken, *resp.NextPageToken)
+			}
+			return err
+		},
+	}
+}
+
+func buildPublishToLocalQueueCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "publish-to-local-queue",
+		Usage: "publishes a message to a local queue of the latest process execution of a processId",
+		Flags: append([]cli.Flag{
+			requestFileFlag(),
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagQueueName,
+				Usage: "queue name of the message, which is appended to the messages of the request file if any",
+			},
+			&cli.StringFlag{
+				Name:  flagDedupId,
+				Usage: "dedup id of the message",
+			},
+		}, encodedObjectFlags("message payload")...),
+		Action: func(c *cli.Context) error {
+			var req xcapi.PublishToLocalQueueRequest
+			if err := readRequestFile(c, &req); err != nil {
+				return err
+			}
+			req.Namespace = namespace(c, req.Namespace)
+			applyStringFlag(c, flagProcessId, &req.ProcessId)
+			if c.IsSet(flagQueueName) {
+				message := xcapi.LocalQueueMessage{
+					QueueName: c.String(flagQueueName),
+				}
+				if c.IsSet(flagDedupId) {
+					message.DedupId = ptr.Any(c.String(flagDedupId))
+				}
+				payload, err := readEncodedObject(c)
+				if err != nil {
+					return err
+				}
+				message.Payload = payload
+				req.Messages = append(req.Messages, message)
+			}
+			if err := requireValues(map[string]string{flagProcessId: req.ProcessId}); err != nil {
+				return err
+			}
+			if len(req.Messages) == 0 {
+				return fmt.Errorf("--%v or the messages in the request file is required", flagQueueName)
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathPublishToLocalQueue, req)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			return p.printFields([][2]string{
+				{"Published Messages", strconv.Itoa(len(req.Messages))},
+			})
+		},
+	}
+}
+
+func buildRpcCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "rpc",
+		Usage: "executes an rpc of the latest process execution of a processId",
+		Flags: append([]cli.Flag{
+			requestFileFlag(),
+			processIdFlag(),
+			&cli.StringFlag{
+				Name:  flagRpcName,
+				Usage: "name of the rpc",
+			},
+			&cli.IntFlag{
+				Name:  flagTimeoutSeconds,
+				Usage: "timeout of calling the worker, default to the rpc config of the API service",
+			},
+			&cli.StringFlag{
+				Name:  flagRequestId,
+				Usage: "request id to deduplicate the retries",
+			},
+		}, encodedObjectFlags("rpc input")...),
+		Action: func(c *cli.Context) error {
+			var req xcapi.ProcessExecutionRpcRequest
+			var options api.ProcessExecutionRpcOptions
+			if err := readRequestFile(c, &req, &options); err != nil {
+				return err
+			}
+			req.Namespace = namespace(c, req.Namespace)
+			applyStringFlag(c, flagProcessId, &req.ProcessId)
+			applyStringFlag(c, flagRpcName, &req.RpcName)
+			input, err := readEncodedObject(c)
+			if err != nil {
+				return err
+			}
+			if input != nil {
+				req.Input = input
+			}
+			if c.IsSet(flagTimeoutSeconds) {
+				req.TimeoutSeconds = ptr.Any(int32(c.Int(flagTimeoutSeconds)))
+			}
+			if c.IsSet(flagRequestId) {
+				options.RequestId = ptr.Any(c.String(flagRequestId))
+			}
+			if err := requireValues(map[string]string{
+				flagProcessId: req.ProcessId,
+				flagRpcName:   req.RpcName,
+			}); err != nil {
+				return err
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathProcessExecutionRpc, req, options)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			var resp xcapi.ProcessExecutionRpcResponse
+			if err := decodeResponse(body, &resp); err != nil {
+				return err
+			}
+			return p.printFields([][2]string{
+				{"Output", formatEncodedObject(resp.Output)},
+			})
+		},
+	}
+}
+
+func buildWaitForCompletionCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "wait-for-completion",
+		Usage: "waits for the latest process execution of a processId to be closed",
+		Flags: []cli.Flag{
+			processIdFlag(),
+			&cli.IntFlag{
+				Name:  flagTimeoutSeconds,
+				Usage: "timeout of the waiting, default to the max timeout of the API service",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			req := xcapi.ProcessExecutionWaitForCompletionRequest{
+				Namespace: c.String(flagNamespace),
+				ProcessId: c.String(flagProcessId),
+			}
+			if c.IsSet(flagTimeoutSeconds) {
+				req.TimeoutSeconds = ptr.Any(int32(c.Int(flagTimeoutSeconds)))
+			}
+			if err := requireValues(map[string]string{flagProcessId: req.ProcessId}); err != nil {
+				return err
+			}
+
+			p, err := newPrinter(c)
+			if err != nil {
+				return err
+			}
+			body, err := newAPIClient(c).call(c.Context, api.PathWaitForProcessCompletion, req)
+			if err != nil {
+				return err
+			}
+			if p.json {
+				return p.printJSON(body)
+			}
+			var resp xcapi.ProcessExecutionWaitForCompletionResponse
+			var options api.ProcessExecutionWaitForCompletionOptions
+			if err := decodeResponse(body, &resp, &options); err != nil {
+				return err
+			}
+			fields := [][2]string{
+				{"Timeout", strconv.FormatBool(resp.GetTimeout())},
+				{"Status", string(resp.GetStatus())},
+				{"Process Execution Id", options.ProcessExecutionId},
+				{"Output", formatEncodedObject(options.Output)},
+			}
+			return p.printFields(append(fields, closeRecordFields(options.CloseRecord)...))
+		},
+	}
+}
+
+func closeRecordFields(closeRecord *data_models.ProcessCloseRecordJson) [][2]string {
+	if closeRecord == nil {
+		return nil
+	}
+	fields := [][2]string{
+		{"Close Reason", string(closeRecord.ReasonType)},
+		{"Stop Reason", closeRecord.StopReason},
+	}
+	if closeRecord.Failure != nil {
+		fields = append(fields,
+			[2]string{"Failed State Execution", closeRecord.Failure.StateExecutionId},
+			[2]string{"Failure", formatCompactJSON(closeRecord.Failure.Failure)})
+	}
+	return fields
+}
+
+// namespace returns the namespace flag if it's set, or the namespace of the request file.
+// The default value of the flag is used if neither is set.
+func namespace(c *cli.Context, fromRequestFile string) string {
+	if c.IsSet(flagNamespace) || fromRequestFile == "" {
+		return c.String(flagNamespace)
+	}
+	return fromRequestFile
+}
+
+// applyStringFlag sets the value of the flag to the target if the flag is set
+func applyStringFlag(c *cli.Context, flag string, target *string) {
+	if c.IsSet(flag) {
+		*target = c.String(flag)
+	}
+}
+
+// requireValues returns an error listing the flags of the empty values
+func requireValues(values map[string]string) error {
+	var missing []string
+	for flag, value := range values {
+		if value == "" {
+			missing = append(missing, "--"+flag)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing required flags: %v", strings.Join(missing, ", "))
+	}
+	return nil
+}
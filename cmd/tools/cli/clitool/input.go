@@ -0,0 +1,116 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// readRequestFile decodes the JSON file of the request-file flag into each of the objs, if the flag is set.
+// Like the API service, the same file is decoded into both the xcapi request and its options.
+// The other flags are applied afterwards, so they take precedence over the file.
+func readRequestFile(c *cli.Context, objs ...interface{}) error {
+	path := c.String(flagRequestFile)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := json.Unmarshal(data, obj); err != nil {
+			return fmt.Errorf("invalid request file %v: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// readEncodedObject returns the encoded object of the encoding, data and data-file flags,
+// or nil if neither data nor data-file is set
+func readEncodedObject(c *cli.Context) (*xcapi.EncodedObject, error) {
+	if c.IsSet(flagData) && c.IsSet(flagDataFile) {
+		return nil, fmt.Errorf("%v and %v cannot be used together", flagData, flagDataFile)
+	}
+	data := c.String(flagData)
+	if c.IsSet(flagDataFile) {
+		bytes, err := os.ReadFile(c.String(flagDataFile))
+		if err != nil {
+			return nil, err
+		}
+		data = string(bytes)
+	} else if !c.IsSet(flagData) {
+		return nil, nil
+	}
+	return &xcapi.EncodedObject{
+		Encoding: c.String(flagEncoding),
+		Data:     data,
+	}, nil
+}
+
+// parseTimestamp parses either the unix seconds or the RFC3339 time
+func parseTimestamp(value string) (int64, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %v, expecting unix seconds or RFC3339", value)
+	}
+	return t.Unix(), nil
+}
+
+// parseTimeRangeFilter returns nil if neither of the flags is set.
+// The earliest time is default to 0, and the latest time is default to now.
+func parseTimeRangeFilter(c *cli.Context, earliestFlag, latestFlag string) (*xcapi.TimeRangeFilter, error) {
+	if !c.IsSet(earliestFlag) && !c.IsSet(latestFlag) {
+		return nil, nil
+	}
+	earliest := int64(0)
+	latest := time.Now().Unix()
+	var err error
+	if c.IsSet(earliestFlag) {
+		if earliest, err = parseTimestamp(c.String(earliestFlag)); err != nil {
+			return nil, err
+		}
+	}
+	if c.IsSet(latestFlag) {
+		if latest, err = parseTimestamp(c.String(latestFlag)); err != nil {
+			return nil, err
+		}
+	}
+	return &xcapi.TimeRangeFilter{
+		EarliestTime: &earliest,
+		LatestTime:   &latest,
+	}, nil
+}
+
+// parseSearchAttributes parses the name=value pairs. The value is decoded as JSON if it's valid,
+// e.g. numbers, booleans and quoted strings, otherwise it's used as a string.
+func parseSearchAttributes(pairs []string) (map[string]interface{}, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	attributes := map[string]interface{}{}
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid search attribute %v, expecting name=value", pair)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			decoded = value
+		}
+		attributes[name] = decoded
+	}
+	return attributes, nil
+}
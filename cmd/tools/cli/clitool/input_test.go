@@ -0,0 +1,58 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"0":                         0,
+		"1701425250":                1701425250,
+		"2023-12-01T10:07:30Z":      1701425250,
+		"2023-12-01T11:07:30+01:00": 1701425250,
+	} {
+		actual, err := parseTimestamp(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+
+	for _, value := range []string{"", "yesterday", "2023-12-01"} {
+		_, err := parseTimestamp(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseSearchAttributes(t *testing.T) {
+	attributes, err := parseSearchAttributes([]string{
+		"CustomerId=abc",
+		"Amount=12.5",
+		"Vip=true",
+		"Quoted=\"123\"",
+		"Equation=a=b",
+		"Empty=",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"CustomerId": "abc",
+		"Amount":     12.5,
+		"Vip":        true,
+		"Quoted":     "123",
+		"Equation":   "a=b",
+		"Empty":      "",
+	}, attributes)
+
+	attributes, err = parseSearchAttributes(nil)
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+
+	for _, pair := range []string{"CustomerId", "=abc"} {
+		_, err := parseSearchAttributes([]string{pair})
+		assert.Error(t, err, pair)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package clitool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer prints the response either as the JSON body from the server, or as a table
+type printer struct {
+	json bool
+	w    io.Writer
+}
+
+func newPrinter(c *cli.Context) (*printer, error) {
+	switch output := c.String(flagOutput); output {
+	case outputTable:
+		return &printer{w: os.Stdout}, nil
+	case outputJSON:
+		return &printer{json: true, w: os.Stdout}, nil
+	default:
+		return nil, fmt.Errorf("invalid output %v, expecting %v or %v", output, outputTable, outputJSON)
+	}
+}
+
+func (p *printer) printJSON(body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return err
+	}
+	out.WriteString("\n")
+	_, err := out.WriteTo(p.w)
+	return err
+}
+
+// printTable prints the rows aligned under the header
+func (p *printer) printTable(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printFields prints the name and value pairs of a single object, skipping the empty values
+func (p *printer) printFields(fields [][2]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for _, field := range fields {
+		if field[1] != "" {
+			fmt.Fprintf(tw, "%v:\t%v\n", field[0], field[1])
+		}
+	}
+	return tw.Flush()
+}
+
+func formatTimestamp(unixSeconds int64) string {
+	if unixSeconds == 0 {
+		return ""
+	}
+	return time.Unix(unixSeconds, 0).Format(time.RFC3339)
+}
+
+func formatEncodedObject(obj *xcapi.EncodedObject) string {
+	if obj == nil {
+		return ""
+	}
+	if obj.Encoding == "" {
+		return obj.Data
+	}
+	return fmt.Sprintf("%v (encoding: %v)", obj.Data, obj.Encoding)
+}
+
+// formatCompactJSON returns the one line JSON of the obj, or empty if it's nil
+func formatCompactJSON(obj interface{}) string {
+	data, err := json.Marshal(obj)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
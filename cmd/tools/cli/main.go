@@ -0,0 +1,18 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/xcherryio/xcherry/cmd/tools/cli/clitool"
+	"log"
+	"os"
+)
+
+func main() {
+	app := clitool.BuildCLIOptions()
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cron parses the standard 5-field cron expressions, e.g.
+//
+//	minute hour day-of-month month day-of-week
+//	*/15   9-17 *            *     MON-FRI
+//
+// The fields support "*", values, ranges "a-b", steps "*/n" or "a-b/n", and lists "a,b".
+// The months and days of week can also be the first three letters of the names, and Sunday is 0 or 7.
+// The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported as well.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// if either of the day fields is "*", the other one is used alone,
+	// otherwise a day matches if either of them matches
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day-of-month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}}
+	// 7 is also Sunday, and is folded into 0 after parsing
+	dayOfWeekField = field{name: "day-of-week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearchYears bounds the search of the next time, for the expressions that never match like "0 0 30 2 *"
+const maxSearchYears = 5
+
+// Parse parses the cron expression
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		standard, ok := descriptors[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor %v", expr)
+		}
+		expr = standard
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q, expect 5 fields but got %v", expr, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, _, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dayOfMonth, s.dayOfMonthStar, err = parseField(fields[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dayOfWeek, s.dayOfWeekStar, err = parseField(fields[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek = s.dayOfWeek&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// Next returns the earliest matching time that is strictly after t, in the location of t.
+// It returns the zero time if there is no matching time in the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(end) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := has(s.dayOfMonth, t.Day())
+	dowMatch := has(s.dayOfWeek, int(t.Weekday()))
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// parseField returns the bits of the matching values, and whether the field is "*"
+func parseField(text string, f field) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		rangeText, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangeText = part[:i]
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step in %v field %q", f.name, part)
+			}
+		}
+
+		var low, high int
+		if rangeText == "*" {
+			low, high = f.min, f.max
+			if f.max == dayOfWeekField.max {
+				// not including the duplicated Sunday
+				high = 6
+			}
+		} else if i := strings.Index(rangeText, "-"); i >= 0 {
+			var err error
+			if low, err = parseValue(rangeText[:i], f); err != nil {
+				return 0, false, err
+			}
+			if high, err = parseValue(rangeText[i+1:], f); err != nil {
+				return 0, false, err
+			}
+			if low > high {
+				return 0, false, fmt.Errorf("invalid range in %v field %q", f.name, part)
+			}
+		} else {
+			value, err := parseValue(rangeText, f)
+			if err != nil {
+				return 0, false, err
+			}
+			low, high = value, value
+			if step > 1 {
+				// "a/n" means from a to the max
+				high = f.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, strings.HasPrefix(text, "*"), nil
+}
+
+func parseValue(text string, f field) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return i + f.min, nil
+		}
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value %q in %v field, must be between %v and %v", text, f.name, f.min, f.max)
+	}
+	return value, nil
+}
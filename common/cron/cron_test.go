@@ -0,0 +1,62 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// Friday
+	from := time.Date(2023, 12, 1, 10, 7, 30, 0, time.UTC)
+	for expr, expected := range map[string]time.Time{
+		"* * * * *":           time.Date(2023, 12, 1, 10, 8, 0, 0, time.UTC),
+		"*/15 * * * *":        time.Date(2023, 12, 1, 10, 15, 0, 0, time.UTC),
+		"0 9-17 * * MON-FRI":  time.Date(2023, 12, 1, 11, 0, 0, 0, time.UTC),
+		"30 8 * * mon":        time.Date(2023, 12, 4, 8, 30, 0, 0, time.UTC),
+		"0 0 * * 7":           time.Date(2023, 12, 3, 0, 0, 0, 0, time.UTC),
+		"0 0 1 JAN,jul *":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":          time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"5/20 10 * * *":       time.Date(2023, 12, 1, 10, 25, 0, 0, time.UTC),
+		"0 0 15 * SUN":        time.Date(2023, 12, 3, 0, 0, 0, 0, time.UTC),
+		"@hourly":             time.Date(2023, 12, 1, 11, 0, 0, 0, time.UTC),
+		"@daily":              time.Date(2023, 12, 2, 0, 0, 0, 0, time.UTC),
+		"@weekly":             time.Date(2023, 12, 3, 0, 0, 0, 0, time.UTC),
+		"@monthly":            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 30 2 *":          {},
+		"  7 10 1 12 FRI   ":  time.Date(2023, 12, 8, 10, 7, 0, 0, time.UTC),
+		"0-10/5 10 * * *":     time.Date(2023, 12, 1, 10, 10, 0, 0, time.UTC),
+		"0 0 1-7 * */7":       time.Date(2023, 12, 3, 0, 0, 0, 0, time.UTC),
+		"59 23 31 12 *":       time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC),
+		"0,10,20 10,11 * * *": time.Date(2023, 12, 1, 10, 10, 0, 0, time.UTC),
+	} {
+		schedule, err := Parse(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expected, schedule.Next(from), expr)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * FOO *",
+		"@every",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -8,6 +8,7 @@ package decision
 import (
 	"fmt"
 	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
 )
 
 func ValidateDecision(decision xcapi.StateDecision) error {
@@ -16,3 +17,49 @@ func ValidateDecision(decision xcapi.StateDecision) error {
 	}
 	return nil
 }
+
+// ValidateStartChildProcesses checks the child processes to start by a state decision of the parent process
+func ValidateStartChildProcesses(parentProcessId string, children []xcapi.ProcessExecutionStartRequest) error {
+	for _, child := range children {
+		if child.GetProcessId() == "" || child.GetProcessType() == "" || child.GetWorkerUrl() == "" {
+			return fmt.Errorf("processId, processType and workerUrl are required to start a child process")
+		}
+		if child.GetProcessId() == parentProcessId {
+			return fmt.Errorf("cannot start a child process with the same processId %v as the parent", parentProcessId)
+		}
+		if child.ProcessStartConfig != nil && child.ProcessStartConfig.AppDatabaseConfig != nil {
+			return fmt.Errorf("appDatabaseConfig is not supported for the child process %v", child.GetProcessId())
+		}
+	}
+	return nil
+}
+
+// ValidateContinueAsNew checks the continue-as-new of a state decision, it's valid to be nil
+func ValidateContinueAsNew(
+	decision xcapi.StateDecision, continueAsNew *data_models.ContinueAsNew,
+	appDatabaseConfig *data_models.InternalAppDatabaseConfig,
+) error {
+	if continueAsNew == nil {
+		return nil
+	}
+	if decision.HasThreadCloseDecision() || len(decision.GetNextStates()) > 0 {
+		return fmt.Errorf("cannot have continueAsNew with thread decision or next states")
+	}
+	if continueAsNew.StartStateId == nil && (continueAsNew.StartStateInput != nil || continueAsNew.StartStateConfig != nil) {
+		return fmt.Errorf("startStateId is required for the startStateInput and startStateConfig of continueAsNew")
+	}
+	if appDatabaseConfig != nil {
+		return fmt.Errorf("continueAsNew is not supported for the process with appDatabaseConfig")
+	}
+	return nil
+}
+
+// ValidateProcessCompletionCommands checks the process completion commands of a wait until response
+func ValidateProcessCompletionCommands(commands []data_models.ProcessCompletionCommandJson) error {
+	for _, command := range commands {
+		if command.ProcessId == "" {
+			return fmt.Errorf("processId is required for the process completion command")
+		}
+	}
+	return nil
+}
@@ -41,6 +41,13 @@ func MustNewUUID() UUID {
 	return newUuid[:]
 }
 
+// NewNameBasedUUID returns the UUID(version 5) derived from the given name,
+// the same name always results in the same UUID
+func NewNameBasedUUID(name string) UUID {
+	newUuid := uuid.NewV5(uuid.NamespaceOID, name)
+	return newUuid[:]
+}
+
 // MustParseUUID returns a UUID parsed from the given string representation
 // returns nil if the input is empty string
 // panics if the given input is malformed
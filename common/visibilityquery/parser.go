@@ -0,0 +1,309 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package visibilityquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse parses the query with the grammar below. The keywords are case-insensitive.
+//
+//	query      := [expression] [ORDER BY field [ASC|DESC]]
+//	expression := and {OR and}
+//	and        := term {AND term}
+//	term       := '(' expression ')' | field operator value | field IN '(' value {',' value} ')'
+//	operator   := '=' | '!=' | '<>' | '<' | '<=' | '>' | '>='
+//	value      := 'string' | "string" | number | TRUE | FALSE
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	result := &Query{}
+	if !p.peekKeyword("ORDER") && !p.atEnd() {
+		result.Where, err = p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekKeyword("ORDER") {
+		p.next()
+		if !p.peekKeyword("BY") {
+			return nil, p.errorf("expect BY after ORDER")
+		}
+		p.next()
+		field := p.next()
+		if field.kind != tokenIdentifier {
+			return nil, p.errorf("expect a field after ORDER BY")
+		}
+		result.OrderBy = &OrderBy{Field: field.text}
+		if p.peekKeyword("DESC") {
+			p.next()
+			result.OrderBy.Descending = true
+		} else if p.peekKeyword("ASC") {
+			p.next()
+		}
+	}
+
+	if !p.atEnd() {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return result, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdentifier tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+	tokenEnd
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '=' || r == '<' || r == '>' || r == '!':
+			start := i
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "!" {
+				return nil, fmt.Errorf("invalid operator at position %v", start)
+			}
+			if op == "<>" {
+				op = string(ComparisonOperatorNotEqual)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: start})
+		case r == '\'' || r == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at position %v", start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %v", r, i)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEnd, pos: len(runes)})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEnd {
+		p.index++
+	}
+	return t
+}
+
+func (p *parser) atEnd() bool {
+	return p.peek().kind == tokenEnd
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdentifier && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid query at position %v: %v", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseExpression() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &LogicalExpression{Operator: LogicalOperatorOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("AND") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &LogicalExpression{Operator: LogicalOperatorAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	if p.peek().kind == tokenLeftParen {
+		p.next()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRightParen {
+			return nil, p.errorf("expect )")
+		}
+		p.next()
+		return expr, nil
+	}
+
+	field := p.next()
+	if field.kind != tokenIdentifier || isKeyword(field.text) {
+		return nil, fmt.Errorf("invalid query at position %v: expect a field", field.pos)
+	}
+
+	if p.peekKeyword("IN") {
+		p.next()
+		if p.peek().kind != tokenLeftParen {
+			return nil, p.errorf("expect ( after IN")
+		}
+		p.next()
+		var values []interface{}
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.peek().kind == tokenComma {
+				p.next()
+				continue
+			}
+			if p.peek().kind != tokenRightParen {
+				return nil, p.errorf("expect , or )")
+			}
+			p.next()
+			break
+		}
+		return &ComparisonExpression{Field: field.text, Operator: ComparisonOperatorIn, Values: values}, nil
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, fmt.Errorf("invalid query at position %v: expect an operator after %v", op.pos, field.text)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &ComparisonExpression{
+		Field:    field.text,
+		Operator: ComparisonOperator(op.text),
+		Values:   []interface{}{value},
+	}, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+	case tokenNumber:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query at position %v: invalid number %v", t.pos, t.text)
+		}
+		return f, nil
+	case tokenIdentifier:
+		if strings.EqualFold(t.text, "TRUE") {
+			return true, nil
+		}
+		if strings.EqualFold(t.text, "FALSE") {
+			return false, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid query at position %v: expect a value", t.pos)
+}
+
+func isKeyword(text string) bool {
+	switch strings.ToUpper(text) {
+	case "AND", "OR", "ORDER", "BY", "ASC", "DESC", "IN", "TRUE", "FALSE":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package visibilityquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/config"
+)
+
+func TestParseEmpty(t *testing.T) {
+	query, err := Parse("  ")
+	assert.Nil(t, err)
+	assert.Nil(t, query.Where)
+	assert.Nil(t, query.OrderBy)
+}
+
+func TestParsePrecedence(t *testing.T) {
+	query, err := Parse("ProcessType = 'order' and Status = \"FAILED\" OR Status <> 'TIMEOUT'")
+	assert.Nil(t, err)
+	assert.Equal(t, &LogicalExpression{
+		Operator: LogicalOperatorOr,
+		Left: &LogicalExpression{
+			Operator: LogicalOperatorAnd,
+			Left:     Compare(FieldProcessType, ComparisonOperatorEqual, "order"),
+			Right:    Compare(FieldStatus, ComparisonOperatorEqual, "FAILED"),
+		},
+		Right: Compare(FieldStatus, ComparisonOperatorNotEqual, "TIMEOUT"),
+	}, query.Where)
+}
+
+func TestParseParenthesesAndOrderBy(t *testing.T) {
+	query, err := Parse("ProcessType = 'order' AND (StartTime >= 100 OR amount < -1.5) ORDER BY StartTime desc")
+	assert.Nil(t, err)
+	assert.Equal(t, &LogicalExpression{
+		Operator: LogicalOperatorAnd,
+		Left:     Compare(FieldProcessType, ComparisonOperatorEqual, "order"),
+		Right: &LogicalExpression{
+			Operator: LogicalOperatorOr,
+			Left:     Compare(FieldStartTime, ComparisonOperatorGreaterOrEqual, int64(100)),
+			Right:    Compare("amount", ComparisonOperatorLessThan, -1.5),
+		},
+	}, query.Where)
+	assert.Equal(t, &OrderBy{Field: FieldStartTime, Descending: true}, query.OrderBy)
+}
+
+func TestParseIn(t *testing.T) {
+	query, err := Parse("Status IN ('FAILED', 'TIMEOUT') ORDER BY CloseTime")
+	assert.Nil(t, err)
+	assert.Equal(t, &ComparisonExpression{
+		Field:    FieldStatus,
+		Operator: ComparisonOperatorIn,
+		Values:   []interface{}{"FAILED", "TIMEOUT"},
+	}, query.Where)
+	assert.Equal(t, &OrderBy{Field: FieldCloseTime}, query.OrderBy)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, q := range []string{
+		"Status =",
+		"Status = 'FAILED' AND",
+		"(Status = 'FAILED'",
+		"Status = 'FAILED",
+		"Status ! 'FAILED'",
+		"Status IN ('FAILED'",
+		"Status = 'FAILED' ORDER StartTime",
+		"Status = 'FAILED' ProcessType = 'order'",
+		"AND = 'x'",
+	} {
+		_, err := Parse(q)
+		assert.NotNil(t, err, q)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	searchAttributes := map[string]config.SearchAttributeType{
+		"customerId": config.SearchAttributeTypeString,
+		"amount":     config.SearchAttributeTypeDouble,
+		"count":      config.SearchAttributeTypeInteger,
+		"vip":        config.SearchAttributeTypeBoolean,
+	}
+
+	query, err := Parse("Status = 'FAILED' AND CloseTime > '2023-12-01T00:00:00Z' AND StartTime > 100 " +
+		"AND amount > 1 AND count IN (1, 2) AND vip = true AND customerId = 'c' AND DelayedStartTime < 200 " +
+		"AND CloseReason = 'CONTINUED_AS_NEW' ORDER BY amount DESC")
+	assert.Nil(t, err)
+	assert.Nil(t, Validate(query, searchAttributes))
+	assert.Equal(t, config.SearchAttributeTypeDouble, query.OrderBy.SearchAttributeType)
+
+	var comparisons []*ComparisonExpression
+	var walk func(expr Expression)
+	walk = func(expr Expression) {
+		switch e := expr.(type) {
+		case *LogicalExpression:
+			walk(e.Left)
+			walk(e.Right)
+		case *ComparisonExpression:
+			comparisons = append(comparisons, e)
+		}
+	}
+	walk(query.Where)
+	assert.Equal(t, 9, len(comparisons))
+	assert.Equal(t, xcapi.FAILED, comparisons[0].Values[0])
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), comparisons[1].Values[0])
+	assert.Equal(t, time.Unix(100, 0), comparisons[2].Values[0])
+	assert.Equal(t, 1.0, comparisons[3].Values[0])
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, comparisons[4].Values)
+	assert.Equal(t, config.SearchAttributeTypeBoolean, comparisons[5].SearchAttributeType)
+	assert.Equal(t, "c", comparisons[6].Values[0])
+	assert.Equal(t, time.Unix(200, 0), comparisons[7].Values[0])
+	assert.Equal(t, "CONTINUED_AS_NEW", comparisons[8].Values[0])
+
+	for _, q := range []string{
+		"unknown = 1",
+		"Status = 'UNKNOWN'",
+		"Status > 'FAILED'",
+		"vip > true",
+		"count = 1.5",
+		"StartTime > 'yesterday'",
+		"ProcessId = 1",
+		"Status = 'FAILED' ORDER BY unknown",
+		"CloseReason > 'TIMEOUT'",
+		"Status = 'FAILED' ORDER BY CloseReason",
+	} {
+		query, err := Parse(q)
+		assert.Nil(t, err, q)
+		assert.NotNil(t, Validate(query, searchAttributes), q)
+	}
+}
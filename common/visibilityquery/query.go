@@ -0,0 +1,303 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+// Package visibilityquery is a small filter expression language for listing and counting process executions, e.g.
+//
+//	ProcessType = 'order' AND (Status = 'FAILED' OR Status = 'TIMEOUT') AND StartTime >= 1700000000 ORDER BY StartTime DESC
+//
+// The fields are either the built-in fields, or the search attributes registered in the config.
+// The SQL extensions translate the validated expressions into parameterized queries.
+package visibilityquery
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/config"
+)
+
+// The built-in fields. They take precedence over the search attributes with the same names.
+const (
+	FieldProcessId          = "ProcessId"
+	FieldProcessExecutionId = "ProcessExecutionId"
+	FieldProcessType        = "ProcessType"
+	FieldStatus             = "Status"
+	FieldStartTime          = "StartTime"
+	FieldCloseTime          = "CloseTime"
+	// FieldDelayedStartTime is the time to run the start state, only set for the process executions with a delayed start
+	FieldDelayedStartTime = "DelayedStartTime"
+	// FieldCloseReason is the reason type of the close record, e.g. CONTINUED_AS_NEW, only set for the closed process executions.
+	// It's not supported in ORDER BY.
+	FieldCloseReason = "CloseReason"
+)
+
+type LogicalOperator string
+
+const (
+	LogicalOperatorAnd LogicalOperator = "AND"
+	LogicalOperatorOr  LogicalOperator = "OR"
+)
+
+type ComparisonOperator string
+
+const (
+	ComparisonOperatorEqual          ComparisonOperator = "="
+	ComparisonOperatorNotEqual       ComparisonOperator = "!="
+	ComparisonOperatorLessThan       ComparisonOperator = "<"
+	ComparisonOperatorLessOrEqual    ComparisonOperator = "<="
+	ComparisonOperatorGreaterThan    ComparisonOperator = ">"
+	ComparisonOperatorGreaterOrEqual ComparisonOperator = ">="
+	ComparisonOperatorIn             ComparisonOperator = "IN"
+)
+
+type (
+	Query struct {
+		// Where is nil if there is no filter
+		Where Expression
+		// OrderBy is nil if not specified
+		OrderBy *OrderBy
+	}
+
+	// Expression is either a LogicalExpression or a ComparisonExpression
+	Expression interface {
+		isExpression()
+	}
+
+	LogicalExpression struct {
+		Operator LogicalOperator
+		Left     Expression
+		Right    Expression
+	}
+
+	ComparisonExpression struct {
+		Field    string
+		Operator ComparisonOperator
+		// Values has exactly one value, except for the IN operator.
+		// After validation, the values of StartTime/CloseTime/DelayedStartTime are time.Time, the values of Status are xcapi.ProcessStatus,
+		// the values of search attributes are string/int64/float64/bool based on the registered types,
+		// and the others are string.
+		Values []interface{}
+		// SearchAttributeType is set by the validation if the field is a search attribute
+		SearchAttributeType config.SearchAttributeType
+	}
+
+	OrderBy struct {
+		Field      string
+		Descending bool
+		// SearchAttributeType is set by the validation if the field is a search attribute
+		SearchAttributeType config.SearchAttributeType
+	}
+)
+
+func (LogicalExpression) isExpression()    {}
+func (ComparisonExpression) isExpression() {}
+
+// And combines the expressions with AND, the nil expressions are skipped
+func And(expressions ...Expression) Expression {
+	var result Expression
+	for _, expr := range expressions {
+		if expr == nil {
+			continue
+		}
+		if result == nil {
+			result = expr
+		} else {
+			result = &LogicalExpression{
+				Operator: LogicalOperatorAnd,
+				Left:     result,
+				Right:    expr,
+			}
+		}
+	}
+	return result
+}
+
+// Compare is a helper to build a ComparisonExpression with a single value
+func Compare(field string, operator ComparisonOperator, value interface{}) Expression {
+	return &ComparisonExpression{
+		Field:    field,
+		Operator: operator,
+		Values:   []interface{}{value},
+	}
+}
+
+// IsBuiltInField returns true if the field is not a search attribute
+func IsBuiltInField(field string) bool {
+	switch field {
+	case FieldProcessId, FieldProcessExecutionId, FieldProcessType, FieldStatus, FieldStartTime, FieldCloseTime,
+		FieldDelayedStartTime, FieldCloseReason:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the fields, operators and values of the query, and normalizes the values into the field types
+func Validate(query *Query, searchAttributes map[string]config.SearchAttributeType) error {
+	if query.Where != nil {
+		if err := validateExpression(query.Where, searchAttributes); err != nil {
+			return err
+		}
+	}
+	if query.OrderBy != nil {
+		if query.OrderBy.Field == FieldCloseReason {
+			return fmt.Errorf("%v is not supported in ORDER BY", FieldCloseReason)
+		}
+		if !IsBuiltInField(query.OrderBy.Field) {
+			saType, ok := searchAttributes[query.OrderBy.Field]
+			if !ok {
+				return fmt.Errorf("unknown field %v in ORDER BY", query.OrderBy.Field)
+			}
+			query.OrderBy.SearchAttributeType = saType
+		}
+	}
+	return nil
+}
+
+func validateExpression(expr Expression, searchAttributes map[string]config.SearchAttributeType) error {
+	switch e := expr.(type) {
+	case *LogicalExpression:
+		if e.Operator != LogicalOperatorAnd && e.Operator != LogicalOperatorOr {
+			return fmt.Errorf("unsupported logical operator %v", e.Operator)
+		}
+		if err := validateExpression(e.Left, searchAttributes); err != nil {
+			return err
+		}
+		return validateExpression(e.Right, searchAttributes)
+	case *ComparisonExpression:
+		return validateComparison(e, searchAttributes)
+	default:
+		return fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+func validateComparison(e *ComparisonExpression, searchAttributes map[string]config.SearchAttributeType) error {
+	if len(e.Values) == 0 || (e.Operator != ComparisonOperatorIn && len(e.Values) != 1) {
+		return fmt.Errorf("invalid number of values for %v %v", e.Field, e.Operator)
+	}
+
+	var normalize func(value interface{}) (interface{}, error)
+	equalityOnly := false
+	switch e.Field {
+	case FieldProcessId, FieldProcessExecutionId, FieldProcessType:
+		normalize = normalizeString
+	case FieldStatus:
+		equalityOnly = true
+		normalize = normalizeStatus
+	case FieldCloseReason:
+		equalityOnly = true
+		normalize = normalizeString
+	case FieldStartTime, FieldCloseTime, FieldDelayedStartTime:
+		normalize = normalizeTime
+	default:
+		saType, ok := searchAttributes[e.Field]
+		if !ok {
+			return fmt.Errorf("unknown field %v", e.Field)
+		}
+		e.SearchAttributeType = saType
+		switch saType {
+		case config.SearchAttributeTypeString:
+			normalize = normalizeString
+		case config.SearchAttributeTypeInteger:
+			normalize = normalizeInteger
+		case config.SearchAttributeTypeDouble:
+			normalize = normalizeDouble
+		case config.SearchAttributeTypeBoolean:
+			equalityOnly = true
+			normalize = normalizeBoolean
+		default:
+			return fmt.Errorf("unsupported search attribute type %v", saType)
+		}
+	}
+
+	switch e.Operator {
+	case ComparisonOperatorEqual, ComparisonOperatorNotEqual, ComparisonOperatorIn:
+	case ComparisonOperatorLessThan, ComparisonOperatorLessOrEqual,
+		ComparisonOperatorGreaterThan, ComparisonOperatorGreaterOrEqual:
+		if equalityOnly {
+			return fmt.Errorf("operator %v is not supported for %v", e.Operator, e.Field)
+		}
+	default:
+		return fmt.Errorf("unsupported comparison operator %v", e.Operator)
+	}
+
+	for i, value := range e.Values {
+		normalized, err := normalize(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %v: %w", e.Field, err)
+		}
+		e.Values[i] = normalized
+	}
+	return nil
+}
+
+func normalizeString(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%v is not a string", value)
+}
+
+func normalizeStatus(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case xcapi.ProcessStatus:
+		value = string(v)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a string", value)
+	}
+	status, err := xcapi.NewProcessStatusFromValue(s)
+	if err != nil {
+		return nil, err
+	}
+	return *status, nil
+}
+
+// normalizeTime accepts the unix seconds, or the RFC3339 format
+func normalizeTime(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("%v is not a unix timestamp in seconds or a RFC3339 time", value)
+	}
+}
+
+func normalizeInteger(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), nil
+		}
+	}
+	return nil, fmt.Errorf("%v is not an integer", value)
+}
+
+func normalizeDouble(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%v is not a number", value)
+}
+
+func normalizeBoolean(value interface{}) (interface{}, error) {
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("%v is not a boolean", value)
+}
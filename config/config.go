@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -42,6 +43,33 @@ type (
 		// Only SQL is supported for now.
 		ProcessStoreConfig    *SQL `yaml:"processStore"`
 		VisibilityStoreConfig *SQL `yaml:"visibilityStore"`
+		// SearchAttributes is the registry of the custom search attributes for visibility, from name to type.
+		// Only the registered search attributes can be set on process executions, or be used in filtering and sorting.
+		SearchAttributes map[string]SearchAttributeType `yaml:"searchAttributes"`
+		// Retention is how long to keep the closed process executions before deleting all their data
+		Retention RetentionConfig `yaml:"retention"`
+		// Archival is for archiving the closed process executions before deleting them after the retention.
+		// It's disabled if not specified.
+		Archival *ArchivalConfig `yaml:"archival"`
+	}
+
+	RetentionConfig struct {
+		// Default is the retention for the namespaces that are not configured in Namespaces.
+		// If not specified then the closed process executions are kept forever.
+		Default time.Duration `yaml:"default"`
+		// Namespaces is the retention per namespace. Zero means keeping forever.
+		Namespaces map[string]time.Duration `yaml:"namespaces"`
+	}
+
+	ArchivalConfig struct {
+		// Filesystem archives each process execution into a gzipped JSON file under the directory.
+		// Only filesystem archival is supported for now.
+		Filesystem *FilesystemArchivalConfig `yaml:"filesystem"`
+	}
+
+	FilesystemArchivalConfig struct {
+		// Directory is the root directory of the archive, the files are grouped by namespace in subdirectories
+		Directory string `yaml:"directory"`
 	}
 
 	ApiServiceConfig struct {
@@ -52,6 +80,12 @@ type (
 		// AsyncServiceAddress is the address for API service to call the AsyncService's internal APIs
 		// It's required in the standalone mode, but not needed in the cluster mode
 		AsyncServiceAddress string `yaml:"asyncServiceAddress"`
+		// BatchOperation is the config for running batch operations
+		BatchOperation BatchOperationConfig `yaml:"batchOperation"`
+		// WaitForProcessCompletion is the config for the WaitForProcessCompletion API
+		WaitForProcessCompletion WaitForProcessCompletionConfig `yaml:"waitForProcessCompletion"`
+		// WebUI is the config for the web UI to browse the process executions. It's disabled by default.
+		WebUI WebUIConfig `yaml:"webUI"`
 	}
 
 	AsyncServiceConfig struct {
@@ -187,6 +221,8 @@ type (
 
 	AsyncServiceMode string
 
+	SearchAttributeType string
+
 	RpcConfig struct {
 		// MaxRpcAPITimeout is the maximum timeout for RPC APIs
 		// Exceeding the timeout will cause the timeout to be capped at this value.
@@ -196,8 +232,62 @@ type (
 		// If not specified then the default value of 10 seconds is used.
 		DefaultRpcAPITimeout time.Duration `yaml:"defaultRpcAPITimeout"`
 	}
+
+	WaitForProcessCompletionConfig struct {
+		// MaxTimeout is the maximum timeout of the WaitForProcessCompletion API
+		// Exceeding the timeout will cause the timeout to be capped at this value.
+		// It's also capped by the HttpServer.WriteTimeout, so that the response can still be written.
+		// If not specified then the default value of 10 minutes is used.
+		MaxTimeout time.Duration `yaml:"maxTimeout"`
+	}
+
+	WebUIConfig struct {
+		// Enabled serves the embedded web UI under /ui/ by the HttpServer of the API service,
+		// together with the read endpoints it calls. There is no authentication, so only enable it
+		// when the API service is not exposed to untrusted networks.
+		Enabled bool `yaml:"enabled"`
+	}
+
+	BatchOperationConfig struct {
+		// PollInterval is the interval to look for the running batch operations that are not owned by any instance
+		// If not specified then the default value of 10 seconds is used.
+		PollInterval time.Duration `yaml:"pollInterval"`
+		// LeaseDuration is how long an instance owns a batch operation before renewing the lease.
+		// Another instance can take over the batch operation after the lease is expired.
+		// If not specified then the default value of 1 minute is used.
+		LeaseDuration time.Duration `yaml:"leaseDuration"`
+		// PageSize is the page size of the visibility query to select the process executions
+		// If not specified then the default value of 100 is used.
+		PageSize int32 `yaml:"pageSize"`
+		// DefaultRps is the default number of actions to apply per second for a batch operation
+		// If not specified then the default value of 10 is used.
+		DefaultRps int32 `yaml:"defaultRps"`
+		// MaxRps is the maximum number of actions to apply per second for a batch operation
+		// If not specified then the default value of 100 is used.
+		MaxRps int32 `yaml:"maxRps"`
+		// MaxConcurrentBatchOperations is the maximum number of batch operations running in an instance
+		// If not specified then the default value of 5 is used.
+		MaxConcurrentBatchOperations int `yaml:"maxConcurrentBatchOperations"`
+	}
 )
 
+const (
+	SearchAttributeTypeString  SearchAttributeType = "STRING"
+	SearchAttributeTypeInteger SearchAttributeType = "INTEGER"
+	SearchAttributeTypeDouble  SearchAttributeType = "DOUBLE"
+	SearchAttributeTypeBoolean SearchAttributeType = "BOOLEAN"
+)
+
+// GetRetention returns the retention of the namespace, zero means keeping forever
+func (c RetentionConfig) GetRetention(namespace string) time.Duration {
+	if retention, ok := c.Namespaces[namespace]; ok {
+		return retention
+	}
+	return c.Default
+}
+
+var searchAttributeNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 const (
 	// AsyncServiceModeStandalone means there is only one node for async service
 	AsyncServiceModeStandalone = "standalone"
@@ -240,6 +330,33 @@ func (c *Config) ValidateAndSetDefaults() error {
 		c.Database.Shards = 1
 	}
 
+	for name, saType := range c.Database.SearchAttributes {
+		if !searchAttributeNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid search attribute name %v, must only contain letters, digits and underscores", name)
+		}
+		switch saType {
+		case SearchAttributeTypeString, SearchAttributeTypeInteger, SearchAttributeTypeDouble, SearchAttributeTypeBoolean:
+		default:
+			return fmt.Errorf("invalid type %v for search attribute %v", saType, name)
+		}
+	}
+
+	if c.Database.Retention.Default < 0 {
+		return fmt.Errorf("invalid default retention %v", c.Database.Retention.Default)
+	}
+	for namespace, retention := range c.Database.Retention.Namespaces {
+		if retention < 0 {
+			return fmt.Errorf("invalid retention %v for namespace %v", retention, namespace)
+		}
+	}
+
+	if c.Database.Archival != nil {
+		fsConfig := c.Database.Archival.Filesystem
+		if fsConfig == nil || fsConfig.Directory == "" {
+			return fmt.Errorf("archival.filesystem.directory is required when archival is enabled")
+		}
+	}
+
 	if c.ApiService != nil {
 		rpcConfig := &c.ApiService.Rpc
 		if rpcConfig.MaxRpcAPITimeout == 0 {
@@ -249,6 +366,30 @@ func (c *Config) ValidateAndSetDefaults() error {
 			rpcConfig.DefaultRpcAPITimeout = 10 * time.Second
 		}
 
+		if c.ApiService.WaitForProcessCompletion.MaxTimeout == 0 {
+			c.ApiService.WaitForProcessCompletion.MaxTimeout = 10 * time.Minute
+		}
+
+		batchConfig := &c.ApiService.BatchOperation
+		if batchConfig.PollInterval == 0 {
+			batchConfig.PollInterval = 10 * time.Second
+		}
+		if batchConfig.LeaseDuration == 0 {
+			batchConfig.LeaseDuration = time.Minute
+		}
+		if batchConfig.PageSize == 0 {
+			batchConfig.PageSize = 100
+		}
+		if batchConfig.DefaultRps == 0 {
+			batchConfig.DefaultRps = 10
+		}
+		if batchConfig.MaxRps == 0 {
+			batchConfig.MaxRps = 100
+		}
+		if batchConfig.MaxConcurrentBatchOperations == 0 {
+			batchConfig.MaxConcurrentBatchOperations = 5
+		}
+
 		if c.Membership == nil && c.ApiService.AsyncServiceAddress == "" {
 			return fmt.Errorf("ApiService.AsyncServiceAddress is required if not using Membership")
 		}
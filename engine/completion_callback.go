@@ -0,0 +1,119 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// processCompletionCallbackTask posts the completion payload to the callback url,
+// and retries with the backoff of the retry policy registered by StartProcess
+func (w *immediateTaskConcurrentProcessor) processCompletionCallbackTask(
+	ctx context.Context, task data_models.ImmediateTask,
+) error {
+	info := task.ImmediateTaskInfo.CompletionCallbackInfo
+	if info == nil {
+		return fmt.Errorf("completion callback info is not set")
+	}
+
+	if task.ImmediateTaskInfo.WorkerTaskBackoffInfo == nil {
+		task.ImmediateTaskInfo.WorkerTaskBackoffInfo = createWorkerTaskBackoffInfo()
+	}
+	backoffInfo := task.ImmediateTaskInfo.WorkerTaskBackoffInfo
+	backoffInfo.CompletedAttempts++
+
+	request := data_models.RecordCompletionCallbackAttemptRequest{
+		Task: task,
+	}
+	statusCode, err := w.postCompletionCallback(ctx, *info)
+	if err == nil {
+		request.Delivered = true
+	} else {
+		details := err.Error()
+		maxDetailSize := w.cfg.AsyncService.ImmediateTaskQueue.MaxStateAPIFailureDetailSize
+		if len(details) > maxDetailSize {
+			details = details[:maxDetailSize] + "...(truncated)"
+		}
+		request.LastFailure = &data_models.StateExecutionFailureJson{
+			StatusCode:           &statusCode,
+			Details:              &details,
+			CompletedAttempts:    &backoffInfo.CompletedAttempts,
+			LastAttemptTimestamp: ptr.Any(time.Now().Unix()),
+		}
+
+		nextIntervalSecs, shouldRetry := GetNextBackoff(
+			backoffInfo.CompletedAttempts, backoffInfo.FirstAttemptTimestampSeconds, info.RetryPolicy)
+		if shouldRetry {
+			request.NextAttemptTimestampSeconds = ptr.Any(time.Now().Unix() + int64(nextIntervalSecs))
+			request.LastFailure.NextAttemptTimestamp = request.NextAttemptTimestampSeconds
+		}
+
+		w.logger.Info("failed to post completion callback",
+			tag.Error(err),
+			tag.StatusCode(int(statusCode)),
+			tag.Namespace(info.Payload.Namespace),
+			tag.ProcessId(info.Payload.ProcessId),
+			tag.ProcessExecutionId(task.ProcessExecutionId.String()),
+			tag.Value(info.Url))
+	}
+
+	err = w.processStore.RecordCompletionCallbackAttempt(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if request.NextAttemptTimestampSeconds != nil {
+		w.taskNotifier.NotifyNewTimerTasks(xcapi.NotifyTimerTasksRequest{
+			ShardId:            task.ShardId,
+			Namespace:          &info.Payload.Namespace,
+			ProcessId:          &info.Payload.ProcessId,
+			ProcessExecutionId: ptr.Any(task.ProcessExecutionId.String()),
+			FireTimestamps:     []int64{*request.NextAttemptTimestampSeconds},
+		})
+	}
+	return nil
+}
+
+// postCompletionCallback returns the http status code, which is zero if the request is not sent
+func (w *immediateTaskConcurrentProcessor) postCompletionCallback(
+	ctx context.Context, info data_models.CompletionCallbackInfoJson,
+) (int32, error) {
+	body, err := json.Marshal(info.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancF := context.WithTimeout(ctx, w.cfg.AsyncService.ImmediateTaskQueue.DefaultAsyncStateAPITimeout)
+	defer cancF()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, info.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(httpResp.Body)
+		return int32(httpResp.StatusCode),
+			fmt.Errorf("callback returned status %v, responseBody: %v", httpResp.StatusCode, string(responseBody))
+	}
+	return int32(httpResp.StatusCode), nil
+}
@@ -17,6 +17,8 @@ var defaultWorkerTaskBackoffRetryPolicy = xcapi.RetryPolicy{
 	MaximumAttemptsDurationSeconds: ptr.Any(int32(0)),
 }
 
+// DEFAULT_WAIT_FOR_TIMEOUT_MAX is the default timeout of the WaitForProcessCompletion API,
+// and the max time to wait in an async server in each round of the waiting
 const DEFAULT_WAIT_FOR_TIMEOUT_MAX int32 = 30
 
 const WaitForProcessCompletionResultStop string = "STOP"
@@ -33,6 +33,7 @@ type immediateTaskConcurrentProcessor struct {
 	// shardId: WaitForProcessCompletionChannels
 	waitForProcessCompletionChannelsPerShardMap map[int32]WaitForProcessCompletionChannels
 	taskNotifier                                TaskNotifier
+	processEventHub                             ProcessEventHub
 	processStore                                persistence.ProcessStore
 	visibilityStore                             persistence.VisibilityStore
 	logger                                      log.Logger
@@ -40,7 +41,7 @@ type immediateTaskConcurrentProcessor struct {
 }
 
 func NewImmediateTaskConcurrentProcessor(
-	ctx context.Context, cfg config.Config, notifier TaskNotifier,
+	ctx context.Context, cfg config.Config, notifier TaskNotifier, processEventHub ProcessEventHub,
 	processStore persistence.ProcessStore,
 	visibilityStore persistence.VisibilityStore, logger log.Logger,
 ) ImmediateTaskProcessor {
@@ -52,6 +53,7 @@ func NewImmediateTaskConcurrentProcessor(
 		taskToCommitChans: make(map[int32]chan<- data_models.ImmediateTask),
 		waitForProcessCompletionChannelsPerShardMap: make(map[int32]WaitForProcessCompletionChannels),
 		taskNotifier:    notifier,
+		processEventHub: processEventHub,
 		processStore:    processStore,
 		visibilityStore: visibilityStore,
 		logger:          logger,
@@ -160,6 +162,10 @@ func (w *immediateTaskConcurrentProcessor) processImmediateTask(
 		return w.processLocalQueueMessagesTask(ctx, task)
 	} else if task.TaskType == data_models.ImmediateTaskTypeVisibility {
 		return w.processVisibilityTask(ctx, task)
+	} else if task.TaskType == data_models.ImmediateTaskTypeProcessCompletionCommand {
+		return w.processProcessCompletionCommandTask(ctx, task)
+	} else if task.TaskType == data_models.ImmediateTaskTypeCompletionCallback {
+		return w.processCompletionCallbackTask(ctx, task)
 	}
 
 	prep, err := w.processStore.PrepareStateExecution(ctx, data_models.PrepareStateExecutionRequest{
@@ -173,6 +179,23 @@ func (w *immediateTaskConcurrentProcessor) processImmediateTask(
 		return err
 	}
 
+	if prep.ProcessExecutionPaused &&
+		(prep.Status == data_models.StateExecutionStatusWaitUntilRunning ||
+			prep.Status == data_models.StateExecutionStatusExecuteRunning) {
+		parkResp, err := w.processStore.ParkImmediateTask(ctx, data_models.ParkImmediateTaskRequest{
+			Task: task,
+		})
+		if err != nil {
+			return err
+		}
+		if parkResp.Parked {
+			w.logger.Debug("parked the immediate task of the paused process execution",
+				tag.ID(task.GetTaskId()), tag.ProcessExecutionId(task.ProcessExecutionId.String()))
+			return nil
+		}
+		// the process execution is resumed in the meantime
+	}
+
 	iwfWorkerBaseUrl := urlautofix.FixWorkerUrl(prep.Info.WorkerURL)
 	apiClient := xcapi.NewAPIClient(&xcapi.Configuration{
 		Servers: []xcapi.ServerConfiguration{
@@ -180,6 +203,9 @@ func (w *immediateTaskConcurrentProcessor) processImmediateTask(
 				URL: iwfWorkerBaseUrl,
 			},
 		},
+		HTTPClient: &http.Client{
+			Transport: requestExtTransport{base: http.DefaultTransport},
+		},
 	})
 
 	if prep.Status == data_models.StateExecutionStatusWaitUntilRunning {
@@ -199,19 +225,38 @@ func (w *immediateTaskConcurrentProcessor) processVisibilityTask(
 	if task.ImmediateTaskInfo.VisibilityInfo == nil {
 		return fmt.Errorf("visibility info is not set")
 	}
-	err := w.visibilityStore.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
-		Namespace:          task.ImmediateTaskInfo.VisibilityInfo.Namespace,
-		ProcessId:          task.ImmediateTaskInfo.VisibilityInfo.ProcessId,
-		ProcessExecutionId: task.ProcessExecutionId,
-		ProcessType:        task.ImmediateTaskInfo.VisibilityInfo.ProcessType,
-		Status:             task.ImmediateTaskInfo.VisibilityInfo.Status,
-		StartTime:          task.ImmediateTaskInfo.VisibilityInfo.StartTime,
-		CloseTime:          task.ImmediateTaskInfo.VisibilityInfo.CloseTime,
-	})
+	var err error
+	if task.ImmediateTaskInfo.VisibilityInfo.Deleted {
+		err = w.visibilityStore.DeleteProcessExecution(ctx, data_models.DeleteProcessExecutionVisibilityRequest{
+			Namespace:          task.ImmediateTaskInfo.VisibilityInfo.Namespace,
+			ProcessExecutionId: task.ProcessExecutionId,
+		})
+	} else {
+		err = w.visibilityStore.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+			Namespace:          task.ImmediateTaskInfo.VisibilityInfo.Namespace,
+			ProcessId:          task.ImmediateTaskInfo.VisibilityInfo.ProcessId,
+			ProcessExecutionId: task.ProcessExecutionId,
+			ProcessType:        task.ImmediateTaskInfo.VisibilityInfo.ProcessType,
+			Status:             task.ImmediateTaskInfo.VisibilityInfo.Status,
+			StartTime:          task.ImmediateTaskInfo.VisibilityInfo.StartTime,
+			CloseTime:          task.ImmediateTaskInfo.VisibilityInfo.CloseTime,
+			DelayedStartTime:   task.ImmediateTaskInfo.VisibilityInfo.DelayedStartTime,
+			CloseRecord:        task.ImmediateTaskInfo.VisibilityInfo.CloseRecord,
+			SearchAttributes:   task.ImmediateTaskInfo.VisibilityInfo.SearchAttributes,
+		})
+	}
 	if err != nil {
 		return err
 	}
 
+	w.publishProcessStatusEvent(task)
+
+	if !task.ImmediateTaskInfo.VisibilityInfo.Deleted && task.ImmediateTaskInfo.VisibilityInfo.CloseTime != nil {
+		// every path closing a process execution goes through closeProcessExecution of the process store,
+		// which adds the visibility task with the close time, so the waiting requests are signaled here
+		w.signalProcessCompletion(task.ProcessExecutionId.String(), task.ImmediateTaskInfo.VisibilityInfo.Status)
+	}
+
 	return w.processStore.DeleteImmediateTasks(ctx, data_models.DeleteImmediateTasksRequest{
 		ShardId:                  task.ShardId,
 		MinTaskSequenceInclusive: *task.TaskSequence,
@@ -219,6 +264,66 @@ func (w *immediateTaskConcurrentProcessor) processVisibilityTask(
 	})
 }
 
+// signalProcessCompletion signals the requests waiting for the closed process execution in this instance.
+// The visibility task may not be in the same shard as the process execution, so all the shards are signaled.
+// The requests waiting in other instances find out the completion by checking the database again.
+func (w *immediateTaskConcurrentProcessor) signalProcessCompletion(
+	processExecutionId string, status data_models.ProcessExecutionStatus,
+) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	for _, waitForProcessCompletionChannelsPerShard := range w.waitForProcessCompletionChannelsPerShardMap {
+		waitForProcessCompletionChannelsPerShard.Signal(processExecutionId, status.String())
+	}
+}
+
+// publishProcessStatusEvent publishes the start or the close of the process execution recorded by the visibility task
+func (w *immediateTaskConcurrentProcessor) publishProcessStatusEvent(task data_models.ImmediateTask) {
+	info := task.ImmediateTaskInfo.VisibilityInfo
+	var eventType ProcessEventType
+	var timestamp int64
+	if info.Deleted {
+		return
+	} else if info.CloseTime != nil {
+		eventType = ProcessEventTypeProcessClosed
+		timestamp = *info.CloseTime
+	} else if info.StartTime != nil {
+		eventType = ProcessEventTypeProcessStarted
+		timestamp = *info.StartTime
+	} else {
+		// only upserting the search attributes
+		return
+	}
+
+	w.processEventHub.Publish(ProcessEvent{
+		Type:               eventType,
+		Namespace:          info.Namespace,
+		ProcessId:          info.ProcessId,
+		ProcessExecutionId: task.ProcessExecutionId.String(),
+		ProcessType:        info.ProcessType,
+		ProcessStatus:      xcapi.ProcessStatus(info.Status.String()).Ptr(),
+		Timestamp:          timestamp,
+	})
+}
+
+// publishStateEvent publishes the event of the state execution of the task
+func (w *immediateTaskConcurrentProcessor) publishStateEvent(
+	eventType ProcessEventType, task data_models.ImmediateTask,
+	prep data_models.PrepareStateExecutionResponse, failure *ProcessEventFailure,
+) {
+	w.processEventHub.Publish(ProcessEvent{
+		Type:               eventType,
+		Namespace:          prep.Info.Namespace,
+		ProcessId:          prep.Info.ProcessId,
+		ProcessExecutionId: task.ProcessExecutionId.String(),
+		ProcessType:        prep.Info.ProcessType,
+		StateExecutionId:   task.GetStateExecutionId(),
+		Failure:            failure,
+		Timestamp:          time.Now().Unix(),
+	})
+}
+
 func (w *immediateTaskConcurrentProcessor) processWaitUntilTask(
 	ctx context.Context, task data_models.ImmediateTask,
 	prep data_models.PrepareStateExecutionResponse, apiClient *xcapi.APIClient,
@@ -252,6 +357,15 @@ func (w *immediateTaskConcurrentProcessor) processWaitUntilTask(
 		defer httpResp.Body.Close()
 	}
 
+	var respExt data_models.AsyncStateWaitUntilResponseExtJson
+	if err == nil {
+		err = data_models.ReadWorkerResponseExt(httpResp, &respExt)
+	}
+	processCompletionCommands := respExt.CommandRequest.ProcessCompletionCommands
+	if err == nil {
+		err = decision.ValidateProcessCompletionCommands(processCompletionCommands)
+	}
+
 	if httperror.CheckHttpResponseAndError(err, httpResp, w.logger) {
 		status, details := w.composeHttpError(err, httpResp, prep.Info, task)
 
@@ -280,11 +394,15 @@ func (w *immediateTaskConcurrentProcessor) processWaitUntilTask(
 		PublishToLocalQueue: resp.GetPublishToLocalQueue(),
 		TaskShardId:         task.ShardId,
 		TaskSequence:        task.GetTaskSequence(),
+
+		ProcessCompletionCommands: processCompletionCommands,
 	})
 	if err != nil {
 		return err
 	}
 
+	w.publishStateEvent(ProcessEventTypeStateWaitUntilCompleted, task, prep, nil)
+
 	if compResp.HasNewImmediateTask {
 		w.notifyNewImmediateTask(task.ShardId, prep, task)
 	}
@@ -323,6 +441,15 @@ func (w *immediateTaskConcurrentProcessor) applyStateFailureRecoveryPolicy(
 			Namespace:       prep.Info.Namespace,
 			ProcessId:       prep.Info.ProcessId,
 			ProcessStopType: xcapi.FAIL,
+			Failure: &data_models.ProcessFailureJson{
+				StateExecutionId: task.GetStateExecutionId(),
+				Failure: data_models.StateExecutionFailureJson{
+					StatusCode:           &status,
+					Details:              &details,
+					CompletedAttempts:    &completedAttempts,
+					LastAttemptTimestamp: ptr.Any(time.Now().Unix()),
+				},
+			},
 		})
 
 		if errStopProcess != nil {
@@ -378,6 +505,12 @@ func (w *immediateTaskConcurrentProcessor) applyStateFailureRecoveryPolicy(
 		return fmt.Errorf("unknown state failure recovery policy %v", stateRecoveryPolicy.Policy)
 	}
 
+	w.publishStateEvent(ProcessEventTypeStateApiFailed, task, prep, &ProcessEventFailure{
+		StatusCode:        status,
+		Details:           details,
+		CompletedAttempts: completedAttempts,
+		WillRetry:         false,
+	})
 	return nil
 }
 
@@ -459,7 +592,16 @@ func (w *immediateTaskConcurrentProcessor) processExecuteTask(
 		}
 	}
 
-	req := apiClient.DefaultAPI.ApiV1XcherryWorkerAsyncStateExecutePost(ctx)
+	workerApiCtx := ctx
+	if len(prep.ProcessCompletionResults) > 0 {
+		workerApiCtx = withRequestExt(ctx, data_models.AsyncStateExecuteRequestExtJson{
+			CommandResults: data_models.ProcessCompletionResultsJson{
+				ProcessCompletionResults: prep.ProcessCompletionResults,
+			},
+		})
+	}
+
+	req := apiClient.DefaultAPI.ApiV1XcherryWorkerAsyncStateExecutePost(workerApiCtx)
 	resp, httpResp, errToCheck = req.AsyncStateExecuteRequest(
 		xcapi.AsyncStateExecuteRequest{
 			Context: createApiContext(
@@ -486,6 +628,21 @@ func (w *immediateTaskConcurrentProcessor) processExecuteTask(
 		errToCheck = decision.ValidateDecision(resp.StateDecision)
 	}
 
+	var respExt data_models.AsyncStateExecuteResponseExtJson
+	if errToCheck == nil {
+		errToCheck = data_models.ReadWorkerResponseExt(httpResp, &respExt)
+	}
+	if errToCheck == nil {
+		errToCheck = data_models.ValidateSearchAttributes(
+			w.cfg.Database.SearchAttributes, respExt.UpsertSearchAttributes, true)
+	}
+	if errToCheck == nil {
+		errToCheck = decision.ValidateStartChildProcesses(prep.Info.ProcessId, respExt.StateDecision.StartChildProcesses)
+	}
+	if errToCheck == nil {
+		errToCheck = decision.ValidateContinueAsNew(resp.StateDecision, respExt.StateDecision.ContinueAsNew, prep.Info.AppDatabaseConfig)
+	}
+
 	if httperror.CheckHttpResponseAndError(errToCheck, httpResp, w.logger) {
 		status, details := w.composeHttpError(errToCheck, httpResp, prep.Info, task)
 
@@ -516,6 +673,10 @@ func (w *immediateTaskConcurrentProcessor) processExecuteTask(
 		AppDatabaseConfig:     prep.Info.AppDatabaseConfig,
 		WriteAppDatabase:      resp.WriteToAppDatabase,
 		UpdateLocalAttributes: resp.WriteToLocalAttributes,
+
+		UpsertSearchAttributes: respExt.UpsertSearchAttributes,
+		StartChildProcesses:    respExt.StateDecision.StartChildProcesses,
+		ContinueAsNew:          respExt.StateDecision.ContinueAsNew,
 	})
 	if err != nil {
 		return err
@@ -526,16 +687,18 @@ func (w *immediateTaskConcurrentProcessor) processExecuteTask(
 		w.logger.Warn("failed to write app database", tag.ID(task.GetTaskId()))
 		return fmt.Errorf("failed to write app database")
 	}
+
+	w.publishStateEvent(ProcessEventTypeStateExecuteCompleted, task, prep, nil)
+
 	if compResp.HasNewImmediateTask {
 		w.notifyNewImmediateTask(task.ShardId, prep, task)
 	}
 
-	// signal to the process completion waiting channel
-	waitForProcessCompletionChannelsPerShard, ok := w.waitForProcessCompletionChannelsPerShardMap[task.ShardId]
-	if ok && compResp.ProcessStatus != data_models.ProcessExecutionStatusUndefined &&
-		compResp.ProcessStatus != data_models.ProcessExecutionStatusRunning {
-
-		waitForProcessCompletionChannelsPerShard.Signal(task.ProcessExecutionId.String(), compResp.ProcessStatus.String())
+	if len(compResp.FireTimestamps) > 0 {
+		w.taskNotifier.NotifyNewTimerTasks(xcapi.NotifyTimerTasksRequest{
+			ShardId:        task.ShardId,
+			FireTimestamps: compResp.FireTimestamps,
+		})
 	}
 
 	return nil
@@ -617,6 +780,12 @@ func (w *immediateTaskConcurrentProcessor) retryTask(
 		ProcessExecutionId: ptr.Any(task.ProcessExecutionId.String()),
 		FireTimestamps:     []int64{fireTimeUnixSeconds},
 	})
+	w.publishStateEvent(ProcessEventTypeStateApiFailed, task, prep, &ProcessEventFailure{
+		StatusCode:        LastFailureStatus,
+		Details:           LastFailureDetails,
+		CompletedAttempts: task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts,
+		WillRetry:         true,
+	})
 	w.logger.Debug("retry is scheduled", tag.Value(nextIntervalSecs), tag.Value(time.Unix(fireTimeUnixSeconds, 0)))
 	return nil
 }
@@ -679,6 +848,25 @@ func (w *immediateTaskConcurrentProcessor) processLocalQueueMessagesTask(
 	return nil
 }
 
+func (w *immediateTaskConcurrentProcessor) processProcessCompletionCommandTask(
+	ctx context.Context, task data_models.ImmediateTask,
+) error {
+	resp, err := w.processStore.ProcessProcessCompletionCommand(ctx, data_models.ProcessProcessCompletionCommandRequest{
+		Task: task,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.HasNewImmediateTask {
+		w.taskNotifier.NotifyNewImmediateTasks(xcapi.NotifyImmediateTasksRequest{
+			ShardId:            task.ShardId,
+			ProcessExecutionId: ptr.Any(task.ProcessExecutionId.String()),
+		})
+	}
+	return nil
+}
+
 func (w *immediateTaskConcurrentProcessor) readAppDatabaseIfNeeded(
 	ctx context.Context, prep data_models.PrepareStateExecutionResponse, task data_models.ImmediateTask,
 ) (*data_models.AppDatabaseReadResponse, error) {
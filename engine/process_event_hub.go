@@ -0,0 +1,129 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"sync"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+type ProcessEventType string
+
+const (
+	ProcessEventTypeProcessStarted          ProcessEventType = "PROCESS_STARTED"
+	ProcessEventTypeStateWaitUntilCompleted ProcessEventType = "STATE_WAIT_UNTIL_COMPLETED"
+	ProcessEventTypeStateExecuteCompleted   ProcessEventType = "STATE_EXECUTE_COMPLETED"
+	ProcessEventTypeStateApiFailed          ProcessEventType = "STATE_API_FAILED"
+	ProcessEventTypeProcessClosed           ProcessEventType = "PROCESS_CLOSED"
+)
+
+const processEventSubscriptionBufferSize = 100
+
+// ProcessEvent is a change of a process execution, streamed to the subscribers of the process events
+type ProcessEvent struct {
+	Type               ProcessEventType `json:"type"`
+	Namespace          string           `json:"namespace"`
+	ProcessId          string           `json:"processId"`
+	ProcessExecutionId string           `json:"processExecutionId"`
+	ProcessType        string           `json:"processType"`
+	// StateExecutionId is only set for the events of state executions
+	StateExecutionId string `json:"stateExecutionId,omitempty"`
+	// ProcessStatus is only set for PROCESS_STARTED and PROCESS_CLOSED
+	ProcessStatus *xcapi.ProcessStatus `json:"processStatus,omitempty"`
+	// Failure is only set for STATE_API_FAILED
+	Failure *ProcessEventFailure `json:"failure,omitempty"`
+	// Timestamp is the unix seconds of when the event happened
+	Timestamp int64 `json:"timestamp"`
+}
+
+type ProcessEventFailure struct {
+	StatusCode        int32  `json:"statusCode"`
+	Details           string `json:"details"`
+	CompletedAttempts int32  `json:"completedAttempts"`
+	// WillRetry is false if the state failure recovery policy is applied after this failure
+	WillRetry bool `json:"willRetry"`
+}
+
+// ProcessEventFilter selects the events to subscribe to. Namespace is required, the empty fields match all.
+type ProcessEventFilter struct {
+	Namespace          string `json:"namespace"`
+	ProcessId          string `json:"processId,omitempty"`
+	ProcessExecutionId string `json:"processExecutionId,omitempty"`
+	ProcessType        string `json:"processType,omitempty"`
+}
+
+func (f ProcessEventFilter) Match(event ProcessEvent) bool {
+	return f.Namespace == event.Namespace &&
+		(f.ProcessId == "" || f.ProcessId == event.ProcessId) &&
+		(f.ProcessExecutionId == "" || f.ProcessExecutionId == event.ProcessExecutionId) &&
+		(f.ProcessType == "" || f.ProcessType == event.ProcessType)
+}
+
+// ProcessEventHub fans out the events of the process executions processed by this instance to the subscribers.
+// The delivery is best effort: the events are not persisted, and are dropped for the subscribers that don't keep up.
+type ProcessEventHub interface {
+	Publish(event ProcessEvent)
+	// Subscribe returns the channel of the matching events, and the function to unsubscribe,
+	// which must be called when the subscriber is done
+	Subscribe(filter ProcessEventFilter) (events <-chan ProcessEvent, unsubscribe func())
+}
+
+type processEventSubscription struct {
+	filter ProcessEventFilter
+	events chan ProcessEvent
+}
+
+type processEventHubImpl struct {
+	nextSubscriptionId int64
+	subscriptions      map[int64]processEventSubscription
+	lock               sync.RWMutex
+}
+
+func NewProcessEventHub() ProcessEventHub {
+	return &processEventHubImpl{
+		subscriptions: map[int64]processEventSubscription{},
+		lock:          sync.RWMutex{},
+	}
+}
+
+func (h *processEventHubImpl) Publish(event ProcessEvent) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for _, subscription := range h.subscriptions {
+		if !subscription.filter.Match(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			// never block the task processing for a slow subscriber
+		}
+	}
+}
+
+func (h *processEventHubImpl) Subscribe(filter ProcessEventFilter) (<-chan ProcessEvent, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	id := h.nextSubscriptionId
+	h.nextSubscriptionId++
+	events := make(chan ProcessEvent, processEventSubscriptionBufferSize)
+	h.subscriptions[id] = processEventSubscription{
+		filter: filter,
+		events: events,
+	}
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			h.lock.Lock()
+			defer h.lock.Unlock()
+
+			delete(h.subscriptions, id)
+			close(events)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProcessEventHubFilter(t *testing.T) {
+	hub := NewProcessEventHub()
+
+	nsEvents, unsubscribeNs := hub.Subscribe(ProcessEventFilter{Namespace: "ns"})
+	defer unsubscribeNs()
+	prcEvents, unsubscribePrc := hub.Subscribe(ProcessEventFilter{Namespace: "ns", ProcessId: "p1"})
+	defer unsubscribePrc()
+
+	event1 := ProcessEvent{Type: ProcessEventTypeProcessStarted, Namespace: "ns", ProcessId: "p1"}
+	event2 := ProcessEvent{Type: ProcessEventTypeProcessStarted, Namespace: "ns", ProcessId: "p2"}
+	event3 := ProcessEvent{Type: ProcessEventTypeProcessStarted, Namespace: "other-ns", ProcessId: "p1"}
+	hub.Publish(event1)
+	hub.Publish(event2)
+	hub.Publish(event3)
+
+	assert.Equal(t, event1, <-nsEvents)
+	assert.Equal(t, event2, <-nsEvents)
+	assert.Equal(t, 0, len(nsEvents))
+	assert.Equal(t, event1, <-prcEvents)
+	assert.Equal(t, 0, len(prcEvents))
+}
+
+func TestProcessEventHubSlowSubscriber(t *testing.T) {
+	hub := NewProcessEventHub()
+
+	events, unsubscribe := hub.Subscribe(ProcessEventFilter{Namespace: "ns"})
+
+	// publishing never blocks, the events exceeding the buffer are dropped
+	for i := 0; i < processEventSubscriptionBufferSize+10; i++ {
+		hub.Publish(ProcessEvent{Type: ProcessEventTypeStateExecuteCompleted, Namespace: "ns", Timestamp: int64(i)})
+	}
+	assert.Equal(t, processEventSubscriptionBufferSize, len(events))
+	assert.Equal(t, int64(0), (<-events).Timestamp)
+
+	unsubscribe()
+	// unsubscribing twice is a noop
+	unsubscribe()
+	hub.Publish(ProcessEvent{Type: ProcessEventTypeProcessClosed, Namespace: "ns"})
+
+	count := 0
+	for range events {
+		count++
+	}
+	assert.Equal(t, processEventSubscriptionBufferSize-1, count)
+}
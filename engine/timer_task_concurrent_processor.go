@@ -24,12 +24,14 @@ type timerTaskConcurrentProcessor struct {
 	currentShards map[int32]bool
 	taskNotifier  TaskNotifier
 	store         persistence.ProcessStore
-	logger        log.Logger
+	// archiver is nil if the archival is disabled
+	archiver persistence.Archiver
+	logger   log.Logger
 }
 
 func NewTimerTaskConcurrentProcessor(
 	ctx context.Context, cfg config.Config, notifier TaskNotifier,
-	store persistence.ProcessStore, logger log.Logger,
+	store persistence.ProcessStore, archiver persistence.Archiver, logger log.Logger,
 ) TimerTaskProcessor {
 	bufferSize := cfg.AsyncService.TimerTaskQueue.ProcessorBufferSize
 	return &timerTaskConcurrentProcessor{
@@ -39,6 +41,7 @@ func NewTimerTaskConcurrentProcessor(
 		currentShards:     map[int32]bool{},
 		taskNotifier:      notifier,
 		store:             store,
+		archiver:          archiver,
 		logger:            logger,
 	}
 }
@@ -106,12 +109,17 @@ func (w *timerTaskConcurrentProcessor) processTimerTask(
 	w.logger.Debug("start executing timer task", tag.ID(task.GetStateExecutionId()))
 
 	switch task.TaskType {
-	case data_models.TimerTaskTypeWorkerTaskBackoff:
+	case data_models.TimerTaskTypeWorkerTaskBackoff, data_models.TimerTaskTypeDelayedStart:
+		// both are converted into the immediate tasks
 		return w.processTimerTaskWorkerTaskBackoff(task)
 	case data_models.TimerTaskTypeProcessTimeout:
 		return w.processTimerTaskProcessTimeout(task)
 	case data_models.TimerTaskTypeTimerCommand:
 		return w.processTimerTaskForTimerCommand(task)
+	case data_models.TimerTaskTypeDeleteProcessExecution:
+		return w.processTimerTaskForDeleteProcessExecution(task)
+	case data_models.TimerTaskTypeFireSchedule:
+		return w.processTimerTaskForSchedule(task)
 	default:
 		panic(fmt.Sprintf("unknown timer task type %v", task.TaskType))
 	}
@@ -183,3 +191,81 @@ func (w *timerTaskConcurrentProcessor) processTimerTaskForTimerCommand(
 
 	return nil
 }
+
+func (w *timerTaskConcurrentProcessor) processTimerTaskForDeleteProcessExecution(
+	task data_models.TimerTask,
+) error {
+	if w.archiver != nil {
+		err := w.archiveProcessExecution(task)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := w.store.ProcessTimerTaskForDeleteProcessExecution(w.rootCtx, data_models.ProcessTimerTaskRequest{
+		Task: task,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.HasNewImmediateTask {
+		// the visibility task to delete the visibility record
+		w.taskNotifier.NotifyNewImmediateTasks(xcapi.NotifyImmediateTasksRequest{
+			ShardId:            task.ShardId,
+			ProcessExecutionId: ptr.Any(task.ProcessExecutionId.String()),
+		})
+	}
+
+	return nil
+}
+
+func (w *timerTaskConcurrentProcessor) processTimerTaskForSchedule(
+	task data_models.TimerTask,
+) error {
+	resp, err := w.store.ProcessTimerTaskForSchedule(w.rootCtx, data_models.ProcessTimerTaskRequest{
+		Task: task,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the started process execution and the next firing are in the same shard as the schedule
+	if resp.HasNewImmediateTask {
+		notiReq := xcapi.NotifyImmediateTasksRequest{
+			ShardId: task.ShardId,
+		}
+		if resp.ProcessExecutionId != nil {
+			notiReq.ProcessExecutionId = ptr.Any(resp.ProcessExecutionId.String())
+		}
+		w.taskNotifier.NotifyNewImmediateTasks(notiReq)
+	}
+	if len(resp.NewTimerTaskFireTimestamps) > 0 {
+		w.taskNotifier.NotifyNewTimerTasks(xcapi.NotifyTimerTasksRequest{
+			ShardId:        task.ShardId,
+			FireTimestamps: resp.NewTimerTaskFireTimestamps,
+		})
+	}
+
+	return nil
+}
+
+// archiveProcessExecution archives the closed process execution before deleting it.
+// It's safe to retry as the archive is overwritten.
+func (w *timerTaskConcurrentProcessor) archiveProcessExecution(
+	task data_models.TimerTask,
+) error {
+	resp, err := w.store.GetProcessExecutionForArchival(w.rootCtx, data_models.GetProcessExecutionForArchivalRequest{
+		ProcessExecutionId: task.ProcessExecutionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// skip if already deleted, or still running which won't be deleted either
+	if resp.NotExists || resp.Record.Status == xcapi.RUNNING {
+		return nil
+	}
+
+	return w.archiver.Archive(w.rootCtx, resp.Record)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type requestExtContextKey struct{}
+
+// withRequestExt attaches the typed extension of the worker API request, e.g. data_models.AsyncStateExecuteRequestExtJson,
+// for the fields that are not yet defined in the xcapi IDL
+func withRequestExt(ctx context.Context, ext interface{}) context.Context {
+	return context.WithValue(ctx, requestExtContextKey{}, ext)
+}
+
+// requestExtTransport encodes the extension attached by withRequestExt into the same JSON body of the xcapi request,
+// because the generated xcapi client only encodes the fields defined in the IDL
+type requestExtTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestExtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ext := req.Context().Value(requestExtContextKey{})
+	if ext == nil || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	var merged map[string]interface{}
+	err = json.Unmarshal(body, &merged)
+	if err != nil {
+		return nil, err
+	}
+	extBytes, err := json.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(extBytes, &fields)
+	if err != nil {
+		return nil, err
+	}
+	mergeJSONObjects(merged, fields)
+	body, err = json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return t.base.RoundTrip(req)
+}
+
+// mergeJSONObjects merges the fields into the object recursively, the fields take precedence
+func mergeJSONObjects(object map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range fields {
+		existing, ok1 := object[key].(map[string]interface{})
+		nested, ok2 := value.(map[string]interface{})
+		if ok1 && ok2 {
+			mergeJSONObjects(existing, nested)
+			continue
+		}
+		object[key] = value
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/jmoiron/sqlx/types"
 	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
 )
 
 /**
@@ -50,6 +51,9 @@ type (
 		StateExecutionLocalQueues  types.JSONText
 
 		GracefulCompleteRequested bool
+		Paused                    bool
+		// CloseResult is the output or failure of the closed process
+		CloseResult types.JSONText
 	}
 
 	ProcessExecutionRow struct {
@@ -72,6 +76,31 @@ type (
 		TimeoutSeconds            int32
 		Info                      types.JSONText
 		GracefulCompleteRequested bool
+		Paused                    bool
+		CloseResult               types.JSONText
+	}
+
+	// ProcessExecutionRunRow is the summary of a process execution of a processId
+	ProcessExecutionRunRow struct {
+		ProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ProcessExecutionIdString string
+
+		Status      data_models.ProcessExecutionStatus
+		StartTime   time.Time
+		ProcessType string
+		// CloseTimestamp is nil if the process execution is running
+		CloseTimestamp *int64
+	}
+
+	ProcessExecutionRunsSelectFilter struct {
+		Namespace string
+		ProcessId string
+		// LastStartTime and LastProcessExecutionIdString are the last process execution of the previous page,
+		// only set if it's not the first page
+		LastStartTime                *time.Time
+		LastProcessExecutionIdString string
+		PageSize                     int32
 	}
 
 	AsyncStateExecutionSelectFilter struct {
@@ -98,6 +127,10 @@ type (
 
 		LastFailure types.JSONText
 
+		// Input and Info are only selected, not updated
+		Input types.JSONText
+		Info  types.JSONText
+
 		PreviousVersion int32 // for conditional check
 	}
 
@@ -134,6 +167,9 @@ type (
 
 		Input types.JSONText
 		Info  types.JSONText
+
+		// ProcessExecutionPaused is only selected by SelectAsyncStateExecution
+		ProcessExecutionPaused bool
 	}
 
 	ImmediateTaskRowForInsert struct {
@@ -260,6 +296,130 @@ type (
 		ProcessExecutionIdString string
 		Key                      string
 		Value                    types.JSONText
+		// Version is increased by the database on every write, it's ignored by InsertLocalAttribute and UpsertLocalAttribute
+		Version int64
+	}
+
+	HistoryEventRow struct {
+		ProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ProcessExecutionIdString string
+		EventId                  int32
+
+		EventType       data_models.HistoryEventType
+		StateId         string
+		StateIdSequence int32
+		CreateTime      time.Time
+
+		Info types.JSONText
+	}
+
+	BatchOperationRow struct {
+		Namespace        string
+		BatchOperationId string
+
+		Status         data_models.BatchOperationStatus
+		Info           types.JSONText
+		Rps            int32
+		NextPageToken  string
+		SucceededCount int32
+		FailedCount    int32
+		SkippedCount   int32
+		ErrorMessage   string
+
+		Owner           string
+		LeaseExpireTime time.Time
+
+		PreviousVersion int32 // for conditional check
+		CreateTime      time.Time
+		LastUpdateTime  time.Time
+	}
+
+	ScheduleRow struct {
+		Namespace  string
+		ScheduleId string
+
+		ShardId                 int32
+		Paused                  bool
+		Info                    types.JSONText
+		NextFireTimeUnixSeconds int64
+		LastFireTimeUnixSeconds int64
+		LastProcessId           string
+		FiredCount              int32
+		SkippedCount            int32
+
+		PreviousVersion int32 // for conditional check
+		CreateTime      time.Time
+		LastUpdateTime  time.Time
+	}
+
+	ChildProcessRow struct {
+		ParentProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ParentProcessExecutionIdString string
+		ChildProcessExecutionId        uuid.UUID
+		// See the top of the file for why we need this field
+		ChildProcessExecutionIdString string
+
+		ProcessId string
+		// the state execution of the parent process that started the child process
+		StateId         string
+		StateIdSequence int32
+		StartTime       time.Time
+	}
+
+	ProcessCompletionWaiterRow struct {
+		// the process to wait for
+		Namespace string
+		ProcessId string
+
+		WaiterProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		WaiterProcessExecutionIdString string
+		WaiterStateId                  string
+		WaiterStateIdSequence          int32
+		// the index of the process completion command of the waiting state execution
+		CommandIndex int32
+	}
+
+	// ParkedImmediateTaskRow is a waitUntil/execute task of a paused process execution,
+	// which is inserted back to the immediate tasks when the process execution is resumed
+	ParkedImmediateTaskRow struct {
+		ProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ProcessExecutionIdString string
+		StateId                  string
+		StateIdSequence          int32
+
+		TaskType data_models.ImmediateTaskType
+		Info     types.JSONText
+	}
+
+	ProcessRequestRow struct {
+		Namespace string
+		ProcessId string
+		RequestId string
+
+		RequestType        data_models.ProcessRequestType
+		ProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ProcessExecutionIdString string
+		Response                 types.JSONText
+		CreateTime               time.Time
+	}
+
+	CompletionCallbackRow struct {
+		ProcessExecutionId uuid.UUID
+		// See the top of the file for why we need this field
+		ProcessExecutionIdString string
+		// the index of the url in the StartProcess request
+		CallbackIndex int32
+
+		Url               string
+		RetryPolicy       types.JSONText
+		Status            data_models.CompletionCallbackStatus
+		CompletedAttempts int32
+		LastFailure       types.JSONText
 	}
 
 	ExecutionVisibilityRow struct {
@@ -270,6 +430,29 @@ type (
 		ProcessTypeName          string
 		Status                   data_models.ProcessExecutionStatus
 		StartTime                time.Time
-		CloseTime                time.Time
+		CloseTime                *time.Time // nil for the running process executions
+		DelayedStartTime         *time.Time // nil if the start is not delayed
+		SearchAttributes         types.JSONText
+		CloseRecord              types.JSONText // nil for the running process executions
+	}
+
+	ExecutionVisibilityQuery struct {
+		Namespace string
+		// Where is the optional validated filter expression
+		Where   visibilityquery.Expression
+		OrderBy visibilityquery.OrderBy
+
+		// LastProcessExecutionIdString and LastSortValue are from the last row of the previous page,
+		// LastProcessExecutionIdString is empty for the first page.
+		// LastSortValue is the text form of the OrderBy field, and unix seconds for the time fields.
+		LastProcessExecutionIdString string
+		LastSortValue                string
+		PageSize                     int32
+	}
+
+	ExecutionVisibilityCountRow struct {
+		Status          data_models.ProcessExecutionStatus
+		ProcessTypeName string
+		Count           int64
 	}
 )
@@ -5,19 +5,20 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/common/uuid"
 	"github.com/xcherryio/xcherry/persistence/data_models"
 	"strings"
-	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/xcherryio/xcherry/extensions"
 )
 
 const selectLatestExecutionQuery = `SELECT
-	le.process_execution_id, e.shard_id, e.status, e.start_time, e.timeout_seconds, e.history_event_id_sequence, e.state_execution_sequence_maps, e.info
+	le.process_execution_id, e.shard_id, e.status, e.start_time, e.timeout_seconds, e.history_event_id_sequence, e.state_execution_sequence_maps, e.info,
+	e.paused, e.close_result
 	FROM xcherry_sys_latest_process_executions le
 	INNER JOIN xcherry_sys_process_executions e ON e.process_id = le.process_id AND e.id = le.process_execution_id
 	WHERE le.namespace = $1 AND le.process_id = $2`
@@ -34,7 +35,8 @@ func (d dbSession) SelectLatestProcessExecution(
 }
 
 const selectAsyncStateExecutionQuery = `SELECT 
-    status, wait_until_commands, wait_until_command_results, version as previous_version, info, input, last_failure
+    status, wait_until_commands, wait_until_command_results, version as previous_version, info, input, last_failure,
+    COALESCE((SELECT paused FROM xcherry_sys_process_executions WHERE id=$1), false) as process_execution_paused
 	FROM xcherry_sys_async_state_executions WHERE process_execution_id=$1 AND state_id=$2 AND state_id_sequence=$3`
 
 func (d dbSession) SelectAsyncStateExecution(
@@ -49,6 +51,62 @@ func (d dbSession) SelectAsyncStateExecution(
 	return &row, err
 }
 
+func (d dbSession) SelectProcessExecution(
+	ctx context.Context, processExecutionId uuid.UUID,
+) (*extensions.ProcessExecutionRow, error) {
+	var row extensions.ProcessExecutionRow
+	err := d.db.GetContext(ctx, &row, selectProcessExecutionQuery, processExecutionId.String())
+	row.StartTime = FromPostgresDateTime(row.StartTime)
+	return &row, err
+}
+
+// The ids are time-ordered UUIDs(version 7), so the process executions started at the same time
+// are ordered by the creation time of the ids, and then by the ids deterministically
+const selectProcessExecutionRunsQuery = `SELECT 
+    id as process_execution_id, status, start_time, info->>'processType' as process_type,
+	(close_result->>'closeTimestamp')::bigint as close_timestamp
+	FROM xcherry_sys_process_executions WHERE namespace=$1 AND process_id=$2
+	ORDER BY start_time, id LIMIT $3`
+
+const selectProcessExecutionRunsAfterQuery = `SELECT 
+    id as process_execution_id, status, start_time, info->>'processType' as process_type,
+	(close_result->>'closeTimestamp')::bigint as close_timestamp
+	FROM xcherry_sys_process_executions WHERE namespace=$1 AND process_id=$2 AND (start_time, id) > ($4, $5::uuid)
+	ORDER BY start_time, id LIMIT $3`
+
+func (d dbSession) SelectProcessExecutionRuns(
+	ctx context.Context, filter extensions.ProcessExecutionRunsSelectFilter,
+) ([]extensions.ProcessExecutionRunRow, error) {
+	var rows []extensions.ProcessExecutionRunRow
+	var err error
+	if filter.LastStartTime == nil {
+		err = d.db.SelectContext(ctx, &rows, selectProcessExecutionRunsQuery,
+			filter.Namespace, filter.ProcessId, filter.PageSize)
+	} else {
+		err = d.db.SelectContext(ctx, &rows, selectProcessExecutionRunsAfterQuery,
+			filter.Namespace, filter.ProcessId, filter.PageSize,
+			ToPostgresDateTime(*filter.LastStartTime), filter.LastProcessExecutionIdString)
+	}
+	for i := range rows {
+		rows[i].StartTime = FromPostgresDateTime(rows[i].StartTime)
+	}
+	return rows, err
+}
+
+const selectAsyncStateExecutionsQuery = `SELECT 
+    process_execution_id, state_id, state_id_sequence, status, wait_until_commands, wait_until_command_results, 
+    version as previous_version, info, input, last_failure
+	FROM xcherry_sys_async_state_executions WHERE process_execution_id=$1
+	ORDER BY state_id, state_id_sequence`
+
+func (d dbSession) SelectAsyncStateExecutions(
+	ctx context.Context, processExecutionId uuid.UUID,
+) ([]extensions.AsyncStateExecutionRow, error) {
+	var rows []extensions.AsyncStateExecutionRow
+	err := d.db.SelectContext(ctx, &rows, selectAsyncStateExecutionsQuery, processExecutionId.String())
+	return rows, err
+}
+
 const batchSelectImmediateTasksQuery = `SELECT 
     shard_id, task_sequence, process_execution_id, state_id, state_id_sequence, task_type, info
 	FROM xcherry_sys_immediate_tasks WHERE shard_id = $1 AND task_sequence>= $2 ORDER BY task_sequence ASC LIMIT $3`
@@ -131,6 +189,20 @@ func (d dbSession) SelectLocalQueueMessages(
 	return rows, err
 }
 
+const selectAllLocalQueueMessagesQuery = `SELECT
+	process_execution_id, queue_name, dedup_id, payload
+	FROM xcherry_sys_local_queue_messages WHERE process_execution_id = $1
+	ORDER BY queue_name, dedup_id
+`
+
+func (d dbSession) SelectAllLocalQueueMessages(
+	ctx context.Context, processExecutionId uuid.UUID,
+) ([]extensions.LocalQueueMessageRow, error) {
+	var rows []extensions.LocalQueueMessageRow
+	err := d.db.SelectContext(ctx, &rows, selectAllLocalQueueMessagesQuery, processExecutionId.String())
+	return rows, err
+}
+
 func (d dbSession) SelectAppDatabaseTableByPK(
 	ctx context.Context, tableName string, primaryKeys [][]xcapi.AppDatabaseColumnValue, columns []string,
 ) ([]extensions.AppDatabaseTableRowSelect, error) {
@@ -188,7 +260,7 @@ func (d dbSession) SelectAppDatabaseTableByPK(
 }
 
 const selectLocalAttributesQuery = `SELECT
-process_execution_id, key, value
+process_execution_id, key, value, version
 FROM xcherry_sys_local_attributes WHERE process_execution_id = ? AND key IN (?)
 `
 
@@ -205,240 +277,258 @@ func (d dbSession) SelectLocalAttributes(
 	return rows, err
 }
 
-const insertProcessExecutionStartQuery = `INSERT INTO xcherry_sys_executions_visibility
-	(namespace, process_id, process_execution_id, process_type_name, status, start_time)
-	VALUES (:namespace, :process_id, :process_execution_id_string, :process_type_name, :status, :start_time)`
+const selectAllLocalAttributesQuery = `SELECT
+process_execution_id, key, value, version
+FROM xcherry_sys_local_attributes WHERE process_execution_id = $1
+ORDER BY key
+`
 
-func (d dbSession) InsertProcessExecutionStartForVisibility(
-	ctx context.Context, row extensions.ExecutionVisibilityRow,
-) error {
-	row.StartTime = ToPostgresDateTime(row.StartTime)
-	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
-	_, err := d.db.NamedExecContext(ctx, insertProcessExecutionStartQuery, row)
-	return err
+func (d dbSession) SelectAllLocalAttributes(
+	ctx context.Context, processExecutionId uuid.UUID,
+) ([]extensions.LocalAttributeRow, error) {
+	var rows []extensions.LocalAttributeRow
+	err := d.db.SelectContext(ctx, &rows, selectAllLocalAttributesQuery, processExecutionId.String())
+	return rows, err
 }
 
-const updateProcessExecutionStatusQuery = `UPDATE xcherry_sys_executions_visibility
-	SET status = :status, close_time = :close_time
-	WHERE namespace = :namespace AND process_execution_id = :process_execution_id_string
+const selectHistoryEventsQuery = `SELECT
+process_execution_id, event_id, event_type, state_id, state_id_sequence, create_time, info
+FROM xcherry_sys_process_execution_history_events WHERE process_execution_id = $1 AND event_id > $2
+ORDER BY event_id ASC LIMIT $3
 `
 
-func (d dbSession) UpdateProcessExecutionStatusForVisibility(
-	ctx context.Context, row extensions.ExecutionVisibilityRow,
-) error {
-	row.CloseTime = ToPostgresDateTime(row.CloseTime)
-	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
-	_, err := d.db.NamedExecContext(ctx, updateProcessExecutionStatusQuery, row)
+func (d dbSession) SelectHistoryEvents(
+	ctx context.Context, processExecutionId uuid.UUID, minEventIdExclusive int32, pageSize int32,
+) ([]extensions.HistoryEventRow, error) {
+	var rows []extensions.HistoryEventRow
+	err := d.db.SelectContext(ctx, &rows, selectHistoryEventsQuery, processExecutionId.String(), minEventIdExclusive, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].CreateTime = FromPostgresDateTime(rows[i].CreateTime)
+	}
+	return rows, nil
+}
+
+const insertBatchOperationQuery = `INSERT INTO xcherry_sys_batch_operations
+	(namespace, batch_operation_id, status, info, rps, next_page_token, lease_expire_time, create_time, last_update_time) VALUES
+	(:namespace, :batch_operation_id, :status, :info, :rps, :next_page_token, :lease_expire_time, :create_time, :last_update_time)`
+
+func (d dbSession) InsertBatchOperation(ctx context.Context, row extensions.BatchOperationRow) error {
+	row.LeaseExpireTime = ToPostgresDateTime(row.LeaseExpireTime)
+	row.CreateTime = ToPostgresDateTime(row.CreateTime)
+	row.LastUpdateTime = ToPostgresDateTime(row.LastUpdateTime)
+	_, err := d.db.NamedExecContext(ctx, insertBatchOperationQuery, row)
 	return err
 }
 
-const selectProcessExecutionsQuery = `SELECT * 
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND start_time >= $2 AND start_time <= $3 
-AND (process_execution_id > $4 OR start_time < $5)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $6
-`
+const selectBatchOperationColumns = `namespace, batch_operation_id, status, info, rps, next_page_token,
+	succeeded_count, failed_count, skipped_count, error_message, owner, lease_expire_time,
+	version as previous_version, create_time, last_update_time`
+
+const selectBatchOperationQuery = `SELECT ` + selectBatchOperationColumns + `
+	FROM xcherry_sys_batch_operations WHERE namespace=$1 AND batch_operation_id=$2`
+
+func (d dbSession) SelectBatchOperation(
+	ctx context.Context, namespace string, batchOperationId string,
+) (*extensions.BatchOperationRow, error) {
+	var row extensions.BatchOperationRow
+	err := d.db.GetContext(ctx, &row, selectBatchOperationQuery, namespace, batchOperationId)
+	fromPostgresBatchOperationRow(&row)
+	return &row, err
+}
+
+const selectBatchOperationsByStatusQuery = `SELECT ` + selectBatchOperationColumns + `
+	FROM xcherry_sys_batch_operations WHERE status=$1 ORDER BY create_time ASC LIMIT $2`
 
-func (d dbSession) SelectProcessExecutions(
-	ctx context.Context,
-	namespace string,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsQuery,
-		namespace,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
+func (d dbSession) SelectBatchOperationsByStatus(
+	ctx context.Context, status data_models.BatchOperationStatus, pageSize int32,
+) ([]extensions.BatchOperationRow, error) {
+	var rows []extensions.BatchOperationRow
+	err := d.db.SelectContext(ctx, &rows, selectBatchOperationsByStatusQuery, status, pageSize)
 	if err != nil {
 		return nil, err
 	}
-
+	for i := range rows {
+		fromPostgresBatchOperationRow(&rows[i])
+	}
 	return rows, nil
 }
 
-const selectProcessExecutionsByStatusQuery = `SELECT * 
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND status = $2 
-AND start_time >= $3 AND start_time <= $4 
-AND (process_execution_id > $5 OR start_time < $6)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $7
-`
-
-func (d dbSession) SelectProcessExecutionsByStatus(
-	ctx context.Context,
-	namespace string,
-	status data_models.ProcessExecutionStatus,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsByStatusQuery,
-		namespace,
-		status,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
+const updateBatchOperationQuery = `UPDATE xcherry_sys_batch_operations set
+version = :previous_version + 1,
+status = :status,
+next_page_token = :next_page_token,
+succeeded_count = :succeeded_count,
+failed_count = :failed_count,
+skipped_count = :skipped_count,
+error_message = :error_message,
+owner = :owner,
+lease_expire_time = :lease_expire_time,
+last_update_time = :last_update_time
+WHERE namespace=:namespace AND batch_operation_id=:batch_operation_id AND version = :previous_version`
+
+func (d dbSession) UpdateBatchOperation(ctx context.Context, row extensions.BatchOperationRow) error {
+	row.LeaseExpireTime = ToPostgresDateTime(row.LeaseExpireTime)
+	row.LastUpdateTime = ToPostgresDateTime(row.LastUpdateTime)
+	result, err := d.db.NamedExecContext(ctx, updateBatchOperationQuery, row)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	effected, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if effected != 1 {
+		return conditionalUpdateFailure
+	}
+	return nil
+}
 
-	return rows, nil
+func fromPostgresBatchOperationRow(row *extensions.BatchOperationRow) {
+	row.LeaseExpireTime = FromPostgresDateTime(row.LeaseExpireTime)
+	row.CreateTime = FromPostgresDateTime(row.CreateTime)
+	row.LastUpdateTime = FromPostgresDateTime(row.LastUpdateTime)
+}
+
+const selectScheduleColumns = `namespace, schedule_id, shard_id, paused, info, next_fire_time_unix_seconds,
+	last_fire_time_unix_seconds, last_process_id, fired_count, skipped_count,
+	version as previous_version, create_time, last_update_time`
+
+const selectScheduleQuery = `SELECT ` + selectScheduleColumns + `
+	FROM xcherry_sys_schedules WHERE namespace=$1 AND schedule_id=$2`
+
+func (d dbSession) SelectSchedule(
+	ctx context.Context, namespace string, scheduleId string,
+) (*extensions.ScheduleRow, error) {
+	var row extensions.ScheduleRow
+	err := d.db.GetContext(ctx, &row, selectScheduleQuery, namespace, scheduleId)
+	fromPostgresScheduleRow(&row)
+	return &row, err
 }
 
-const selectProcessExecutionsByTypeQuery = `SELECT * 
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND process_type_name = $2 
-AND start_time >= $3 AND start_time <= $4 
-AND (process_execution_id > $5 OR start_time < $6)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $7
+func fromPostgresScheduleRow(row *extensions.ScheduleRow) {
+	row.CreateTime = FromPostgresDateTime(row.CreateTime)
+	row.LastUpdateTime = FromPostgresDateTime(row.LastUpdateTime)
+}
+
+const selectChildProcessesQuery = `SELECT
+parent_process_execution_id, child_process_execution_id, process_id, state_id, state_id_sequence, start_time
+FROM xcherry_sys_child_processes WHERE parent_process_execution_id = $1
+ORDER BY start_time ASC
 `
 
-func (d dbSession) SelectProcessExecutionsByTypeQuery(
-	ctx context.Context,
-	namespace string,
-	processTypeName string,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsByTypeQuery,
-		namespace,
-		processTypeName,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
-	if err != nil {
-		return nil, err
+func (d dbSession) SelectChildProcesses(
+	ctx context.Context, parentProcessExecutionId uuid.UUID,
+) ([]extensions.ChildProcessRow, error) {
+	var rows []extensions.ChildProcessRow
+	err := d.db.SelectContext(ctx, &rows, selectChildProcessesQuery, parentProcessExecutionId.String())
+	for i := range rows {
+		rows[i].StartTime = FromPostgresDateTime(rows[i].StartTime)
 	}
+	return rows, err
+}
 
-	return rows, nil
+const selectCompletionCallbacksQuery = `SELECT
+process_execution_id, callback_index, url, retry_policy, status, completed_attempts, last_failure
+FROM xcherry_sys_completion_callbacks WHERE process_execution_id = $1
+ORDER BY callback_index ASC
+`
+
+func (d dbSession) SelectCompletionCallbacks(
+	ctx context.Context, processExecutionId uuid.UUID,
+) ([]extensions.CompletionCallbackRow, error) {
+	var rows []extensions.CompletionCallbackRow
+	err := d.db.SelectContext(ctx, &rows, selectCompletionCallbacksQuery, processExecutionId.String())
+	return rows, err
 }
 
-const selectProcessExecutionsByIdQuery = `SELECT *
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND process_id = $2 
-AND start_time >= $3 AND start_time <= $4 
-AND (process_execution_id > $5 OR start_time < $6)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $7`
-
-func (d dbSession) SelectProcessExecutionsById(
-	ctx context.Context,
-	namespace string,
-	processId string,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsByIdQuery,
-		namespace,
-		processId,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
-	if err != nil {
-		return nil, err
-	}
+const insertProcessExecutionStartQuery = `INSERT INTO xcherry_sys_executions_visibility
+	(namespace, process_id, process_execution_id, process_type_name, status, start_time, delayed_start_time, search_attributes)
+	VALUES (:namespace, :process_id, :process_execution_id_string, :process_type_name, :status, :start_time, :delayed_start_time, :search_attributes)`
 
-	return rows, nil
+func (d dbSession) InsertProcessExecutionStartForVisibility(
+	ctx context.Context, row extensions.ExecutionVisibilityRow,
+) error {
+	row.StartTime = ToPostgresDateTime(row.StartTime)
+	if row.DelayedStartTime != nil {
+		delayedStartTime := ToPostgresDateTime(*row.DelayedStartTime)
+		row.DelayedStartTime = &delayedStartTime
+	}
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	_, err := d.db.NamedExecContext(ctx, insertProcessExecutionStartQuery, row)
+	return err
 }
 
-const selectProcessExecutionsByStatusAndType = `SELECT *
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND status = $2 
-AND process_type_name = $3 
-AND start_time >= $4 AND start_time <= $5 
-AND (process_execution_id > $6 OR start_time < $7)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $8
+const updateProcessExecutionStatusQuery = `UPDATE xcherry_sys_executions_visibility
+	SET status = :status, close_time = :close_time, close_record = :close_record
+	WHERE namespace = :namespace AND process_execution_id = :process_execution_id_string
 `
 
-func (d dbSession) SelectProcessExecutionsByStatusAndType(
-	ctx context.Context,
-	namespace string,
-	status data_models.ProcessExecutionStatus,
-	processTypeName string,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsByStatusAndType,
-		namespace,
-		status,
-		processTypeName,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
+func (d dbSession) UpdateProcessExecutionStatusForVisibility(
+	ctx context.Context, row extensions.ExecutionVisibilityRow,
+) error {
+	closeTime := ToPostgresDateTime(*row.CloseTime)
+	row.CloseTime = &closeTime
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	result, err := d.db.NamedExecContext(ctx, updateProcessExecutionStatusQuery, row)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return checkVisibilityRecordUpdated(result, row.ProcessExecutionIdString)
+}
 
-	return rows, nil
+const upsertProcessExecutionSearchAttributesQuery = `UPDATE xcherry_sys_executions_visibility
+	SET search_attributes = jsonb_strip_nulls(search_attributes || :search_attributes)
+	WHERE namespace = :namespace AND process_execution_id = :process_execution_id_string
+`
+
+func (d dbSession) UpsertProcessExecutionSearchAttributesForVisibility(
+	ctx context.Context, row extensions.ExecutionVisibilityRow,
+) error {
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	result, err := d.db.NamedExecContext(ctx, upsertProcessExecutionSearchAttributesQuery, row)
+	if err != nil {
+		return err
+	}
+	return checkVisibilityRecordUpdated(result, row.ProcessExecutionIdString)
 }
 
-const selectProcessExecutionsByStatusAndIdQuery = `SELECT *
-FROM xcherry_sys_executions_visibility
-WHERE namespace = $1 
-AND status = $2 
-AND process_id = $3 
-AND start_time >= $4 AND start_time <= $5 
-AND (process_execution_id > $6 OR start_time < $7)
-ORDER BY start_time DESC, process_execution_id
-LIMIT $8`
-
-func (d dbSession) SelectProcessExecutionsByStatusAndId(
-	ctx context.Context,
-	namespace string,
-	status data_models.ProcessExecutionStatus,
-	processId string,
-	startTimeMinInclusive, startTimeMaxInclusive int64,
-	lastProcessExecutionIdString string,
-	lastStartTime int64,
-	pageSize int32,
-) ([]extensions.ExecutionVisibilityRow, error) {
-	var rows []extensions.ExecutionVisibilityRow
-	err := d.db.SelectContext(ctx, &rows, selectProcessExecutionsByStatusAndIdQuery,
-		namespace,
-		status,
-		processId,
-		time.Unix(startTimeMinInclusive, 0),
-		time.Unix(startTimeMaxInclusive, 0),
-		lastProcessExecutionIdString,
-		time.Unix(lastStartTime, 0),
-		pageSize)
+// checkVisibilityRecordUpdated returns a not found error if the visibility record doesn't exist, so that the visibility
+// task is retried instead of losing the update. The visibility tasks are processed concurrently, so the updates can run
+// before the start is recorded.
+func checkVisibilityRecordUpdated(result sql.Result, processExecutionIdString string) error {
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if affected != 1 {
+		return fmt.Errorf("the visibility record of process execution %v is not found: %w",
+			processExecutionIdString, sql.ErrNoRows)
 	}
+	return nil
+}
 
-	return rows, nil
+const deleteProcessExecutionForVisibilityQuery = `DELETE 
+	FROM xcherry_sys_executions_visibility WHERE namespace = $1 AND process_execution_id = $2`
+
+func (d dbSession) DeleteProcessExecutionForVisibility(
+	ctx context.Context, namespace string, processExecutionId uuid.UUID,
+) error {
+	_, err := d.db.ExecContext(ctx, deleteProcessExecutionForVisibilityQuery, namespace, processExecutionId.String())
+	return err
+}
+
+const selectProcessRequestQuery = `SELECT
+namespace, process_id, request_id, request_type, process_execution_id, response, create_time
+FROM xcherry_sys_process_requests WHERE namespace = $1 AND process_id = $2 AND request_id = $3`
+
+func (d dbSession) SelectProcessRequest(
+	ctx context.Context, namespace, processId, requestId string,
+) (*extensions.ProcessRequestRow, error) {
+	var row extensions.ProcessRequestRow
+	err := d.db.GetContext(ctx, &row, selectProcessRequestQuery, namespace, processId, requestId)
+	row.CreateTime = FromPostgresDateTime(row.CreateTime)
+	return &row, err
 }
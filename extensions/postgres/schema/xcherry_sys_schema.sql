@@ -18,10 +18,14 @@ CREATE TABLE xcherry_sys_process_executions(
     state_execution_sequence_maps jsonb NOT NULL , -- some maps from stateId and sequence number
     state_execution_local_queues jsonb, -- some maps to quickly consume received local queue messages
     graceful_complete_requested BOOLEAN NOT NULL DEFAULT false, -- if set to true, the process will be gracefully completed when there is no running state
+    paused BOOLEAN NOT NULL DEFAULT false, -- if set to true, the waitUntil/execute tasks are parked in xcherry_sys_parked_immediate_tasks instead of calling the worker
     info jsonb , -- workerURL, processType, etc
+    close_result jsonb, -- the output or failure of the closed process
     PRIMARY KEY (id)
 );
 
+CREATE INDEX process_executions_by_process_id ON xcherry_sys_process_executions (namespace, process_id, start_time);
+
 CREATE TABLE xcherry_sys_async_state_executions(
    process_execution_id uuid NOT NULL,
    state_id VARCHAR(255) NOT NULL,
@@ -41,7 +45,7 @@ CREATE TABLE xcherry_sys_immediate_tasks(
     shard_id INTEGER NOT NULL, -- for virtual sharding
     task_sequence bigserial,   
     --
-    task_type SMALLINT NOT NULL, -- 1: waitUntil 2: execute 3: localQueueMessage
+    task_type SMALLINT NOT NULL, -- 1: waitUntil 2: execute 3: localQueueMessage 4: visibility 5: processCompletionCommand 6: completionCallback
     process_execution_id uuid,
     -- if the `task_type` is localQueueMessage, the value of state_id is "".
     state_id VARCHAR(255), -- for looking up xcherry_sys_async_state_executions
@@ -50,6 +54,8 @@ CREATE TABLE xcherry_sys_immediate_tasks(
     -- the info represents various information depending on the `task_type`:
     -- if the `task_type` is waitUntil or execute, the value corresponds to the state execution information.
     -- if the `task_type` is localQueueMessage, the value corresponds to the message information.
+    -- if the `task_type` is processCompletionCommand, the value corresponds to the result of the command.
+    -- if the `task_type` is completionCallback, the value corresponds to the callback url and payload.
     info jsonb,
     PRIMARY KEY (shard_id, task_sequence)
 );
@@ -59,7 +65,7 @@ CREATE TABLE xcherry_sys_timer_tasks(
     fire_time_unix_seconds BIGINT NOT NULL, 
     task_sequence bigserial, -- to help ensure the PK uniqueness 
     --
-    task_type SMALLINT, -- 1: process timeout 2: user timer command, 3: worker_task_backoff
+    task_type SMALLINT, -- 1: process timeout 2: user timer command, 3: worker_task_backoff, 4: delete process execution, 5: fire schedule, 6: delayed start
     process_execution_id uuid, -- for looking up xcherry_sys_async_state_executions
     state_id VARCHAR(255), -- for looking up xcherry_sys_async_state_executions
     state_id_sequence INTEGER, -- for looking up xcherry_sys_async_state_executions
@@ -79,9 +85,120 @@ CREATE TABLE xcherry_sys_local_attributes(
     process_execution_id uuid NOT NULL,
     key VARCHAR(31) NOT NULL,
     value jsonb,
+    version BIGINT NOT NULL DEFAULT 1, -- increased on every write, for the compare-and-set of the SetLocalAttributes API
     PRIMARY KEY (process_execution_id, key)
 );
 
+CREATE TABLE xcherry_sys_process_execution_history_events(
+    process_execution_id uuid NOT NULL,
+    event_id INTEGER NOT NULL, -- allocated from xcherry_sys_process_executions.history_event_id_sequence
+    --
+    event_type SMALLINT NOT NULL, -- 1:process_started/2:state_scheduled/3:wait_until_completed/4:timer_fired/5:messages_received/6:messages_consumed/7:execute_completed/8:state_failed/9:rpc_decision_applied/10:process_closed/11:process_reset/12:child_process_started/13:waiting_process_closed/14:process_paused/15:process_resumed/16:local_attributes_updated
+    state_id VARCHAR(255), -- "" if the event is not about a state execution
+    state_id_sequence INTEGER, -- 0 if the event is not about a state execution
+    create_time TIMESTAMP NOT NULL,
+    info jsonb, -- the details depending on the `event_type`
+    PRIMARY KEY (process_execution_id, event_id)
+);
+
+CREATE TABLE xcherry_sys_batch_operations(
+    namespace VARCHAR(31) NOT NULL,
+    batch_operation_id VARCHAR(255) NOT NULL,
+    --
+    status SMALLINT NOT NULL, -- 1:running/2:completed/3:canceled/4:failed
+    info jsonb NOT NULL, -- the visibility filter and the action to apply
+    rps INTEGER NOT NULL, -- the max number of actions to apply per second
+    next_page_token VARCHAR(255) NOT NULL, -- the checkpoint of the visibility query, "" to start from the beginning
+    succeeded_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT NOT NULL DEFAULT '',
+    owner VARCHAR(255) NOT NULL DEFAULT '', -- the api service instance that is running the batch operation
+    lease_expire_time TIMESTAMP NOT NULL, -- other instances can take over the batch operation after the lease is expired
+    version INTEGER NOT NULL DEFAULT 1, -- for conditional update
+    create_time TIMESTAMP NOT NULL,
+    last_update_time TIMESTAMP NOT NULL,
+    PRIMARY KEY (namespace, batch_operation_id)
+);
+
+CREATE INDEX batch_operations_by_status ON xcherry_sys_batch_operations (status);
+
+CREATE TABLE xcherry_sys_schedules(
+    namespace VARCHAR(31) NOT NULL,
+    schedule_id VARCHAR(255) NOT NULL,
+    --
+    shard_id INTEGER NOT NULL, -- the shard of the timer tasks to fire the schedule
+    paused BOOLEAN NOT NULL DEFAULT false,
+    info jsonb NOT NULL, -- the spec, the start request template and the overlap policy
+    next_fire_time_unix_seconds BIGINT NOT NULL, -- 0 if the schedule will not fire, e.g. paused
+    last_fire_time_unix_seconds BIGINT NOT NULL DEFAULT 0,
+    last_process_id VARCHAR(255) NOT NULL DEFAULT '', -- the process started by the last firing, for the overlap policy
+    fired_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL DEFAULT 1, -- for conditional update, and for detecting the stale timer tasks
+    create_time TIMESTAMP NOT NULL,
+    last_update_time TIMESTAMP NOT NULL,
+    PRIMARY KEY (namespace, schedule_id)
+);
+
+CREATE TABLE xcherry_sys_child_processes(
+    parent_process_execution_id uuid NOT NULL,
+    child_process_execution_id uuid NOT NULL,
+    process_id VARCHAR(255) NOT NULL, -- the process id of the child process
+    state_id VARCHAR(255) NOT NULL, -- the state execution of the parent process that started the child process
+    state_id_sequence INTEGER NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    PRIMARY KEY (parent_process_execution_id, child_process_execution_id)
+);
+
+CREATE TABLE xcherry_sys_process_completion_waiters(
+    namespace VARCHAR(31) NOT NULL,
+    process_id VARCHAR(255) NOT NULL, -- the process to wait for
+    waiter_process_execution_id uuid NOT NULL,
+    waiter_state_id VARCHAR(255) NOT NULL,
+    waiter_state_id_sequence INTEGER NOT NULL,
+    command_index INTEGER NOT NULL, -- the index of the process completion command of the waiting state execution
+    PRIMARY KEY (namespace, process_id, waiter_process_execution_id, waiter_state_id, waiter_state_id_sequence, command_index)
+);
+
+CREATE INDEX process_completion_waiters_by_waiter ON xcherry_sys_process_completion_waiters (waiter_process_execution_id);
+
+CREATE TABLE xcherry_sys_parked_immediate_tasks(
+    process_execution_id uuid NOT NULL, -- the paused process execution
+    state_id VARCHAR(255) NOT NULL,
+    state_id_sequence INTEGER NOT NULL,
+    --
+    task_type SMALLINT NOT NULL, -- 1: waitUntil 2: execute
+    info jsonb, -- same as xcherry_sys_immediate_tasks.info
+    PRIMARY KEY (process_execution_id, state_id, state_id_sequence)
+);
+
+CREATE TABLE xcherry_sys_process_requests(
+    namespace VARCHAR(31) NOT NULL,
+    process_id VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL, -- the request id provided by the client to deduplicate the repeated requests
+    --
+    request_type SMALLINT NOT NULL, -- 1:start/2:stop/3:rpc
+    process_execution_id uuid NOT NULL, -- the process execution that the request is applied to
+    response jsonb, -- the response to return for the repeated requests, e.g. the rpc output
+    create_time TIMESTAMP NOT NULL,
+    PRIMARY KEY (namespace, process_id, request_id)
+);
+
+CREATE INDEX process_requests_by_process_execution ON xcherry_sys_process_requests (process_execution_id);
+
+CREATE TABLE xcherry_sys_completion_callbacks(
+    process_execution_id uuid NOT NULL,
+    callback_index INTEGER NOT NULL, -- the index of the url in the StartProcess request
+    --
+    url VARCHAR(2047) NOT NULL,
+    retry_policy jsonb, -- null for the default retry policy
+    status SMALLINT NOT NULL, -- 1:pending/2:delivered/3:failed
+    completed_attempts INTEGER NOT NULL DEFAULT 0,
+    last_failure jsonb, -- the failure of the last attempt, same as xcherry_sys_async_state_executions.last_failure
+    PRIMARY KEY (process_execution_id, callback_index)
+);
+
 CREATE TABLE xcherry_sys_executions_visibility (
     namespace VARCHAR(31) NOT NULL,
     process_id VARCHAR(255) NOT NULL,
@@ -90,6 +207,9 @@ CREATE TABLE xcherry_sys_executions_visibility (
     status SMALLINT, -- 0:undefined/1:running/2:completed/3:failed/4:timeout/5:terminated
     start_time TIMESTAMP NOT NULL,
     close_time TIMESTAMP NULL,
+    delayed_start_time TIMESTAMP NULL, -- the time to run the start state if the start is delayed
+    search_attributes jsonb NOT NULL DEFAULT '{}', -- the custom search attributes registered in the config
+    close_record jsonb NULL, -- the reason why the process execution is closed
     PRIMARY KEY (namespace, process_execution_id)
 );
 
@@ -102,3 +222,11 @@ CREATE INDEX by_process_id_start_time ON xcherry_sys_executions_visibility (name
 CREATE INDEX by_status_start_time ON xcherry_sys_executions_visibility (namespace, status, start_time DESC, process_execution_id);
 
 CREATE INDEX by_status_type_start_time ON xcherry_sys_executions_visibility (namespace, status, process_type_name, start_time DESC, process_execution_id);
+
+CREATE INDEX by_close_time ON xcherry_sys_executions_visibility (namespace, close_time DESC, process_execution_id);
+
+CREATE INDEX by_type_close_time ON xcherry_sys_executions_visibility (namespace, process_type_name, close_time DESC, process_execution_id);
+
+CREATE INDEX by_status_close_time ON xcherry_sys_executions_visibility (namespace, status, close_time DESC, process_execution_id);
+
+CREATE INDEX by_search_attributes ON xcherry_sys_executions_visibility USING GIN (search_attributes jsonb_path_ops);
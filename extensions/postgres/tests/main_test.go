@@ -17,9 +17,12 @@ import (
 	"github.com/xcherryio/xcherry/extensions/postgres/postgrestool"
 
 	"github.com/xcherryio/xcherry/persistence/process"
+	"github.com/xcherryio/xcherry/persistence/process/sqltest"
+	"github.com/xcherryio/xcherry/persistence/visibility"
 )
 
 var store persistence.ProcessStore
+var visibilityStore persistence.VisibilityStore
 
 func TestMain(m *testing.M) {
 	testDBName := fmt.Sprintf("test%v", time.Now().UnixNano())
@@ -47,7 +50,16 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	store, err = process.NewSQLProcessStore(*sqlConfig, log.NewDevelopmentLogger())
+	retention := config.RetentionConfig{
+		Namespaces: map[string]time.Duration{
+			sqltest.RetentionTestNamespace: sqltest.RetentionTestDuration,
+		},
+	}
+	store, err = process.NewSQLProcessStore(*sqlConfig, retention, log.NewDevelopmentLogger())
+	if err != nil {
+		panic(err)
+	}
+	visibilityStore, err = visibility.NewSqlVisibilityStore(*sqlConfig, log.NewDevelopmentLogger())
 	if err != nil {
 		panic(err)
 	}
@@ -64,3 +64,97 @@ func TestAppDatabase(t *testing.T) {
 	sqltest.CleanupEnv(assert.New(t), store)
 	sqltest.SQLAppDatabaseTest(t, assert.New(t), store)
 }
+
+func TestHistory(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLHistoryTest(t, assert.New(t), store)
+}
+
+func TestDescribeStateExecutions(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLDescribeStateExecutionsTest(t, assert.New(t), store)
+}
+
+func TestResetProcessExecution(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLResetProcessExecutionTest(t, assert.New(t), store)
+}
+
+func TestResetGracefulComplete(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLResetGracefulCompleteTest(t, assert.New(t), store)
+}
+
+func TestBatchOperation(t *testing.T) {
+	sqltest.SQLBatchOperationTest(t, assert.New(t), store)
+}
+
+func TestSearchAttributes(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLSearchAttributesTest(t, assert.New(t), store)
+}
+
+func TestRetention(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLRetentionTest(t, assert.New(t), store)
+}
+
+func TestPublishToLocalQueueWithStart(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLPublishToLocalQueueWithStartTest(t, assert.New(t), store)
+}
+
+func TestSchedule(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLScheduleTest(t, assert.New(t), store)
+}
+
+func TestDelayedStart(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLDelayedStartTest(t, assert.New(t), store)
+}
+
+func TestChildProcess(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLChildProcessTest(t, assert.New(t), store)
+}
+
+func TestPauseProcessExecution(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLPauseProcessExecutionTest(t, assert.New(t), store)
+}
+
+func TestProcessRequest(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLProcessRequestTest(t, assert.New(t), store)
+}
+
+func TestCompletionCallback(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLCompletionCallbackTest(t, assert.New(t), store)
+}
+
+func TestProcessCloseResult(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLProcessCloseResultTest(t, assert.New(t), store)
+}
+
+func TestLocalAttributes(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLLocalAttributesTest(t, assert.New(t), store)
+}
+
+func TestProcessExecutionRuns(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLProcessExecutionRunsTest(t, assert.New(t), store)
+}
+
+func TestContinueAsNew(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLContinueAsNewTest(t, assert.New(t), store)
+}
+
+func TestRpcClose(t *testing.T) {
+	sqltest.CleanupEnv(assert.New(t), store)
+	sqltest.SQLRpcCloseTest(t, assert.New(t), store)
+}
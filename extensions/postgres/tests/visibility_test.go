@@ -0,0 +1,23 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	visibilitysqltest "github.com/xcherryio/xcherry/persistence/visibility/sqltest"
+)
+
+func TestVisibilityRecordStatusWithoutVisibilityRecord(t *testing.T) {
+	visibilitysqltest.SQLRecordStatusWithoutVisibilityRecordTest(t, assert.New(t), store, visibilityStore)
+}
+
+func TestVisibilityCountProcessExecutions(t *testing.T) {
+	visibilitysqltest.SQLCountProcessExecutionsTest(t, assert.New(t), visibilityStore)
+}
+
+func TestVisibilityListByCloseTime(t *testing.T) {
+	visibilitysqltest.SQLListByCloseTimeTest(t, assert.New(t), visibilityStore)
+}
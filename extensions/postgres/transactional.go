@@ -9,6 +9,7 @@ import (
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/xcherryio/xcherry/common/uuid"
 	"github.com/xcherryio/xcherry/extensions"
 )
@@ -73,7 +74,9 @@ status = :status,
 history_event_id_sequence = :history_event_id_sequence,
 state_execution_sequence_maps = :state_execution_sequence_maps,
 state_execution_local_queues = :state_execution_local_queues,
-graceful_complete_requested = :graceful_complete_requested
+graceful_complete_requested = :graceful_complete_requested,
+paused = :paused,
+close_result = :close_result
 WHERE id=:process_execution_id_string
 `
 
@@ -94,7 +97,7 @@ func (d dbTx) InsertAsyncStateExecution(ctx context.Context, row extensions.Asyn
 }
 
 const selectAsyncStateExecutionForUpdateQuery = `SELECT 
-    status, version as previous_version, wait_until_commands, wait_until_command_results, last_failure
+    status, version as previous_version, wait_until_commands, wait_until_command_results, last_failure, input, info
 	FROM xcherry_sys_async_state_executions WHERE process_execution_id=$1 AND state_id=$2 AND state_id_sequence=$3 FOR UPDATE
 `
 
@@ -190,7 +193,7 @@ func (d dbTx) InsertImmediateTask(ctx context.Context, row extensions.ImmediateT
 
 const selectProcessExecutionForUpdateQuery = `SELECT 
     id as process_execution_id, shard_id, status, history_event_id_sequence, state_execution_sequence_maps, 
-    state_execution_local_queues, graceful_complete_requested
+    state_execution_local_queues, graceful_complete_requested, paused, close_result
 	FROM xcherry_sys_process_executions WHERE id=$1 FOR UPDATE`
 
 func (d dbTx) SelectProcessExecutionForUpdate(
@@ -202,8 +205,8 @@ func (d dbTx) SelectProcessExecutionForUpdate(
 }
 
 const selectProcessExecutionQuery = `SELECT 
-    id as process_execution_id, status, history_event_id_sequence, state_execution_sequence_maps, state_execution_local_queues, graceful_complete_requested,
-	namespace, process_id, start_time, timeout_seconds, info
+    id as process_execution_id, shard_id, status, history_event_id_sequence, state_execution_sequence_maps, state_execution_local_queues, graceful_complete_requested, paused,
+	namespace, process_id, start_time, timeout_seconds, info, close_result
 	FROM xcherry_sys_process_executions WHERE id=$1 `
 
 func (d dbTx) SelectProcessExecution(
@@ -350,7 +353,7 @@ func (d dbTx) InsertLocalAttribute(ctx context.Context, row extensions.LocalAttr
 const upsertLocalAttributeQuery = `INSERT INTO xcherry_sys_local_attributes 
 (process_execution_id, key, value)
 VALUES (:process_execution_id_string, :key, :value)
-ON CONFLICT (process_execution_id, key) DO UPDATE SET value = :value
+ON CONFLICT (process_execution_id, key) DO UPDATE SET value = :value, version = xcherry_sys_local_attributes.version + 1
 `
 
 func (d dbTx) UpsertLocalAttribute(ctx context.Context, row extensions.LocalAttributeRow) error {
@@ -358,3 +361,253 @@ func (d dbTx) UpsertLocalAttribute(ctx context.Context, row extensions.LocalAttr
 	_, err := d.tx.NamedExecContext(ctx, upsertLocalAttributeQuery, row)
 	return err
 }
+
+const copyLocalAttributesQuery = `INSERT INTO xcherry_sys_local_attributes 
+(process_execution_id, key, value)
+SELECT CAST(? AS UUID), key, value FROM xcherry_sys_local_attributes WHERE process_execution_id = ? AND key IN (?)
+`
+
+func (d dbTx) CopyLocalAttributes(
+	ctx context.Context, fromProcessExecutionId uuid.UUID, toProcessExecutionId uuid.UUID, keys []string,
+) error {
+	query, args, err := sqlx.In(copyLocalAttributesQuery, toProcessExecutionId.String(), fromProcessExecutionId.String(), keys)
+	if err != nil {
+		return err
+	}
+	query = d.tx.Rebind(query)
+	_, err = d.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+const insertHistoryEventQuery = `INSERT INTO xcherry_sys_process_execution_history_events
+	(process_execution_id, event_id, event_type, state_id, state_id_sequence, create_time, info)
+	VALUES (:process_execution_id_string, :event_id, :event_type, :state_id, :state_id_sequence, :create_time, :info)
+`
+
+func (d dbTx) InsertHistoryEvent(ctx context.Context, row extensions.HistoryEventRow) error {
+	row.CreateTime = ToPostgresDateTime(row.CreateTime)
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	_, err := d.tx.NamedExecContext(ctx, insertHistoryEventQuery, row)
+	return err
+}
+
+const deleteLatestProcessExecutionQuery = `DELETE 
+	FROM xcherry_sys_latest_process_executions WHERE namespace = $1 AND process_id = $2 AND process_execution_id = $3`
+
+func (d dbTx) DeleteLatestProcessExecution(
+	ctx context.Context, namespace string, processId string, processExecutionId uuid.UUID,
+) error {
+	_, err := d.tx.ExecContext(ctx, deleteLatestProcessExecutionQuery, namespace, processId, processExecutionId.String())
+	return err
+}
+
+const deleteProcessExecutionQuery = `DELETE FROM xcherry_sys_process_executions WHERE id = $1`
+
+func (d dbTx) DeleteProcessExecution(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteProcessExecutionQuery, processExecutionId.String())
+	return err
+}
+
+const deleteAsyncStateExecutionsQuery = `DELETE FROM xcherry_sys_async_state_executions WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteAsyncStateExecutions(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteAsyncStateExecutionsQuery, processExecutionId.String())
+	return err
+}
+
+const deleteLocalQueueMessagesQuery = `DELETE FROM xcherry_sys_local_queue_messages WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteLocalQueueMessages(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteLocalQueueMessagesQuery, processExecutionId.String())
+	return err
+}
+
+const deleteLocalAttributesQuery = `DELETE FROM xcherry_sys_local_attributes WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteLocalAttributes(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteLocalAttributesQuery, processExecutionId.String())
+	return err
+}
+
+const deleteHistoryEventsQuery = `DELETE FROM xcherry_sys_process_execution_history_events WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteHistoryEvents(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteHistoryEventsQuery, processExecutionId.String())
+	return err
+}
+
+const insertScheduleQuery = `INSERT INTO xcherry_sys_schedules
+	(namespace, schedule_id, shard_id, paused, info, next_fire_time_unix_seconds, create_time, last_update_time) VALUES
+	(:namespace, :schedule_id, :shard_id, :paused, :info, :next_fire_time_unix_seconds, :create_time, :last_update_time)`
+
+func (d dbTx) InsertSchedule(ctx context.Context, row extensions.ScheduleRow) error {
+	row.CreateTime = ToPostgresDateTime(row.CreateTime)
+	row.LastUpdateTime = ToPostgresDateTime(row.LastUpdateTime)
+	_, err := d.tx.NamedExecContext(ctx, insertScheduleQuery, row)
+	return err
+}
+
+const selectScheduleForUpdateQuery = `SELECT ` + selectScheduleColumns + `
+	FROM xcherry_sys_schedules WHERE namespace=$1 AND schedule_id=$2 FOR UPDATE`
+
+func (d dbTx) SelectScheduleForUpdate(
+	ctx context.Context, namespace string, scheduleId string,
+) (*extensions.ScheduleRow, error) {
+	var row extensions.ScheduleRow
+	err := d.tx.GetContext(ctx, &row, selectScheduleForUpdateQuery, namespace, scheduleId)
+	fromPostgresScheduleRow(&row)
+	return &row, err
+}
+
+const updateScheduleQuery = `UPDATE xcherry_sys_schedules set
+version = :previous_version + 1,
+paused = :paused,
+info = :info,
+next_fire_time_unix_seconds = :next_fire_time_unix_seconds,
+last_fire_time_unix_seconds = :last_fire_time_unix_seconds,
+last_process_id = :last_process_id,
+fired_count = :fired_count,
+skipped_count = :skipped_count,
+last_update_time = :last_update_time
+WHERE namespace=:namespace AND schedule_id=:schedule_id AND version = :previous_version`
+
+func (d dbTx) UpdateSchedule(ctx context.Context, row extensions.ScheduleRow) error {
+	row.LastUpdateTime = ToPostgresDateTime(row.LastUpdateTime)
+	result, err := d.tx.NamedExecContext(ctx, updateScheduleQuery, row)
+	if err != nil {
+		return err
+	}
+	effected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if effected != 1 {
+		return conditionalUpdateFailure
+	}
+	return nil
+}
+
+const deleteScheduleQuery = `DELETE FROM xcherry_sys_schedules WHERE namespace = $1 AND schedule_id = $2`
+
+func (d dbTx) DeleteSchedule(ctx context.Context, namespace string, scheduleId string) error {
+	_, err := d.tx.ExecContext(ctx, deleteScheduleQuery, namespace, scheduleId)
+	return err
+}
+
+const insertChildProcessQuery = `INSERT INTO xcherry_sys_child_processes
+	(parent_process_execution_id, child_process_execution_id, process_id, state_id, state_id_sequence, start_time) VALUES
+	(:parent_process_execution_id_string, :child_process_execution_id_string, :process_id, :state_id, :state_id_sequence, :start_time)`
+
+func (d dbTx) InsertChildProcess(ctx context.Context, row extensions.ChildProcessRow) error {
+	row.ParentProcessExecutionIdString = row.ParentProcessExecutionId.String()
+	row.ChildProcessExecutionIdString = row.ChildProcessExecutionId.String()
+	row.StartTime = ToPostgresDateTime(row.StartTime)
+	_, err := d.tx.NamedExecContext(ctx, insertChildProcessQuery, row)
+	return err
+}
+
+const deleteChildProcessesQuery = `DELETE FROM xcherry_sys_child_processes WHERE parent_process_execution_id = $1`
+
+func (d dbTx) DeleteChildProcesses(ctx context.Context, parentProcessExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteChildProcessesQuery, parentProcessExecutionId.String())
+	return err
+}
+
+const insertProcessCompletionWaiterQuery = `INSERT INTO xcherry_sys_process_completion_waiters
+	(namespace, process_id, waiter_process_execution_id, waiter_state_id, waiter_state_id_sequence, command_index) VALUES
+	(:namespace, :process_id, :waiter_process_execution_id_string, :waiter_state_id, :waiter_state_id_sequence, :command_index)
+	ON CONFLICT DO NOTHING`
+
+func (d dbTx) InsertProcessCompletionWaiter(ctx context.Context, row extensions.ProcessCompletionWaiterRow) error {
+	row.WaiterProcessExecutionIdString = row.WaiterProcessExecutionId.String()
+	_, err := d.tx.NamedExecContext(ctx, insertProcessCompletionWaiterQuery, row)
+	return err
+}
+
+const deleteProcessCompletionWaitersQuery = `DELETE FROM xcherry_sys_process_completion_waiters
+	WHERE namespace = $1 AND process_id = $2
+	RETURNING namespace, process_id, waiter_process_execution_id, waiter_state_id, waiter_state_id_sequence, command_index`
+
+func (d dbTx) DeleteProcessCompletionWaiters(
+	ctx context.Context, namespace string, processId string,
+) ([]extensions.ProcessCompletionWaiterRow, error) {
+	var rows []extensions.ProcessCompletionWaiterRow
+	err := d.tx.SelectContext(ctx, &rows, deleteProcessCompletionWaitersQuery, namespace, processId)
+	return rows, err
+}
+
+const deleteProcessCompletionWaitersOfWaiterQuery = `DELETE FROM xcherry_sys_process_completion_waiters
+	WHERE waiter_process_execution_id = $1`
+
+func (d dbTx) DeleteProcessCompletionWaitersOfWaiter(ctx context.Context, waiterProcessExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteProcessCompletionWaitersOfWaiterQuery, waiterProcessExecutionId.String())
+	return err
+}
+
+const insertParkedImmediateTaskQuery = `INSERT INTO xcherry_sys_parked_immediate_tasks
+	(process_execution_id, state_id, state_id_sequence, task_type, info) VALUES
+	(:process_execution_id_string, :state_id, :state_id_sequence, :task_type, :info)
+	ON CONFLICT DO NOTHING`
+
+func (d dbTx) InsertParkedImmediateTask(ctx context.Context, row extensions.ParkedImmediateTaskRow) error {
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	_, err := d.tx.NamedExecContext(ctx, insertParkedImmediateTaskQuery, row)
+	return err
+}
+
+const deleteParkedImmediateTasksQuery = `DELETE FROM xcherry_sys_parked_immediate_tasks
+	WHERE process_execution_id = $1
+	RETURNING process_execution_id, state_id, state_id_sequence, task_type, info`
+
+func (d dbTx) DeleteParkedImmediateTasks(
+	ctx context.Context, processExecutionId uuid.UUID,
+) ([]extensions.ParkedImmediateTaskRow, error) {
+	var rows []extensions.ParkedImmediateTaskRow
+	err := d.tx.SelectContext(ctx, &rows, deleteParkedImmediateTasksQuery, processExecutionId.String())
+	return rows, err
+}
+
+const insertProcessRequestQuery = `INSERT INTO xcherry_sys_process_requests
+	(namespace, process_id, request_id, request_type, process_execution_id, response, create_time) VALUES
+	(:namespace, :process_id, :request_id, :request_type, :process_execution_id_string, :response, :create_time)`
+
+func (d dbTx) InsertProcessRequest(ctx context.Context, row extensions.ProcessRequestRow) error {
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	row.CreateTime = ToPostgresDateTime(row.CreateTime)
+	_, err := d.tx.NamedExecContext(ctx, insertProcessRequestQuery, row)
+	return err
+}
+
+const deleteProcessRequestsQuery = `DELETE FROM xcherry_sys_process_requests WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteProcessRequests(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteProcessRequestsQuery, processExecutionId.String())
+	return err
+}
+
+const insertCompletionCallbackQuery = `INSERT INTO xcherry_sys_completion_callbacks
+	(process_execution_id, callback_index, url, retry_policy, status, completed_attempts, last_failure) VALUES
+	(:process_execution_id_string, :callback_index, :url, :retry_policy, :status, :completed_attempts, :last_failure)`
+
+func (d dbTx) InsertCompletionCallback(ctx context.Context, row extensions.CompletionCallbackRow) error {
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	_, err := d.tx.NamedExecContext(ctx, insertCompletionCallbackQuery, row)
+	return err
+}
+
+const updateCompletionCallbackQuery = `UPDATE xcherry_sys_completion_callbacks SET
+	status = :status, completed_attempts = :completed_attempts, last_failure = :last_failure
+	WHERE process_execution_id = :process_execution_id_string AND callback_index = :callback_index`
+
+func (d dbTx) UpdateCompletionCallback(ctx context.Context, row extensions.CompletionCallbackRow) error {
+	row.ProcessExecutionIdString = row.ProcessExecutionId.String()
+	_, err := d.tx.NamedExecContext(ctx, updateCompletionCallbackQuery, row)
+	return err
+}
+
+const deleteCompletionCallbacksQuery = `DELETE FROM xcherry_sys_completion_callbacks WHERE process_execution_id = $1`
+
+func (d dbTx) DeleteCompletionCallbacks(ctx context.Context, processExecutionId uuid.UUID) error {
+	_, err := d.tx.ExecContext(ctx, deleteCompletionCallbacksQuery, processExecutionId.String())
+	return err
+}
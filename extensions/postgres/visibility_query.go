@@ -0,0 +1,250 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (d dbSession) SelectProcessExecutions(
+	ctx context.Context, query extensions.ExecutionVisibilityQuery,
+) ([]extensions.ExecutionVisibilityRow, error) {
+	b := &visibilityQueryBuilder{}
+	conditions, err := b.buildConditions(query.Namespace, query.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	sortExpr, cast, err := b.buildField(query.OrderBy.Field, query.OrderBy.SearchAttributeType)
+	if err != nil {
+		return nil, err
+	}
+	direction, comparator := "ASC", ">"
+	if query.OrderBy.Descending {
+		direction, comparator = "DESC", "<"
+	}
+	// the process executions without the value to sort by are skipped, to keep the pagination simple
+	if query.OrderBy.Field == visibilityquery.FieldCloseTime || query.OrderBy.Field == visibilityquery.FieldDelayedStartTime ||
+		query.OrderBy.SearchAttributeType != "" {
+		conditions = append(conditions, sortExpr+" IS NOT NULL")
+	}
+	if query.LastProcessExecutionIdString != "" {
+		lastSortValue, err := b.buildSortValue(query.OrderBy, query.LastSortValue)
+		if err != nil {
+			return nil, err
+		}
+		lastSortValue += cast
+		conditions = append(conditions, fmt.Sprintf("(%v %v %v OR (%v = %v AND process_execution_id > %v))",
+			sortExpr, comparator, lastSortValue, sortExpr, lastSortValue, b.arg(query.LastProcessExecutionIdString)))
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM xcherry_sys_executions_visibility WHERE %v ORDER BY %v %v, process_execution_id LIMIT %v",
+		strings.Join(conditions, " AND "), sortExpr, direction, b.arg(query.PageSize))
+
+	var rows []extensions.ExecutionVisibilityRow
+	err = d.db.SelectContext(ctx, &rows, sql, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (d dbSession) CountProcessExecutions(
+	ctx context.Context,
+	namespace string,
+	where visibilityquery.Expression,
+	groupBy data_models.ProcessExecutionCountGroupBy,
+) ([]extensions.ExecutionVisibilityCountRow, error) {
+	var groupByColumn string
+	switch groupBy {
+	case data_models.ProcessExecutionCountGroupByNone:
+	case data_models.ProcessExecutionCountGroupByStatus:
+		groupByColumn = "status"
+	case data_models.ProcessExecutionCountGroupByProcessType:
+		groupByColumn = "process_type_name"
+	default:
+		return nil, fmt.Errorf("unsupported group by %v", groupBy)
+	}
+
+	b := &visibilityQueryBuilder{}
+	conditions, err := b.buildConditions(namespace, where)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := "SELECT COUNT(*) AS count FROM xcherry_sys_executions_visibility WHERE " + strings.Join(conditions, " AND ")
+	if groupByColumn != "" {
+		sql = fmt.Sprintf("SELECT %v, COUNT(*) AS count FROM xcherry_sys_executions_visibility WHERE %v GROUP BY %v",
+			groupByColumn, strings.Join(conditions, " AND "), groupByColumn)
+	}
+
+	var rows []extensions.ExecutionVisibilityCountRow
+	err = d.db.SelectContext(ctx, &rows, sql, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// visibilityQueryBuilder translates the validated visibilityquery expressions into parameterized SQL
+type visibilityQueryBuilder struct {
+	args []interface{}
+}
+
+func (b *visibilityQueryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%v", len(b.args))
+}
+
+func (b *visibilityQueryBuilder) buildConditions(namespace string, where visibilityquery.Expression) ([]string, error) {
+	conditions := []string{"namespace = " + b.arg(namespace)}
+	if where != nil {
+		condition, err := b.buildExpression(where)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func (b *visibilityQueryBuilder) buildExpression(expr visibilityquery.Expression) (string, error) {
+	switch e := expr.(type) {
+	case *visibilityquery.LogicalExpression:
+		left, err := b.buildExpression(e.Left)
+		if err != nil {
+			return "", err
+		}
+		right, err := b.buildExpression(e.Right)
+		if err != nil {
+			return "", err
+		}
+		switch e.Operator {
+		case visibilityquery.LogicalOperatorAnd:
+			return fmt.Sprintf("(%v AND %v)", left, right), nil
+		case visibilityquery.LogicalOperatorOr:
+			return fmt.Sprintf("(%v OR %v)", left, right), nil
+		default:
+			return "", fmt.Errorf("unsupported logical operator %v", e.Operator)
+		}
+	case *visibilityquery.ComparisonExpression:
+		return b.buildComparison(e)
+	default:
+		return "", fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+func (b *visibilityQueryBuilder) buildComparison(e *visibilityquery.ComparisonExpression) (string, error) {
+	if e.SearchAttributeType != "" && e.Operator == visibilityquery.ComparisonOperatorEqual {
+		// the containment operator can use the GIN index on search_attributes
+		containment, err := json.Marshal(map[string]interface{}{e.Field: e.Values[0]})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("search_attributes @> %v::jsonb", b.arg(string(containment))), nil
+	}
+
+	field, cast, err := b.buildField(e.Field, e.SearchAttributeType)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, len(e.Values))
+	for i, value := range e.Values {
+		values[i] = b.arg(toVisibilityQueryArg(value)) + cast
+	}
+
+	switch e.Operator {
+	case visibilityquery.ComparisonOperatorIn:
+		return fmt.Sprintf("%v IN (%v)", field, strings.Join(values, ", ")), nil
+	case visibilityquery.ComparisonOperatorNotEqual:
+		return fmt.Sprintf("%v <> %v", field, values[0]), nil
+	case visibilityquery.ComparisonOperatorEqual,
+		visibilityquery.ComparisonOperatorLessThan, visibilityquery.ComparisonOperatorLessOrEqual,
+		visibilityquery.ComparisonOperatorGreaterThan, visibilityquery.ComparisonOperatorGreaterOrEqual:
+		return fmt.Sprintf("%v %v %v", field, e.Operator, values[0]), nil
+	default:
+		return "", fmt.Errorf("unsupported comparison operator %v", e.Operator)
+	}
+}
+
+// buildField returns the SQL expression of the field, and the cast for the values to compare with
+func (b *visibilityQueryBuilder) buildField(
+	field string, saType config.SearchAttributeType,
+) (string, string, error) {
+	switch field {
+	case visibilityquery.FieldProcessId:
+		return "process_id", "", nil
+	case visibilityquery.FieldProcessExecutionId:
+		return "process_execution_id", "::uuid", nil
+	case visibilityquery.FieldProcessType:
+		return "process_type_name", "", nil
+	case visibilityquery.FieldStatus:
+		return "status", "", nil
+	case visibilityquery.FieldStartTime:
+		return "start_time", "", nil
+	case visibilityquery.FieldCloseTime:
+		return "close_time", "", nil
+	case visibilityquery.FieldDelayedStartTime:
+		return "delayed_start_time", "", nil
+	case visibilityquery.FieldCloseReason:
+		return "(close_record->>'reasonType')", "", nil
+	}
+
+	var cast string
+	switch saType {
+	case config.SearchAttributeTypeString:
+		cast = ""
+	case config.SearchAttributeTypeInteger:
+		cast = "::bigint"
+	case config.SearchAttributeTypeDouble:
+		cast = "::double precision"
+	case config.SearchAttributeTypeBoolean:
+		cast = "::boolean"
+	default:
+		return "", "", fmt.Errorf("unknown field %v", field)
+	}
+	return fmt.Sprintf("(search_attributes->>%v)%v", b.arg(field), cast), cast, nil
+}
+
+// buildSortValue converts the text form of the last sort value into the query argument
+func (b *visibilityQueryBuilder) buildSortValue(orderBy visibilityquery.OrderBy, text string) (string, error) {
+	switch orderBy.Field {
+	case visibilityquery.FieldStartTime, visibilityquery.FieldCloseTime, visibilityquery.FieldDelayedStartTime:
+		unixSeconds, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid sort value %v of %v", text, orderBy.Field)
+		}
+		return b.arg(ToPostgresDateTime(time.Unix(unixSeconds, 0))), nil
+	case visibilityquery.FieldStatus:
+		status, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid sort value %v of %v", text, orderBy.Field)
+		}
+		return b.arg(status), nil
+	default:
+		return b.arg(text), nil
+	}
+}
+
+func toVisibilityQueryArg(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return ToPostgresDateTime(v)
+	case xcapi.ProcessStatus:
+		return data_models.ParseProcessExecutionStatus(string(v))
+	default:
+		return v
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+var testSearchAttributes = map[string]config.SearchAttributeType{
+	"customerId": config.SearchAttributeTypeString,
+	"amount":     config.SearchAttributeTypeInteger,
+	"score":      config.SearchAttributeTypeDouble,
+	"vip":        config.SearchAttributeTypeBoolean,
+}
+
+func TestVisibilityQueryBuilderConditions(t *testing.T) {
+	testCases := []struct {
+		query string
+		sql   string
+		args  []interface{}
+	}{
+		{
+			query: "",
+			sql:   "namespace = $1",
+			args:  []interface{}{"test-ns"},
+		},
+		{
+			query: "ProcessType = 'order'",
+			sql:   "namespace = $1 AND process_type_name = $2",
+			args:  []interface{}{"test-ns", "order"},
+		},
+		{
+			query: "ProcessType = 'order' AND (Status = 'FAILED' OR ProcessId != 'p1') OR ProcessType = 'refund'",
+			sql: "namespace = $1 AND ((process_type_name = $2 AND (status = $3 OR process_id <> $4))" +
+				" OR process_type_name = $5)",
+			args: []interface{}{"test-ns", "order", data_models.ProcessExecutionStatusFailed, "p1", "refund"},
+		},
+		{
+			query: "Status IN ('FAILED', 'TIMEOUT') AND ProcessId IN ('p1')",
+			sql:   "namespace = $1 AND (status IN ($2, $3) AND process_id IN ($4))",
+			args: []interface{}{"test-ns",
+				data_models.ProcessExecutionStatusFailed, data_models.ProcessExecutionStatusTimeout, "p1"},
+		},
+		{
+			query: "StartTime >= 1700000000 AND CloseTime < 1700000060",
+			sql:   "namespace = $1 AND (start_time >= $2 AND close_time < $3)",
+			args: []interface{}{"test-ns",
+				ToPostgresDateTime(time.Unix(1700000000, 0)), ToPostgresDateTime(time.Unix(1700000060, 0))},
+		},
+		{
+			query: "ProcessExecutionId = '018c0b3a-7f6e-7b5a-9c1d-2e3f4a5b6c7d'",
+			sql:   "namespace = $1 AND process_execution_id = $2::uuid",
+			args:  []interface{}{"test-ns", "018c0b3a-7f6e-7b5a-9c1d-2e3f4a5b6c7d"},
+		},
+		{
+			query: "CloseReason = 'CONTINUED_AS_NEW'",
+			sql:   "namespace = $1 AND (close_record->>'reasonType') = $2",
+			args:  []interface{}{"test-ns", "CONTINUED_AS_NEW"},
+		},
+		{
+			query: "customerId = 'c1' AND amount = 100 AND vip = true",
+			sql: "namespace = $1 AND ((search_attributes @> $2::jsonb AND search_attributes @> $3::jsonb)" +
+				" AND search_attributes @> $4::jsonb)",
+			args: []interface{}{"test-ns", `{"customerId":"c1"}`, `{"amount":100}`, `{"vip":true}`},
+		},
+		{
+			query: "customerId != 'c1' OR customerId IN ('c2', 'c3')",
+			sql: "namespace = $1 AND ((search_attributes->>$2) <> $3" +
+				" OR (search_attributes->>$4) IN ($5, $6))",
+			args: []interface{}{"test-ns", "customerId", "c1", "customerId", "c2", "c3"},
+		},
+		{
+			query: "amount > 100 AND score <= 1.5",
+			sql: "namespace = $1 AND ((search_attributes->>$2)::bigint > $3::bigint" +
+				" AND (search_attributes->>$4)::double precision <= $5::double precision)",
+			args: []interface{}{"test-ns", "amount", int64(100), "score", 1.5},
+		},
+		{
+			query: "vip != false AND amount IN (1, 2)",
+			sql: "namespace = $1 AND ((search_attributes->>$2)::boolean <> $3::boolean" +
+				" AND (search_attributes->>$4)::bigint IN ($5::bigint, $6::bigint))",
+			args: []interface{}{"test-ns", "vip", false, "amount", int64(1), int64(2)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := visibilityquery.Parse(testCase.query)
+		require.NoError(t, err, testCase.query)
+		require.NoError(t, visibilityquery.Validate(query, testSearchAttributes), testCase.query)
+
+		b := &visibilityQueryBuilder{}
+		conditions, err := b.buildConditions("test-ns", query.Where)
+		require.NoError(t, err, testCase.query)
+		assert.Equal(t, testCase.sql, strings.Join(conditions, " AND "), testCase.query)
+		assert.Equal(t, testCase.args, b.args, testCase.query)
+	}
+}
+
+func TestVisibilityQueryBuilderSortField(t *testing.T) {
+	query, err := visibilityquery.Parse("amount > 100 ORDER BY score DESC")
+	require.NoError(t, err)
+	require.NoError(t, visibilityquery.Validate(query, testSearchAttributes))
+
+	// the placeholders of the sort field continue after the ones of the conditions
+	b := &visibilityQueryBuilder{}
+	_, err = b.buildConditions("test-ns", query.Where)
+	require.NoError(t, err)
+	sortExpr, cast, err := b.buildField(query.OrderBy.Field, query.OrderBy.SearchAttributeType)
+	require.NoError(t, err)
+	assert.Equal(t, "(search_attributes->>$4)::double precision", sortExpr)
+	assert.Equal(t, "::double precision", cast)
+	assert.Equal(t, []interface{}{"test-ns", "amount", int64(100), "score"}, b.args)
+
+	sortValue, err := b.buildSortValue(visibilityquery.OrderBy{Field: visibilityquery.FieldCloseTime}, "1700000000")
+	require.NoError(t, err)
+	assert.Equal(t, "$5", sortValue)
+	assert.Equal(t, ToPostgresDateTime(time.Unix(1700000000, 0)), b.args[4])
+
+	_, err = b.buildSortValue(visibilityquery.OrderBy{Field: visibilityquery.FieldStartTime}, "not-a-number")
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/xcherryio/xcherry/persistence/data_models"
 
 	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
 	"github.com/xcherryio/xcherry/config"
 )
 
@@ -77,14 +78,67 @@ type transactionalCRUD interface {
 
 	InsertLocalAttribute(ctx context.Context, insert LocalAttributeRow) error
 	UpsertLocalAttribute(ctx context.Context, row LocalAttributeRow) error
+	// CopyLocalAttributes copies the local attributes of the keys to another process execution, with new versions
+	CopyLocalAttributes(
+		ctx context.Context, fromProcessExecutionId uuid.UUID, toProcessExecutionId uuid.UUID, keys []string,
+	) error
+
+	InsertHistoryEvent(ctx context.Context, row HistoryEventRow) error
+
+	// DeleteLatestProcessExecution deletes the row only if it still points to the processExecutionId
+	DeleteLatestProcessExecution(
+		ctx context.Context, namespace string, processId string, processExecutionId uuid.UUID,
+	) error
+	DeleteProcessExecution(ctx context.Context, processExecutionId uuid.UUID) error
+	DeleteAsyncStateExecutions(ctx context.Context, processExecutionId uuid.UUID) error
+	DeleteLocalQueueMessages(ctx context.Context, processExecutionId uuid.UUID) error
+	DeleteLocalAttributes(ctx context.Context, processExecutionId uuid.UUID) error
+	DeleteHistoryEvents(ctx context.Context, processExecutionId uuid.UUID) error
+
+	InsertSchedule(ctx context.Context, row ScheduleRow) error
+	SelectScheduleForUpdate(ctx context.Context, namespace string, scheduleId string) (*ScheduleRow, error)
+	// UpdateSchedule updates the schedule with conditional check on the PreviousVersion
+	UpdateSchedule(ctx context.Context, row ScheduleRow) error
+	DeleteSchedule(ctx context.Context, namespace string, scheduleId string) error
+
+	InsertChildProcess(ctx context.Context, row ChildProcessRow) error
+	DeleteChildProcesses(ctx context.Context, parentProcessExecutionId uuid.UUID) error
+
+	InsertProcessCompletionWaiter(ctx context.Context, row ProcessCompletionWaiterRow) error
+	// DeleteProcessCompletionWaiters deletes and returns the waiters of the processId
+	DeleteProcessCompletionWaiters(
+		ctx context.Context, namespace string, processId string,
+	) ([]ProcessCompletionWaiterRow, error)
+	// DeleteProcessCompletionWaitersOfWaiter deletes the waiters registered by the waiter process execution
+	DeleteProcessCompletionWaitersOfWaiter(ctx context.Context, waiterProcessExecutionId uuid.UUID) error
+
+	InsertParkedImmediateTask(ctx context.Context, row ParkedImmediateTaskRow) error
+	// DeleteParkedImmediateTasks deletes and returns the parked tasks of the process execution
+	DeleteParkedImmediateTasks(ctx context.Context, processExecutionId uuid.UUID) ([]ParkedImmediateTaskRow, error)
+
+	InsertProcessRequest(ctx context.Context, row ProcessRequestRow) error
+	DeleteProcessRequests(ctx context.Context, processExecutionId uuid.UUID) error
+
+	InsertCompletionCallback(ctx context.Context, row CompletionCallbackRow) error
+	// UpdateCompletionCallback updates the status, completed attempts and last failure of the callback
+	UpdateCompletionCallback(ctx context.Context, row CompletionCallbackRow) error
+	DeleteCompletionCallbacks(ctx context.Context, processExecutionId uuid.UUID) error
 }
 
 type nonTransactionalCRUD interface {
 	SelectLatestProcessExecution(ctx context.Context, namespace string, processId string) (*ProcessExecutionRow, error)
 
+	SelectProcessExecution(ctx context.Context, processExecutionId uuid.UUID) (*ProcessExecutionRow, error)
+	// SelectProcessExecutionRuns returns a page of the process executions of the processId,
+	// ordered by the start time, and then by the id for the same start time
+	SelectProcessExecutionRuns(
+		ctx context.Context, filter ProcessExecutionRunsSelectFilter,
+	) ([]ProcessExecutionRunRow, error)
+
 	SelectAsyncStateExecution(
 		ctx context.Context, filter AsyncStateExecutionSelectFilter,
 	) (*AsyncStateExecutionRow, error)
+	SelectAsyncStateExecutions(ctx context.Context, processExecutionId uuid.UUID) ([]AsyncStateExecutionRow, error)
 
 	BatchSelectImmediateTasks(
 		ctx context.Context, shardId int32, startSequenceInclusive int64, pageSize int32,
@@ -99,6 +153,7 @@ type nonTransactionalCRUD interface {
 	SelectLocalQueueMessages(
 		ctx context.Context, processExecutionId uuid.UUID, dedupIdStrings []string,
 	) ([]LocalQueueMessageRow, error)
+	SelectAllLocalQueueMessages(ctx context.Context, processExecutionId uuid.UUID) ([]LocalQueueMessageRow, error)
 
 	SelectAppDatabaseTableByPK(
 		ctx context.Context, tableName string, primaryKeys [][]xcapi.AppDatabaseColumnValue, columns []string,
@@ -107,75 +162,61 @@ type nonTransactionalCRUD interface {
 	SelectLocalAttributes(
 		ctx context.Context, processExecutionId uuid.UUID, keys []string,
 	) ([]LocalAttributeRow, error)
+	SelectAllLocalAttributes(ctx context.Context, processExecutionId uuid.UUID) ([]LocalAttributeRow, error)
+
+	SelectHistoryEvents(
+		ctx context.Context, processExecutionId uuid.UUID, minEventIdExclusive int32, pageSize int32,
+	) ([]HistoryEventRow, error)
+
+	InsertBatchOperation(ctx context.Context, row BatchOperationRow) error
+	SelectBatchOperation(ctx context.Context, namespace string, batchOperationId string) (*BatchOperationRow, error)
+	SelectBatchOperationsByStatus(
+		ctx context.Context, status data_models.BatchOperationStatus, pageSize int32,
+	) ([]BatchOperationRow, error)
+	// UpdateBatchOperation updates the batch operation with conditional check on the PreviousVersion
+	UpdateBatchOperation(ctx context.Context, row BatchOperationRow) error
+
+	SelectSchedule(ctx context.Context, namespace string, scheduleId string) (*ScheduleRow, error)
+
+	SelectChildProcesses(ctx context.Context, parentProcessExecutionId uuid.UUID) ([]ChildProcessRow, error)
+
+	SelectProcessRequest(ctx context.Context, namespace, processId, requestId string) (*ProcessRequestRow, error)
+
+	SelectCompletionCallbacks(ctx context.Context, processExecutionId uuid.UUID) ([]CompletionCallbackRow, error)
 
 	InsertProcessExecutionStartForVisibility(
 		ctx context.Context, row ExecutionVisibilityRow,
 	) error
 
+	// UpdateProcessExecutionStatusForVisibility returns a not found error if the visibility record doesn't exist
 	UpdateProcessExecutionStatusForVisibility(
 		ctx context.Context, row ExecutionVisibilityRow,
 	) error
 
-	SelectProcessExecutions(
-		ctx context.Context,
-		namespace string,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
-	) ([]ExecutionVisibilityRow, error)
-
-	SelectProcessExecutionsByStatus(
-		ctx context.Context,
-		namespace string,
-		status data_models.ProcessExecutionStatus,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
-	) ([]ExecutionVisibilityRow, error)
-
-	SelectProcessExecutionsByTypeQuery(
-		ctx context.Context,
-		namespace string,
-		processTypeName string,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
-	) ([]ExecutionVisibilityRow, error)
+	// UpsertProcessExecutionSearchAttributesForVisibility merges the SearchAttributes of the row
+	// into the existing ones, the search attributes with null values are removed.
+	// It returns a not found error if the visibility record doesn't exist.
+	UpsertProcessExecutionSearchAttributesForVisibility(
+		ctx context.Context, row ExecutionVisibilityRow,
+	) error
 
-	SelectProcessExecutionsById(
-		ctx context.Context,
-		namespace string,
-		processId string,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
-	) ([]ExecutionVisibilityRow, error)
+	DeleteProcessExecutionForVisibility(
+		ctx context.Context, namespace string, processExecutionId uuid.UUID,
+	) error
 
-	SelectProcessExecutionsByStatusAndType(
-		ctx context.Context,
-		namespace string,
-		status data_models.ProcessExecutionStatus,
-		processTypeName string,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
+	// SelectProcessExecutions selects a page of the process executions with the filter expression and ordering
+	SelectProcessExecutions(
+		ctx context.Context, query ExecutionVisibilityQuery,
 	) ([]ExecutionVisibilityRow, error)
 
-	SelectProcessExecutionsByStatusAndId(
+	// CountProcessExecutions counts the process executions with the optional filter expression,
+	// returns one row for each group, or a single row if groupBy is ProcessExecutionCountGroupByNone
+	CountProcessExecutions(
 		ctx context.Context,
 		namespace string,
-		status data_models.ProcessExecutionStatus,
-		processId string,
-		startTimeMinInclusive, startTimeMaxInclusive int64,
-		lastProcessExecutionIdString string,
-		lastStartTime int64,
-		pageSize int32,
-	) ([]ExecutionVisibilityRow, error)
+		where visibilityquery.Expression,
+		groupBy data_models.ProcessExecutionCountGroupBy,
+	) ([]ExecutionVisibilityCountRow, error)
 }
 
 type ErrorChecker interface {
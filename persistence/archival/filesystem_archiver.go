@@ -0,0 +1,126 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package archival
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xcherryio/xcherry/common/log"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+const archiveFileSuffix = ".json.gz"
+
+// NewArchiver returns the archiver of the config, or nil if the archival is disabled
+func NewArchiver(cfg *config.ArchivalConfig, logger log.Logger) (persistence.Archiver, error) {
+	if cfg == nil || cfg.Filesystem == nil {
+		return nil, nil
+	}
+	return NewFilesystemArchiver(*cfg.Filesystem, logger)
+}
+
+type filesystemArchiverImpl struct {
+	directory string
+	logger    log.Logger
+}
+
+// NewFilesystemArchiver returns an archiver that writes each process execution into
+// <directory>/<namespace>/<processExecutionId>.json.gz
+func NewFilesystemArchiver(cfg config.FilesystemArchivalConfig, logger log.Logger) (persistence.Archiver, error) {
+	err := os.MkdirAll(cfg.Directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &filesystemArchiverImpl{
+		directory: cfg.Directory,
+		logger:    logger,
+	}, nil
+}
+
+func (a *filesystemArchiverImpl) Archive(_ context.Context, record data_models.ArchivedProcessExecutionJson) error {
+	dir := a.getNamespaceDirectory(record.Namespace)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// write into a temp file first, so that a partially written file is never read
+	file, err := os.CreateTemp(dir, record.ProcessExecutionId+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+	defer func() {
+		// noop if the temp file is already renamed
+		_ = os.Remove(tmpPath)
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	err = json.NewEncoder(gzipWriter).Encode(record)
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	err = os.Rename(tmpPath, filepath.Join(dir, record.ProcessExecutionId+archiveFileSuffix))
+	if err != nil {
+		return err
+	}
+	a.logger.Debug("archived process execution", tag.Namespace(record.Namespace), tag.ProcessExecutionId(record.ProcessExecutionId))
+	return nil
+}
+
+func (a *filesystemArchiverImpl) GetArchivedProcessExecution(
+	_ context.Context, request data_models.GetArchivedProcessExecutionRequest,
+) (*data_models.GetArchivedProcessExecutionResponse, error) {
+	path := filepath.Join(a.getNamespaceDirectory(request.Namespace), request.ProcessExecutionId.String()+archiveFileSuffix)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &data_models.GetArchivedProcessExecutionResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	var record data_models.ArchivedProcessExecutionJson
+	err = json.NewDecoder(gzipReader).Decode(&record)
+	if err != nil {
+		return nil, err
+	}
+	return &data_models.GetArchivedProcessExecutionResponse{
+		Record: record,
+	}, nil
+}
+
+func (a *filesystemArchiverImpl) getNamespaceDirectory(namespace string) string {
+	// escape the namespace including the dots, so that it can't point to any other directory like ".."
+	return filepath.Join(a.directory, strings.ReplaceAll(url.PathEscape(namespace), ".", "%2E"))
+}
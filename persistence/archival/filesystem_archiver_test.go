@@ -0,0 +1,110 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package archival
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func TestFilesystemArchiver(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	archiver, err := NewFilesystemArchiver(config.FilesystemArchivalConfig{Directory: dir}, log.NewDevelopmentLogger())
+	require.NoError(t, err)
+
+	prcExeId := uuid.MustNewUUID()
+	record := data_models.ArchivedProcessExecutionJson{
+		Namespace:          "../test-ns",
+		ProcessId:          "test-prcid",
+		ProcessExecutionId: prcExeId.String(),
+		ProcessType:        "test-type",
+		Status:             xcapi.COMPLETED,
+		StartTimestamp:     1700000000,
+		CloseTimestamp:     1700000060,
+		CloseResult: data_models.ProcessCloseResultJson{
+			ProcessCloseRecordJson: data_models.ProcessCloseRecordJson{
+				ReasonType: data_models.ProcessCloseReasonTypeStateDecision,
+			},
+			Output:         xcapi.NewEncodedObject("test-encoding", "test-output"),
+			CloseTimestamp: 1700000060,
+		},
+		StateExecutions: []data_models.ArchivedStateExecutionJson{
+			{
+				StateId:         "state1",
+				StateIdSequence: 1,
+				Status:          data_models.StateExecutionStatusCompleted.String(),
+				Input:           *xcapi.NewEncodedObject("test-encoding", "test-data"),
+			},
+		},
+		LocalAttributes: []xcapi.KeyValue{
+			{Key: "key1", Value: *xcapi.NewEncodedObject("test-encoding", "value1")},
+		},
+		ConsumedMessages: []data_models.ArchivedLocalQueueMessageJson{
+			{QueueName: "q1", DedupId: uuid.MustNewUUID().String(), Payload: *xcapi.NewEncodedObject("", "")},
+		},
+		HistoryEvents: []data_models.ArchivedHistoryEventJson{
+			{
+				EventId:         1,
+				EventType:       data_models.HistoryEventTypeProcessExecutionStarted.String(),
+				CreateTimestamp: 1700000000,
+				Info: data_models.HistoryEventInfoJson{
+					ProcessType: ptr.Any("test-type"),
+				},
+			},
+		},
+	}
+
+	resp, err := archiver.GetArchivedProcessExecution(ctx, data_models.GetArchivedProcessExecutionRequest{
+		Namespace:          record.Namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.NotExists)
+
+	require.NoError(t, archiver.Archive(ctx, record))
+	// archiving again overwrites the existing record
+	require.NoError(t, archiver.Archive(ctx, record))
+
+	resp, err = archiver.GetArchivedProcessExecution(ctx, data_models.GetArchivedProcessExecutionRequest{
+		Namespace:          record.Namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.NotExists)
+	assert.Equal(t, record, resp.Record)
+
+	// the namespace is escaped into a single subdirectory, without leftover temp files
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	files, err := os.ReadDir(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	assert.Equal(t, prcExeId.String()+archiveFileSuffix, files[0].Name())
+
+	resp, err = archiver.GetArchivedProcessExecution(ctx, data_models.GetArchivedProcessExecutionRequest{
+		Namespace:          "test-ns",
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.NotExists)
+}
+
+func TestNewArchiverDisabled(t *testing.T) {
+	archiver, err := NewArchiver(nil, log.NewDevelopmentLogger())
+	require.NoError(t, err)
+	assert.Nil(t, archiver)
+}
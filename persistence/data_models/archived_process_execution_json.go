@@ -0,0 +1,56 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// ArchivedProcessExecutionJson is the self-contained record of a closed process execution in the archive
+type ArchivedProcessExecutionJson struct {
+	Namespace          string              `json:"namespace"`
+	ProcessId          string              `json:"processId"`
+	ProcessExecutionId string              `json:"processExecutionId"`
+	ProcessType        string              `json:"processType"`
+	WorkerUrl          string              `json:"workerUrl"`
+	Status             xcapi.ProcessStatus `json:"status"`
+	StartTimestamp     int64               `json:"startTimestamp"`
+	TimeoutSeconds     int32               `json:"timeoutSeconds"`
+	// CloseTimestamp is the unix seconds of when the process execution was closed
+	CloseTimestamp int64 `json:"closeTimestamp"`
+	// CloseResult is the output, or the reason and failure of the closed process execution
+	CloseResult ProcessCloseResultJson `json:"closeResult"`
+
+	StateExecutions []ArchivedStateExecutionJson `json:"stateExecutions"`
+	LocalAttributes []xcapi.KeyValue             `json:"localAttributes"`
+	// ConsumedMessages are the local queue messages that have been consumed by the state executions,
+	// ordered by the queue name and dedupId
+	ConsumedMessages []ArchivedLocalQueueMessageJson `json:"consumedMessages"`
+	// HistoryEvents are all the history events of the process execution, ordered by the eventId
+	HistoryEvents []ArchivedHistoryEventJson `json:"historyEvents"`
+}
+
+type ArchivedStateExecutionJson struct {
+	StateId         string                      `json:"stateId"`
+	StateIdSequence int32                       `json:"stateIdSequence"`
+	Status          string                      `json:"status"`
+	Input           xcapi.EncodedObject         `json:"input"`
+	LastFailure     *StateExecutionFailureJson  `json:"lastFailure,omitempty"`
+	Info            AsyncStateExecutionInfoJson `json:"info"`
+}
+
+type ArchivedHistoryEventJson struct {
+	EventId         int32                `json:"eventId"`
+	EventType       string               `json:"eventType"`
+	StateId         string               `json:"stateId,omitempty"`
+	StateIdSequence int32                `json:"stateIdSequence,omitempty"`
+	CreateTimestamp int64                `json:"createTimestamp"`
+	Info            HistoryEventInfoJson `json:"info"`
+}
+
+type ArchivedLocalQueueMessageJson struct {
+	QueueName string              `json:"queueName"`
+	DedupId   string              `json:"dedupId"`
+	Payload   xcapi.EncodedObject `json:"payload"`
+}
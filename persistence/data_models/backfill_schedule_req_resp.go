@@ -0,0 +1,32 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	// BackfillScheduleRequest starts the process executions for the fire times of the schedule in the range
+	// (StartTimestamp, EndTimestamp], regardless of the overlap policy and whether the schedule is paused.
+	// The fire times which have already started a process execution are skipped.
+	// It doesn't change the state of the schedule, e.g. the fired count.
+	BackfillScheduleRequest struct {
+		Namespace      string
+		ScheduleId     string
+		StartTimestamp int64
+		EndTimestamp   int64
+		MaxFirings     int
+	}
+
+	BackfillScheduleResponse struct {
+		NotExists                  bool
+		ShardId                    int32
+		StartedProcessExecutionIds []uuid.UUID
+		SkippedCount               int32
+		// the fire timestamps of the new timer tasks for the process timeouts
+		TimeoutTimestamps []int64
+		// NextStartTimestamp is the StartTimestamp to continue the backfill if it stops at the MaxFirings,
+		// zero if the backfill is completed
+		NextStartTimestamp int64
+	}
+)
@@ -0,0 +1,33 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+// BatchOperation is a durable background job that applies an action
+// to the process executions selected by a visibility filter
+type BatchOperation struct {
+	Namespace        string
+	BatchOperationId string
+
+	Status BatchOperationStatus
+	Info   BatchOperationInfoJson
+	Rps    int32
+
+	// the checkpoint of the visibility query, empty to start from the beginning
+	NextPageToken  string
+	SucceededCount int32
+	FailedCount    int32
+	SkippedCount   int32
+	ErrorMessage   string
+
+	// the api service instance that is running the batch operation
+	Owner string
+	// other instances can take over the batch operation after the lease is expired
+	LeaseExpireTimestamp int64
+
+	// for conditional update
+	Version int32
+
+	CreateTimestamp     int64
+	LastUpdateTimestamp int64
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// BatchOperationInfoJson is the static info of a batch operation,
+// which doesn't change after the batch operation is created
+type BatchOperationInfoJson struct {
+	Filter BatchOperationFilterJson `json:"filter"`
+	Action BatchOperationActionJson `json:"action"`
+}
+
+// BatchOperationFilterJson is the visibility filter to select the process executions
+type BatchOperationFilterJson struct {
+	ProcessType       *string              `json:"processType,omitempty"`
+	Status            *xcapi.ProcessStatus `json:"status,omitempty"`
+	EarliestStartTime int64                `json:"earliestStartTime"`
+	LatestStartTime   int64                `json:"latestStartTime"`
+}
+
+// BatchOperationActionJson is the action to apply on each selected process execution.
+// Only the fields related to the action type are set.
+type BatchOperationActionJson struct {
+	Type BatchOperationActionType `json:"type"`
+
+	// for STOP
+	StopType *xcapi.ProcessExecutionStopType `json:"stopType,omitempty"`
+
+	// for PUBLISH_TO_LOCAL_QUEUE
+	Messages []xcapi.LocalQueueMessage `json:"messages,omitempty"`
+
+	// for RPC
+	RpcName           *string              `json:"rpcName,omitempty"`
+	RpcInput          *xcapi.EncodedObject `json:"rpcInput,omitempty"`
+	RpcTimeoutSeconds *int32               `json:"rpcTimeoutSeconds,omitempty"`
+}
+
+func (j *BatchOperationInfoJson) ToBytes() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+func BytesToBatchOperationInfo(bytes []byte) (BatchOperationInfoJson, error) {
+	var obj BatchOperationInfoJson
+	err := json.Unmarshal(bytes, &obj)
+	return obj, err
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	CancelBatchOperationRequest struct {
+		Namespace        string
+		BatchOperationId string
+	}
+
+	CancelBatchOperationResponse struct {
+		NotExists     bool
+		AlreadyClosed bool
+	}
+)
@@ -0,0 +1,22 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+// ParentProcessJson is the state execution of the parent process that started a child process
+type ParentProcessJson struct {
+	ProcessId          string `json:"processId"`
+	ProcessExecutionId string `json:"processExecutionId"`
+	StateId            string `json:"stateId"`
+	StateIdSequence    int32  `json:"stateIdSequence"`
+}
+
+// ChildProcess is a child process started by a state decision of the parent process
+type ChildProcess struct {
+	ProcessId          string
+	ProcessExecutionId string
+	// the state execution of the parent process that started the child process
+	StateId         string
+	StateIdSequence int32
+	StartTimestamp  int64
+}
@@ -12,12 +12,15 @@ type CommandResultsJson struct {
 	// if value is true, the timer was fired. Otherwise, the timer was skipped.
 	TimerResults      map[int]bool                            `json:"timerResults"`
 	LocalQueueResults map[int][]xcapi.LocalQueueMessageResult `json:"localQueueResults"`
+	// the results of the completed process completion commands
+	ProcessCompletionResults map[int]ProcessCompletionResultJson `json:"processCompletionResults,omitempty"`
 }
 
 func NewCommandResultsJson() CommandResultsJson {
 	return CommandResultsJson{
-		TimerResults:      map[int]bool{},
-		LocalQueueResults: map[int][]xcapi.LocalQueueMessageResult{},
+		TimerResults:             map[int]bool{},
+		LocalQueueResults:        map[int][]xcapi.LocalQueueMessageResult{},
+		ProcessCompletionResults: map[int]ProcessCompletionResultJson{},
 	}
 }
 
@@ -28,5 +31,8 @@ func FromCommandResultsJsonToBytes(result CommandResultsJson) ([]byte, error) {
 func BytesToCommandResultsJson(bytes []byte) (CommandResultsJson, error) {
 	var result CommandResultsJson
 	err := json.Unmarshal(bytes, &result)
+	if result.ProcessCompletionResults == nil {
+		result.ProcessCompletionResults = map[int]ProcessCompletionResultJson{}
+	}
 	return result, err
 }
@@ -22,6 +22,13 @@ type (
 
 		UpdateLocalAttributes []xcapi.KeyValue
 
+		UpsertSearchAttributes map[string]interface{}
+
+		// StartChildProcesses are started in the namespace of the process, and linked to the state execution
+		StartChildProcesses []xcapi.ProcessExecutionStartRequest
+		// ContinueAsNew closes the process execution and starts a new execution with the same processId
+		ContinueAsNew *ContinueAsNew
+
 		TaskShardId  int32
 		TaskSequence int64
 	}
@@ -31,5 +38,8 @@ type (
 		ProcessStatus              ProcessExecutionStatus
 		FailedAtWritingAppDatabase bool
 		AppDatabaseWritingError    error
+		// FireTimestamps are the timeout timer tasks of the started child processes
+		// and the process execution started by continuing as new
+		FireTimestamps []int64
 	}
 )
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+type (
+	// CompletionCallbackPayloadJson is the body posted to the completion callback urls
+	CompletionCallbackPayloadJson struct {
+		Namespace          string              `json:"namespace"`
+		ProcessId          string              `json:"processId"`
+		ProcessExecutionId string              `json:"processExecutionId"`
+		ProcessType        string              `json:"processType"`
+		Status             xcapi.ProcessStatus `json:"status"`
+		CloseTimestamp     int64               `json:"closeTimestamp"`
+		// Failure is only set if the process is failed by a state execution failure
+		Failure *ProcessFailureJson `json:"failure,omitempty"`
+	}
+
+	// ProcessFailureJson is the state execution failure that failed the process
+	ProcessFailureJson struct {
+		StateExecutionId string                    `json:"stateExecutionId"`
+		Failure          StateExecutionFailureJson `json:"failure"`
+	}
+
+	// CompletionCallbackInfoJson is the info of the immediate task to deliver a completion callback
+	CompletionCallbackInfoJson struct {
+		CallbackIndex int32                         `json:"callbackIndex"`
+		Url           string                        `json:"url"`
+		RetryPolicy   *xcapi.RetryPolicy            `json:"retryPolicy,omitempty"`
+		Payload       CompletionCallbackPayloadJson `json:"payload"`
+	}
+
+	// CompletionCallback is the delivery status of a completion callback
+	CompletionCallback struct {
+		Url               string
+		Status            CompletionCallbackStatus
+		CompletedAttempts int32
+		// LastFailure is the failure of the last attempt, nil if there is no failed attempt
+		LastFailure *StateExecutionFailureJson
+	}
+)
+
+func BytesToRetryPolicy(bytes []byte) (*xcapi.RetryPolicy, error) {
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+	var obj xcapi.RetryPolicy
+	err := json.Unmarshal(bytes, &obj)
+	return &obj, err
+}
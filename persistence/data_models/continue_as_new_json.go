@@ -0,0 +1,19 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// ContinueAsNew closes the current process execution as completed, and starts a new execution
+// with the same processId in the same transaction
+type ContinueAsNew struct {
+	StartStateId     *string                 `json:"startStateId,omitempty"`
+	StartStateInput  *xcapi.EncodedObject    `json:"startStateInput,omitempty"`
+	StartStateConfig *xcapi.AsyncStateConfig `json:"startStateConfig,omitempty"`
+	// LocalAttributeKeys are the local attributes carried over to the new execution,
+	// the unconsumed local queue messages are always carried over
+	LocalAttributeKeys []string `json:"localAttributeKeys,omitempty"`
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/visibilityquery"
+
+type (
+	CountProcessExecutionsRequest struct {
+		Namespace string
+		// Where is optional, and must have been validated
+		Where   visibilityquery.Expression
+		GroupBy ProcessExecutionCountGroupBy
+	}
+
+	CountProcessExecutionsResponse struct {
+		// Count is the total count of all the groups
+		Count int64
+		// Groups is only returned when GroupBy is not ProcessExecutionCountGroupByNone
+		Groups []ProcessExecutionCountGroup
+	}
+
+	ProcessExecutionCountGroup struct {
+		// Status is only set when grouping by status
+		Status ProcessExecutionStatus
+		// ProcessType is only set when grouping by process type
+		ProcessType string
+		Count       int64
+	}
+)
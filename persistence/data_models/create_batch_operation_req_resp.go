@@ -0,0 +1,17 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	CreateBatchOperationRequest struct {
+		Namespace        string
+		BatchOperationId string
+		Info             BatchOperationInfoJson
+		Rps              int32
+	}
+
+	CreateBatchOperationResponse struct {
+		AlreadyExists bool
+	}
+)
@@ -0,0 +1,20 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	CreateScheduleRequest struct {
+		Namespace  string
+		ScheduleId string
+		ShardId    int32
+		Info       ScheduleInfoJson
+		Paused     bool
+	}
+
+	CreateScheduleResponse struct {
+		AlreadyExists bool
+		// zero if the schedule is paused, or will never fire
+		NextFireTimestamp int64
+	}
+)
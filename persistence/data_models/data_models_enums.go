@@ -92,6 +92,12 @@ const (
 	ImmediateTaskTypeExecute               ImmediateTaskType = 2
 	ImmediateTaskTypeNewLocalQueueMessages ImmediateTaskType = 3
 	ImmediateTaskTypeVisibility            ImmediateTaskType = 4
+	// ImmediateTaskTypeProcessCompletionCommand delivers the result of a process completion command
+	// to the waiting state execution, after the process is closed
+	ImmediateTaskTypeProcessCompletionCommand ImmediateTaskType = 5
+	// ImmediateTaskTypeCompletionCallback posts the completion payload to a callback URL
+	// registered by StartProcess, after the process is closed
+	ImmediateTaskTypeCompletionCallback ImmediateTaskType = 6
 )
 
 func (e ImmediateTaskType) String() string {
@@ -104,6 +110,10 @@ func (e ImmediateTaskType) String() string {
 		return "NewLocalQueueMessages"
 	case ImmediateTaskTypeVisibility:
 		return "Visibility"
+	case ImmediateTaskTypeProcessCompletionCommand:
+		return "ProcessCompletionCommand"
+	case ImmediateTaskTypeCompletionCallback:
+		return "CompletionCallback"
 	default:
 		panic("this is not supported")
 	}
@@ -115,4 +125,199 @@ const (
 	TimerTaskTypeProcessTimeout    TimerTaskType = 1
 	TimerTaskTypeTimerCommand      TimerTaskType = 2
 	TimerTaskTypeWorkerTaskBackoff TimerTaskType = 3
+	// TimerTaskTypeDeleteProcessExecution deletes all the data of a closed process execution after the retention
+	TimerTaskTypeDeleteProcessExecution TimerTaskType = 4
+	// TimerTaskTypeFireSchedule starts a new process execution of a schedule
+	TimerTaskTypeFireSchedule TimerTaskType = 5
+	// TimerTaskTypeDelayedStart is converted into the immediate task of the start state when the delayed start fires
+	TimerTaskTypeDelayedStart TimerTaskType = 6
 )
+
+type HistoryEventType int32
+
+const (
+	HistoryEventTypeProcessExecutionStarted    HistoryEventType = 1
+	HistoryEventTypeStateExecutionScheduled    HistoryEventType = 2
+	HistoryEventTypeStateWaitUntilCompleted    HistoryEventType = 3
+	HistoryEventTypeTimerFired                 HistoryEventType = 4
+	HistoryEventTypeLocalQueueMessagesReceived HistoryEventType = 5
+	HistoryEventTypeLocalQueueMessagesConsumed HistoryEventType = 6
+	HistoryEventTypeStateExecuteCompleted      HistoryEventType = 7
+	HistoryEventTypeStateExecutionFailed       HistoryEventType = 8
+	HistoryEventTypeRpcDecisionApplied         HistoryEventType = 9
+	HistoryEventTypeProcessExecutionClosed     HistoryEventType = 10
+	HistoryEventTypeProcessExecutionReset      HistoryEventType = 11
+	HistoryEventTypeChildProcessStarted        HistoryEventType = 12
+	HistoryEventTypeWaitingProcessClosed       HistoryEventType = 13
+	HistoryEventTypeProcessExecutionPaused     HistoryEventType = 14
+	HistoryEventTypeProcessExecutionResumed    HistoryEventType = 15
+	HistoryEventTypeLocalAttributesUpdated     HistoryEventType = 16
+)
+
+func (e HistoryEventType) String() string {
+	switch e {
+	case HistoryEventTypeProcessExecutionStarted:
+		return "ProcessExecutionStarted"
+	case HistoryEventTypeStateExecutionScheduled:
+		return "StateExecutionScheduled"
+	case HistoryEventTypeStateWaitUntilCompleted:
+		return "StateWaitUntilCompleted"
+	case HistoryEventTypeTimerFired:
+		return "TimerFired"
+	case HistoryEventTypeLocalQueueMessagesReceived:
+		return "LocalQueueMessagesReceived"
+	case HistoryEventTypeLocalQueueMessagesConsumed:
+		return "LocalQueueMessagesConsumed"
+	case HistoryEventTypeStateExecuteCompleted:
+		return "StateExecuteCompleted"
+	case HistoryEventTypeStateExecutionFailed:
+		return "StateExecutionFailed"
+	case HistoryEventTypeRpcDecisionApplied:
+		return "RpcDecisionApplied"
+	case HistoryEventTypeProcessExecutionClosed:
+		return "ProcessExecutionClosed"
+	case HistoryEventTypeProcessExecutionReset:
+		return "ProcessExecutionReset"
+	case HistoryEventTypeChildProcessStarted:
+		return "ChildProcessStarted"
+	case HistoryEventTypeWaitingProcessClosed:
+		return "WaitingProcessClosed"
+	case HistoryEventTypeProcessExecutionPaused:
+		return "ProcessExecutionPaused"
+	case HistoryEventTypeProcessExecutionResumed:
+		return "ProcessExecutionResumed"
+	case HistoryEventTypeLocalAttributesUpdated:
+		return "LocalAttributesUpdated"
+	default:
+		panic("this is not supported")
+	}
+}
+
+type BatchOperationStatus int32
+
+const (
+	BatchOperationStatusRunning   BatchOperationStatus = 1
+	BatchOperationStatusCompleted BatchOperationStatus = 2
+	BatchOperationStatusCanceled  BatchOperationStatus = 3
+	BatchOperationStatusFailed    BatchOperationStatus = 4
+)
+
+func (e BatchOperationStatus) String() string {
+	switch e {
+	case BatchOperationStatusRunning:
+		return "RUNNING"
+	case BatchOperationStatusCompleted:
+		return "COMPLETED"
+	case BatchOperationStatusCanceled:
+		return "CANCELED"
+	case BatchOperationStatusFailed:
+		return "FAILED"
+	default:
+		panic("this is not supported")
+	}
+}
+
+// ProcessRequestType is the type of the request deduplicated by the request id
+type ProcessRequestType int32
+
+const (
+	ProcessRequestTypeStart ProcessRequestType = 1
+	ProcessRequestTypeStop  ProcessRequestType = 2
+	ProcessRequestTypeRpc   ProcessRequestType = 3
+)
+
+func (t ProcessRequestType) String() string {
+	switch t {
+	case ProcessRequestTypeStart:
+		return "Start"
+	case ProcessRequestTypeStop:
+		return "Stop"
+	case ProcessRequestTypeRpc:
+		return "Rpc"
+	default:
+		panic("this is not supported")
+	}
+}
+
+// CompletionCallbackStatus is the delivery status of a completion callback
+type CompletionCallbackStatus int32
+
+const (
+	// CompletionCallbackStatusPending is either waiting for the process to close, or delivering
+	CompletionCallbackStatusPending   CompletionCallbackStatus = 1
+	CompletionCallbackStatusDelivered CompletionCallbackStatus = 2
+	// CompletionCallbackStatusFailed means the retry policy is exhausted
+	CompletionCallbackStatusFailed CompletionCallbackStatus = 3
+)
+
+func (s CompletionCallbackStatus) String() string {
+	switch s {
+	case CompletionCallbackStatusPending:
+		return "PENDING"
+	case CompletionCallbackStatusDelivered:
+		return "DELIVERED"
+	case CompletionCallbackStatusFailed:
+		return "FAILED"
+	default:
+		panic("this is not supported")
+	}
+}
+
+type BatchOperationActionType string
+
+const (
+	BatchOperationActionTypeStop                BatchOperationActionType = "STOP"
+	BatchOperationActionTypePublishToLocalQueue BatchOperationActionType = "PUBLISH_TO_LOCAL_QUEUE"
+	BatchOperationActionTypeRpc                 BatchOperationActionType = "RPC"
+)
+
+// ScheduleOverlapPolicy decides what to do when a schedule fires while
+// the process execution started by the previous firing is still running
+type ScheduleOverlapPolicy string
+
+const (
+	// ScheduleOverlapPolicySkip skips the firing, it's the default
+	ScheduleOverlapPolicySkip ScheduleOverlapPolicy = "SKIP"
+	// ScheduleOverlapPolicyAllowAll starts a new process execution anyway
+	ScheduleOverlapPolicyAllowAll ScheduleOverlapPolicy = "ALLOW_ALL"
+	// ScheduleOverlapPolicyTerminatePrevious terminates the previous process execution, then starts a new one
+	ScheduleOverlapPolicyTerminatePrevious ScheduleOverlapPolicy = "TERMINATE_PREVIOUS"
+)
+
+type ProcessExecutionCountGroupBy string
+
+const (
+	ProcessExecutionCountGroupByNone        ProcessExecutionCountGroupBy = ""
+	ProcessExecutionCountGroupByStatus      ProcessExecutionCountGroupBy = "STATUS"
+	ProcessExecutionCountGroupByProcessType ProcessExecutionCountGroupBy = "PROCESS_TYPE"
+)
+
+// ProcessCloseReasonType is the reason why a process execution is closed
+type ProcessCloseReasonType string
+
+const (
+	// ProcessCloseReasonTypeStateDecision means the process is closed by a thread close decision of a state
+	ProcessCloseReasonTypeStateDecision ProcessCloseReasonType = "STATE_DECISION"
+	// ProcessCloseReasonTypeContinuedAsNew means the process is closed by continuing as a new process execution
+	ProcessCloseReasonTypeContinuedAsNew ProcessCloseReasonType = "CONTINUED_AS_NEW"
+	// ProcessCloseReasonTypeStateFailure means the process is failed by the failure recovery policy of a state
+	ProcessCloseReasonTypeStateFailure ProcessCloseReasonType = "STATE_FAILURE"
+	// ProcessCloseReasonTypeStopRequest means the process is stopped by a StopProcess request
+	ProcessCloseReasonTypeStopRequest ProcessCloseReasonType = "STOP_REQUEST"
+	// ProcessCloseReasonTypeTimeout means the process is timed out by the process timeout
+	ProcessCloseReasonTypeTimeout ProcessCloseReasonType = "TIMEOUT"
+	// ProcessCloseReasonTypeIdReusePolicy means the process is terminated by starting a new process execution
+	// with the TERMINATE_IF_RUNNING id reuse policy
+	ProcessCloseReasonTypeIdReusePolicy ProcessCloseReasonType = "ID_REUSE_POLICY"
+)
+
+// IsValid returns true if the reason type is one of the defined ones
+func (t ProcessCloseReasonType) IsValid() bool {
+	switch t {
+	case ProcessCloseReasonTypeStateDecision, ProcessCloseReasonTypeContinuedAsNew, ProcessCloseReasonTypeStateFailure,
+		ProcessCloseReasonTypeStopRequest, ProcessCloseReasonTypeTimeout, ProcessCloseReasonTypeIdReusePolicy:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type DeleteProcessExecutionVisibilityRequest struct {
+	Namespace          string
+	ProcessExecutionId uuid.UUID
+}
@@ -0,0 +1,15 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	DeleteScheduleRequest struct {
+		Namespace  string
+		ScheduleId string
+	}
+
+	DeleteScheduleResponse struct {
+		NotExists bool
+	}
+)
@@ -3,7 +3,10 @@
 
 package data_models
 
-import "github.com/xcherryio/apis/goapi/xcapi"
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/uuid"
+)
 
 type (
 	DescribeLatestProcessRequest struct {
@@ -11,8 +14,32 @@ type (
 		ProcessId string
 	}
 
+	DescribeProcessExecutionRequest struct {
+		Namespace          string
+		ProcessExecutionId uuid.UUID
+	}
+
+	DescribeProcessExecutionResponse = DescribeLatestProcessResponse
+
 	DescribeLatestProcessResponse struct {
 		Response  *xcapi.ProcessExecutionDescribeResponse
 		NotExists bool
+		ProcessId string
+		// CloseTimestamp is zero if the process execution is running
+		CloseTimestamp int64
+		// DelayedStartTimestamp is the time to run the start state, zero if the start is not delayed
+		DelayedStartTimestamp int64
+		// ParentProcess is only set if the process is started as a child process
+		ParentProcess  *ParentProcessJson
+		ChildProcesses []ChildProcess
+		Paused         bool
+		// CompletionCallbacks are the delivery status of the callback urls registered by StartProcess
+		CompletionCallbacks []CompletionCallback
+		// ContinuedFromProcessExecutionId is only set if the process execution is started by continuing as new
+		ContinuedFromProcessExecutionId string
+		// ContinuedAsNewProcessExecutionId is only set if the process execution is closed by continuing as new
+		ContinuedAsNewProcessExecutionId string
+		// CloseRecord is only set if the process execution is closed
+		CloseRecord *ProcessCloseRecordJson
 	}
 )
@@ -0,0 +1,40 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	DescribeStateExecutionsRequest struct {
+		Namespace string
+		ProcessId string
+		// optional, the latest execution of the processId will be used if not provided
+		ProcessExecutionId *uuid.UUID
+		// only return the state executions in the PendingExecutionMap
+		PendingOnly bool
+	}
+
+	DescribeStateExecutionsResponse struct {
+		NotExists bool
+
+		ProcessExecutionId uuid.UUID
+		StateExecutions    []StateExecutionDescription
+	}
+
+	StateExecutionDescription struct {
+		StateExecutionId
+		Status StateExecutionStatus
+		// true if the state execution is in the PendingExecutionMap of the process execution
+		Pending bool
+
+		WaitUntilCommands       xcapi.CommandRequest
+		WaitUntilCommandResults CommandResultsJson
+		LastFailure             *StateExecutionFailureJson
+
+		Info AsyncStateExecutionInfoJson
+	}
+)
@@ -0,0 +1,18 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	GetArchivedProcessExecutionRequest struct {
+		Namespace          string
+		ProcessExecutionId uuid.UUID
+	}
+
+	GetArchivedProcessExecutionResponse struct {
+		NotExists bool
+		Record    ArchivedProcessExecutionJson
+	}
+)
@@ -14,6 +14,7 @@ type (
 	GetLatestProcessExecutionResponse struct {
 		NotExists bool
 
+		ProcessId          string
 		ProcessExecutionId uuid.UUID
 		ShardId            int32
 		Status             ProcessExecutionStatus
@@ -24,5 +25,7 @@ type (
 		ProcessType string
 		// the URL for server async service to make callback to worker
 		WorkerUrl string
+		// CloseResult is only set if the process execution is closed
+		CloseResult *ProcessCloseResultJson
 	}
 )
@@ -0,0 +1,17 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	GetProcessExecutionForArchivalRequest struct {
+		ProcessExecutionId uuid.UUID
+	}
+
+	GetProcessExecutionForArchivalResponse struct {
+		NotExists bool
+		Record    ArchivedProcessExecutionJson
+	}
+)
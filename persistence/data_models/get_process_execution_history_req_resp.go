@@ -0,0 +1,35 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	GetProcessExecutionHistoryRequest struct {
+		Namespace string
+		ProcessId string
+		// optional, the latest execution of the processId will be used if not provided
+		ProcessExecutionId *uuid.UUID
+
+		MinEventIdExclusive int32
+		PageSize            int32
+	}
+
+	GetProcessExecutionHistoryResponse struct {
+		NotExists bool
+
+		ProcessExecutionId uuid.UUID
+		Events             []HistoryEvent
+		// true if there may be more events after the last event of this page
+		HasMore bool
+	}
+
+	HistoryEvent struct {
+		EventId          int32
+		EventType        HistoryEventType
+		StateExecutionId StateExecutionId
+		CreateTimestamp  int64
+		Info             HistoryEventInfoJson
+	}
+)
@@ -0,0 +1,17 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	GetProcessExecutionRequest struct {
+		Namespace          string
+		ProcessExecutionId uuid.UUID
+	}
+
+	// GetProcessExecutionResponse is the same as GetLatestProcessExecutionResponse,
+	// NotExists is true if the process execution is not in the namespace
+	GetProcessExecutionResponse = GetLatestProcessExecutionResponse
+)
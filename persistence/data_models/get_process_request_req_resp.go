@@ -0,0 +1,25 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	GetProcessRequestRequest struct {
+		Namespace string
+		ProcessId string
+		RequestId string
+	}
+
+	GetProcessRequestResponse struct {
+		NotExists bool
+
+		RequestType ProcessRequestType
+		// ProcessExecutionId is the process execution that the request was applied to
+		ProcessExecutionId uuid.UUID
+		Response           ProcessRequestResponseJson
+	}
+)
@@ -0,0 +1,14 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	GetRunningBatchOperationsRequest struct {
+		PageSize int32
+	}
+
+	GetRunningBatchOperationsResponse struct {
+		BatchOperations []BatchOperation
+	}
+)
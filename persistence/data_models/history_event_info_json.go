@@ -0,0 +1,69 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// HistoryEventInfoJson is the details of a history event.
+// Only the fields related to the event type are set.
+type HistoryEventInfoJson struct {
+	// for ProcessExecutionStarted
+	ProcessType *string `json:"processType,omitempty"`
+	WorkerURL   *string `json:"workerURL,omitempty"`
+
+	// for StateWaitUntilCompleted
+	CommandRequest *xcapi.CommandRequest `json:"commandRequest,omitempty"`
+
+	// for TimerFired
+	TimerCommandIndex *int `json:"timerCommandIndex,omitempty"`
+
+	// for LocalQueueMessagesReceived
+	LocalQueueMessages []LocalQueueMessageInfoJson `json:"localQueueMessages,omitempty"`
+	// for LocalQueueMessagesConsumed
+	ConsumedDedupIds []string `json:"consumedDedupIds,omitempty"`
+
+	// for StateExecuteCompleted and RpcDecisionApplied
+	StateDecision *xcapi.StateDecision `json:"stateDecision,omitempty"`
+	RpcName       *string              `json:"rpcName,omitempty"`
+
+	// for StateExecutionFailed
+	Failure *StateExecutionFailureJson `json:"failure,omitempty"`
+
+	// for ProcessExecutionClosed and WaitingProcessClosed
+	ProcessStatus *string `json:"processStatus,omitempty"`
+	// for ProcessExecutionClosed
+	ContinuedAsNewProcessExecutionId *string `json:"continuedAsNewProcessExecutionId,omitempty"`
+
+	// for ChildProcessStarted and WaitingProcessClosed
+	ProcessId          *string `json:"processId,omitempty"`
+	ProcessExecutionId *string `json:"processExecutionId,omitempty"`
+	// for WaitingProcessClosed
+	ProcessCompletionCommandIndex *int `json:"processCompletionCommandIndex,omitempty"`
+
+	// for ProcessExecutionReset
+	ResetToStateExecutionId *string `json:"resetToStateExecutionId,omitempty"`
+	ResetReason             *string `json:"resetReason,omitempty"`
+
+	// for ProcessExecutionPaused, ProcessExecutionResumed, ProcessExecutionClosed and LocalAttributesUpdated
+	Reason *string `json:"reason,omitempty"`
+
+	// for LocalAttributesUpdated
+	LocalAttributeKeys []string `json:"localAttributeKeys,omitempty"`
+}
+
+func (s *HistoryEventInfoJson) ToBytes() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func BytesToHistoryEventInfo(bytes []byte) (HistoryEventInfoJson, error) {
+	var obj HistoryEventInfoJson
+	if len(bytes) == 0 {
+		return obj, nil
+	}
+	err := json.Unmarshal(bytes, &obj)
+	return obj, err
+}
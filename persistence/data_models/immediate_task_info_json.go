@@ -12,6 +12,16 @@ type ImmediateTaskInfoJson struct {
 	LocalQueueMessageInfo []LocalQueueMessageInfoJson `json:"localQueueMessageInfo"`
 	// used when the `task_type` is visibility
 	VisibilityInfo *VisibilityInfoJson `json:"visibilityInfo"`
+	// used when the `task_type` is processCompletionCommand
+	ProcessCompletionInfo *ProcessCompletionInfoJson `json:"processCompletionInfo,omitempty"`
+	// used when the `task_type` is completionCallback
+	CompletionCallbackInfo *CompletionCallbackInfoJson `json:"completionCallbackInfo,omitempty"`
+}
+
+// ProcessCompletionInfoJson is the result of a process completion command of the waiting state execution
+type ProcessCompletionInfoJson struct {
+	CommandIndex int                         `json:"commandIndex"`
+	Result       ProcessCompletionResultJson `json:"result"`
 }
 
 func BytesToImmediateTaskInfo(bytes []byte) (ImmediateTaskInfoJson, error) {
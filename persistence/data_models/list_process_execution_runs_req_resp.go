@@ -0,0 +1,34 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import "github.com/xcherryio/xcherry/common/uuid"
+
+type (
+	ListProcessExecutionRunsRequest struct {
+		Namespace string
+		ProcessId string
+		PageSize  int32
+		// NextPageToken is from the previous page, nil for the first page
+		NextPageToken *string
+	}
+
+	ListProcessExecutionRunsResponse struct {
+		// Runs are ordered by the start time, the closed runs are deleted after the retention
+		Runs []ProcessExecutionRun
+		// NextPageToken is nil if there are no more runs
+		NextPageToken *string
+	}
+
+	ProcessExecutionRun struct {
+		ProcessExecutionId uuid.UUID
+		ProcessType        string
+		Status             ProcessExecutionStatus
+		StartTimestamp     int64
+		// CloseTimestamp is zero if the process execution is running
+		CloseTimestamp int64
+		// Latest is true if the run is the latest process execution of the processId
+		Latest bool
+	}
+)
@@ -0,0 +1,30 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
+)
+
+type (
+	ListProcessExecutionsRequest struct {
+		xcapi.ListProcessExecutionsRequest
+
+		// CloseTimeFilter is optional, to only return the closed process executions within the time range.
+		// StartTimeFilter is optional when CloseTimeFilter is provided.
+		CloseTimeFilter *xcapi.TimeRangeFilter
+		// Query is optional, and must have been validated. Its filter is combined with the other filters using AND.
+		// The process executions are sorted by start time in descending order if ORDER BY is not provided.
+		// StartTimeFilter is optional when Query is provided.
+		Query *visibilityquery.Query
+	}
+
+	ListProcessExecutionsResponse struct {
+		xcapi.ListProcessExecutionsResponse
+
+		// CloseRecords are aligned with ProcessExecutions by index, the element is nil for a running process execution
+		CloseRecords []*ProcessCloseRecordJson
+	}
+)
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	GetLocalAttributesRequest struct {
+		Namespace string
+		ProcessId string
+		// optional, the latest execution of the processId will be used if not provided
+		ProcessExecutionId *uuid.UUID
+		// optional, all the local attributes will be returned if empty
+		Keys []string
+	}
+
+	GetLocalAttributesResponse struct {
+		NotExists bool
+
+		ProcessExecutionId uuid.UUID
+		Attributes         []LocalAttribute
+	}
+
+	LocalAttribute struct {
+		Key     string
+		Value   xcapi.EncodedObject
+		Version int64
+	}
+
+	SetLocalAttributesRequest struct {
+		Namespace string
+		ProcessId string
+		// optional, the latest execution of the processId will be used if not provided
+		ProcessExecutionId *uuid.UUID
+		Attributes         []LocalAttributeWrite
+		Reason             string
+	}
+
+	LocalAttributeWrite struct {
+		Key   string
+		Value xcapi.EncodedObject
+		// ExpectedVersion is optional for the compare-and-set, 0 means the key must not exist
+		ExpectedVersion *int64
+	}
+
+	SetLocalAttributesResponse struct {
+		NotExists bool
+
+		ProcessExecutionId uuid.UUID
+		// VersionConflicts is not empty if any expected version doesn't match, then nothing is written
+		VersionConflicts []LocalAttributeVersionConflict
+		// Attributes are the written local attributes with the new versions
+		Attributes []LocalAttribute
+	}
+
+	LocalAttributeVersionConflict struct {
+		Key             string
+		ExpectedVersion int64
+		// CurrentVersion is 0 if the key doesn't exist
+		CurrentVersion int64
+	}
+)
@@ -8,6 +8,9 @@ import "encoding/json"
 type PaginationToken struct {
 	LastProcessExecutionId string `json:"lastProcessExecutionId"`
 	LastStartTime          int64  `json:"lastStartTime"`
+	// LastSortValue is the text form of the value to sort by of the last process execution,
+	// LastStartTime is used instead when it's not set, for the tokens issued before sorting is supported
+	LastSortValue *string `json:"lastSortValue,omitempty"`
 }
 
 func NewPaginationToken(lastProcessExecutionId string, lastStartTime int64) *PaginationToken {
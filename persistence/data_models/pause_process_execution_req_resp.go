@@ -0,0 +1,45 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	PauseProcessExecutionRequest struct {
+		ProcessExecutionId uuid.UUID
+		Reason             string
+	}
+
+	PauseProcessExecutionResponse struct {
+		ProcessNotExists  bool
+		ProcessNotRunning bool
+		AlreadyPaused     bool
+	}
+
+	ResumeProcessExecutionRequest struct {
+		ProcessExecutionId uuid.UUID
+		Reason             string
+	}
+
+	ResumeProcessExecutionResponse struct {
+		ProcessNotExists  bool
+		ProcessNotRunning bool
+		NotPaused         bool
+
+		// HasNewImmediateTask is true if any parked task is inserted back to the immediate tasks
+		HasNewImmediateTask bool
+	}
+
+	ParkImmediateTaskRequest struct {
+		Task ImmediateTask
+	}
+
+	ParkImmediateTaskResponse struct {
+		// Parked is false if the process execution is resumed after preparing the state execution,
+		// then the task should be processed as usual
+		Parked bool
+	}
+)
@@ -18,6 +18,8 @@ type (
 		Status StateExecutionStatus
 		// only applicable for state execute API
 		WaitUntilCommandResults xcapi.CommandResults
+		// only applicable for state execute API, one result for each process completion command
+		ProcessCompletionResults []ProcessCompletionResultJson
 
 		// PreviousVersion is for conditional check in the future transactional update
 		PreviousVersion int32
@@ -25,5 +27,8 @@ type (
 		Input       xcapi.EncodedObject
 		Info        AsyncStateExecutionInfoJson
 		LastFailure *StateExecutionFailureJson
+
+		// ProcessExecutionPaused is true if the waitUntil/execute task should be parked instead of calling the worker
+		ProcessExecutionPaused bool
 	}
 )
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// ProcessCloseRecordJson records why a process execution is closed
+type ProcessCloseRecordJson struct {
+	ReasonType ProcessCloseReasonType `json:"reasonType,omitempty"`
+	// Failure is only set if the process is failed by a state execution failure
+	Failure *ProcessFailureJson `json:"failure,omitempty"`
+	// StopReason is the optional reason provided by the caller of StopProcess
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+// ProcessCloseResultJson is the final result of a closed process execution
+type ProcessCloseResultJson struct {
+	ProcessCloseRecordJson
+	// Output is the closeInput of the thread close decision that closed the process
+	Output *xcapi.EncodedObject `json:"output,omitempty"`
+	// CloseTimestamp is the unix seconds of when the process execution was closed
+	CloseTimestamp int64 `json:"closeTimestamp,omitempty"`
+	// ContinuedAsNewProcessExecutionId is only set if the process execution is closed by continuing as new
+	ContinuedAsNewProcessExecutionId string `json:"continuedAsNewProcessExecutionId,omitempty"`
+}
+
+func BytesToProcessCloseResult(bytes []byte) (ProcessCloseResultJson, error) {
+	var obj ProcessCloseResultJson
+	if len(bytes) == 0 {
+		return obj, nil
+	}
+	err := json.Unmarshal(bytes, &obj)
+	return obj, err
+}
+
+func (j ProcessCloseResultJson) ToBytes() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+func BytesToProcessCloseRecord(bytes []byte) (*ProcessCloseRecordJson, error) {
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+	var obj ProcessCloseRecordJson
+	err := json.Unmarshal(bytes, &obj)
+	return &obj, err
+}
+
+func (j ProcessCloseRecordJson) ToBytes() ([]byte, error) {
+	return json.Marshal(j)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// ProcessCompletionCommandJson waits for the latest process execution of the processId to close,
+// in the same namespace as the waiting process. It completes immediately if the process is already closed.
+type ProcessCompletionCommandJson struct {
+	ProcessId string `json:"processId"`
+}
+
+// ProcessCompletionResultJson is the result of a ProcessCompletionCommandJson
+type ProcessCompletionResultJson struct {
+	Status    xcapi.CommandStatus `json:"status"`
+	ProcessId string              `json:"processId"`
+	// ProcessExecutionId and ProcessStatus are only set when the status is COMPLETED_COMMAND
+	ProcessExecutionId *string              `json:"processExecutionId,omitempty"`
+	ProcessStatus      *xcapi.ProcessStatus `json:"processStatus,omitempty"`
+}
+
+// ProcessCompletionCommandsJson is the extension of xcapi.CommandRequest, see AsyncStateWaitUntilResponseExtJson.
+// It's stored in the same JSON with the wait until commands.
+type ProcessCompletionCommandsJson struct {
+	ProcessCompletionCommands []ProcessCompletionCommandJson `json:"processCompletionCommands,omitempty"`
+}
+
+// FromCommandRequestWithProcessCompletionCommandsToBytes merges the process completion commands
+// into the JSON of the command request
+func FromCommandRequestWithProcessCompletionCommandsToBytes(
+	request xcapi.CommandRequest, commands []ProcessCompletionCommandJson,
+) ([]byte, error) {
+	requestBytes, err := FromCommandRequestToBytes(request)
+	if err != nil || len(commands) == 0 {
+		return requestBytes, err
+	}
+
+	var merged map[string]interface{}
+	err = json.Unmarshal(requestBytes, &merged)
+	if err != nil {
+		return nil, err
+	}
+	merged["processCompletionCommands"] = commands
+	return json.Marshal(merged)
+}
+
+func BytesToProcessCompletionCommands(bytes []byte) ([]ProcessCompletionCommandJson, error) {
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+
+	var obj ProcessCompletionCommandsJson
+	err := json.Unmarshal(bytes, &obj)
+	return obj.ProcessCompletionCommands, err
+}
@@ -5,20 +5,32 @@ package data_models
 
 import (
 	"encoding/json"
-	"github.com/xcherryio/apis/goapi/xcapi"
 )
 
 type ProcessExecutionInfoJson struct {
 	ProcessType       string                     `json:"processType"`
 	WorkerURL         string                     `json:"workerURL"`
 	AppDatabaseConfig *InternalAppDatabaseConfig `json:"appDatabaseConfig"`
+	// DelayedStartTimestamp is the time to run the start state, only set if the start is delayed
+	DelayedStartTimestamp *int64 `json:"delayedStartTimestamp,omitempty"`
+	// ParentProcess is only set if the process is started as a child process
+	ParentProcess *ParentProcessJson `json:"parentProcess,omitempty"`
+	// ContinuedFromProcessExecutionId is only set if the process execution is started by continuing as new
+	ContinuedFromProcessExecutionId string `json:"continuedFromProcessExecutionId,omitempty"`
 }
 
-func FromStartRequestToProcessInfoBytes(req xcapi.ProcessExecutionStartRequest) ([]byte, error) {
+func FromStartRequestToProcessInfoBytes(request StartProcessRequest) ([]byte, error) {
+	req := request.Request
 	info := ProcessExecutionInfoJson{
 		ProcessType:       req.GetProcessType(),
 		WorkerURL:         req.GetWorkerUrl(),
 		AppDatabaseConfig: getInternalAppDatabaseConfig(req),
+		ParentProcess:     request.ParentProcess,
+
+		ContinuedFromProcessExecutionId: request.ContinuedFromProcessExecutionId,
+	}
+	if request.DelayedStartTimeUnixSeconds != 0 {
+		info.DelayedStartTimestamp = &request.DelayedStartTimeUnixSeconds
 	}
 	return json.Marshal(info)
 }
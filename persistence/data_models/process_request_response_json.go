@@ -0,0 +1,30 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// ProcessRequestResponseJson is the response of a request with a request id,
+// which is returned again for the repeated requests with the same request id
+type ProcessRequestResponseJson struct {
+	// RpcOutput is only for the Rpc requests
+	RpcOutput *xcapi.EncodedObject `json:"rpcOutput,omitempty"`
+}
+
+func (r ProcessRequestResponseJson) ToBytes() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func BytesToProcessRequestResponse(bytes []byte) (ProcessRequestResponseJson, error) {
+	var obj ProcessRequestResponseJson
+	if len(bytes) == 0 {
+		return obj, nil
+	}
+	err := json.Unmarshal(bytes, &obj)
+	return obj, err
+}
@@ -3,6 +3,8 @@
 
 package data_models
 
+import "github.com/xcherryio/xcherry/common/uuid"
+
 type (
 	ProcessTimerTaskRequest struct {
 		Task TimerTask
@@ -10,5 +12,9 @@ type (
 
 	ProcessTimerTaskResponse struct {
 		HasNewImmediateTask bool
+		// the process execution of the new immediate tasks, only set for TimerTaskTypeFireSchedule
+		ProcessExecutionId *uuid.UUID
+		// the fire timestamps of the new timer tasks in the same shard, only set for TimerTaskTypeFireSchedule
+		NewTimerTaskFireTimestamps []int64
 	}
 )
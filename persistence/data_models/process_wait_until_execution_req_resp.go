@@ -18,6 +18,9 @@ type (
 		PublishToLocalQueue []xcapi.LocalQueueMessage
 		TaskShardId         int32
 		TaskSequence        int64
+
+		// ProcessCompletionCommands are waited for with the same waiting type as the CommandRequest
+		ProcessCompletionCommands []ProcessCompletionCommandJson
 	}
 
 	ProcessWaitUntilExecutionResponse struct {
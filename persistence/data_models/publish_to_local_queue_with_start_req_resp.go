@@ -0,0 +1,30 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	PublishToLocalQueueWithStartRequest struct {
+		// StartRequest is used to start a new process execution if the process is not running
+		StartRequest StartProcessRequest
+		Messages     []xcapi.LocalQueueMessage
+	}
+
+	PublishToLocalQueueWithStartResponse struct {
+		ProcessExecutionId uuid.UUID
+		ShardId            int32
+		// Started is true if a new process execution is started,
+		// otherwise the messages are published to the running process execution
+		Started             bool
+		HasNewImmediateTask bool
+		// AlreadyStarted is true if the process is not running, and the IdReusePolicy disallows starting a new one
+		AlreadyStarted             bool
+		FailedAtWritingAppDatabase bool
+		AppDatabaseWritingError    error
+	}
+)
@@ -0,0 +1,14 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type RecordCompletionCallbackAttemptRequest struct {
+	Task      ImmediateTask
+	Delivered bool
+	// LastFailure is set if the attempt is failed
+	LastFailure *StateExecutionFailureJson
+	// NextAttemptTimestampSeconds is set if the failed attempt will be retried after the backoff,
+	// otherwise the callback is failed
+	NextAttemptTimestampSeconds *int64
+}
@@ -13,4 +13,8 @@ type RecordProcessExecutionStatusRequest struct {
 	Status             ProcessExecutionStatus
 	StartTime          *int64
 	CloseTime          *int64
+	DelayedStartTime   *int64
+	// CloseRecord is only set when the process execution is closed
+	CloseRecord      *ProcessCloseRecordJson
+	SearchAttributes map[string]interface{}
 }
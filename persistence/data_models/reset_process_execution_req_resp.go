@@ -0,0 +1,26 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"github.com/xcherryio/xcherry/common/uuid"
+)
+
+type (
+	ResetProcessExecutionRequest struct {
+		ProcessExecutionId uuid.UUID
+		// the prior state execution to start a new sequence from
+		ResetToStateExecutionId StateExecutionId
+		Reason                  string
+	}
+
+	ResetProcessExecutionResponse struct {
+		ProcessNotExists        bool
+		ProcessNotRunning       bool
+		StateExecutionNotExists bool
+
+		HasNewImmediateTask bool
+		NewStateExecutionId StateExecutionId
+	}
+)
@@ -0,0 +1,30 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+// Schedule starts new process executions periodically with a start request template
+type Schedule struct {
+	Namespace  string
+	ScheduleId string
+
+	// the shard of the timer tasks to fire the schedule, and the tasks of the started process executions
+	ShardId int32
+	Paused  bool
+	Info    ScheduleInfoJson
+
+	// zero if the schedule will not fire, e.g. paused
+	NextFireTimestamp int64
+	// zero if the schedule has never fired
+	LastFireTimestamp int64
+	// the processId started by the last firing, empty if never started
+	LastProcessId string
+	FiredCount    int32
+	SkippedCount  int32
+
+	// for conditional update
+	Version int32
+
+	CreateTimestamp     int64
+	LastUpdateTimestamp int64
+}
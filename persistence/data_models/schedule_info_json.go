@@ -0,0 +1,85 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/cron"
+)
+
+// ScheduleInfoJson is the definition of a schedule, which only changes by updating the schedule
+type ScheduleInfoJson struct {
+	Spec ScheduleSpecJson `json:"spec"`
+	// StartRequest is the template to start the process executions.
+	// The processId of each firing is the processId of the template suffixed with the fire time,
+	// e.g. "daily-report-20231201T000000Z", and the IdReusePolicy is always DISALLOW_REUSE
+	// so that each fire time starts at most one process execution.
+	StartRequest     xcapi.ProcessExecutionStartRequest `json:"startRequest"`
+	SearchAttributes map[string]interface{}             `json:"searchAttributes,omitempty"`
+	OverlapPolicy    ScheduleOverlapPolicy              `json:"overlapPolicy"`
+}
+
+// ScheduleSpecJson is either a cron expression in UTC, or a fixed interval
+type ScheduleSpecJson struct {
+	CronExpression  *string `json:"cronExpression,omitempty"`
+	IntervalSeconds *int64  `json:"intervalSeconds,omitempty"`
+}
+
+func (j *ScheduleInfoJson) ToBytes() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+func BytesToScheduleInfo(bytes []byte) (ScheduleInfoJson, error) {
+	var obj ScheduleInfoJson
+	err := json.Unmarshal(bytes, &obj)
+	return obj, err
+}
+
+// Validate checks that exactly one of the cron expression and the interval is valid
+func (s ScheduleSpecJson) Validate() error {
+	if (s.CronExpression == nil) == (s.IntervalSeconds == nil) {
+		return fmt.Errorf("exactly one of cronExpression and intervalSeconds is required")
+	}
+	if s.CronExpression != nil {
+		_, err := cron.Parse(*s.CronExpression)
+		return err
+	}
+	if *s.IntervalSeconds < 60 {
+		return fmt.Errorf("intervalSeconds must be at least 60")
+	}
+	return nil
+}
+
+// GetNextFireTimestamp returns the first fire time that is strictly after the timestamp,
+// or zero if the schedule will never fire again. The intervals are aligned to the unix epoch.
+func (s ScheduleSpecJson) GetNextFireTimestamp(afterTimestamp int64) (int64, error) {
+	if s.IntervalSeconds != nil {
+		interval := *s.IntervalSeconds
+		if interval <= 0 {
+			return 0, fmt.Errorf("invalid interval %v", interval)
+		}
+		return (afterTimestamp/interval + 1) * interval, nil
+	}
+	if s.CronExpression == nil {
+		return 0, fmt.Errorf("either cron expression or interval is required")
+	}
+	schedule, err := cron.Parse(*s.CronExpression)
+	if err != nil {
+		return 0, err
+	}
+	next := schedule.Next(time.Unix(afterTimestamp, 0).UTC())
+	if next.IsZero() {
+		return 0, nil
+	}
+	return next.Unix(), nil
+}
+
+// GetProcessIdForFireTimestamp returns the deterministic processId to start for the fire time
+func (j *ScheduleInfoJson) GetProcessIdForFireTimestamp(fireTimestamp int64) string {
+	return fmt.Sprintf("%v-%v", j.StartRequest.ProcessId, time.Unix(fireTimestamp, 0).UTC().Format("20060102T150405Z"))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/xcherryio/xcherry/config"
+)
+
+// ValidateSearchAttributes checks the search attributes are registered and the values match the registered types
+func ValidateSearchAttributes(
+	registry map[string]config.SearchAttributeType, attributes map[string]interface{}, allowNull bool,
+) error {
+	for name, value := range attributes {
+		saType, ok := registry[name]
+		if !ok {
+			return fmt.Errorf("search attribute %v is not registered", name)
+		}
+		if value == nil {
+			if allowNull {
+				continue
+			}
+			return fmt.Errorf("value of search attribute %v cannot be null", name)
+		}
+		if err := ValidateSearchAttributeValue(saType, value); err != nil {
+			return fmt.Errorf("invalid value of search attribute %v: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func ValidateSearchAttributeValue(saType config.SearchAttributeType, value interface{}) error {
+	switch saType {
+	case config.SearchAttributeTypeString:
+		if _, ok := value.(string); ok {
+			return nil
+		}
+	case config.SearchAttributeTypeInteger:
+		switch v := value.(type) {
+		case float64:
+			if v == math.Trunc(v) {
+				return nil
+			}
+		case json.Number:
+			if _, err := v.Int64(); err == nil {
+				return nil
+			}
+		case int, int32, int64:
+			return nil
+		}
+	case config.SearchAttributeTypeDouble:
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64:
+			return nil
+		case json.Number:
+			if _, err := v.Float64(); err == nil {
+				return nil
+			}
+		}
+	case config.SearchAttributeTypeBoolean:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported search attribute type %v", saType)
+	}
+	return fmt.Errorf("%v is not a valid %v", value, saType)
+}
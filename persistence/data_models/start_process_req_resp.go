@@ -13,6 +13,18 @@ type (
 		Request                xcapi.ProcessExecutionStartRequest
 		NewTaskShardId         int32
 		TimeoutTimeUnixSeconds int64
+		// DelayedStartTimeUnixSeconds is the time to run the start state, zero if the start is not delayed
+		DelayedStartTimeUnixSeconds int64
+		SearchAttributes            map[string]interface{}
+		// ParentProcess is set if the process is started as a child process
+		ParentProcess *ParentProcessJson
+		// ContinuedFromProcessExecutionId is set if the process is started by continuing as new
+		ContinuedFromProcessExecutionId string
+		// RequestId is recorded with the started process execution if not empty, see GetProcessRequest
+		RequestId string
+		// CompletionCallbackUrls are posted with the CompletionCallbackPayloadJson after the process is closed
+		CompletionCallbackUrls        []string
+		CompletionCallbackRetryPolicy *xcapi.RetryPolicy
 	}
 
 	StartProcessResponse struct {
@@ -21,5 +33,20 @@ type (
 		HasNewImmediateTask        bool
 		FailedAtWritingAppDatabase bool
 		AppDatabaseWritingError    error
+		// DuplicateRequest is the request recorded by a concurrent request with the same request id.
+		// It's only set if the request id is recorded in between, and nothing is written by this request.
+		DuplicateRequest *GetProcessRequestResponse
 	}
 )
+
+// GetTimerTaskFireTimestamps returns the fire timestamps of the timer tasks created by starting the process
+func (r StartProcessRequest) GetTimerTaskFireTimestamps() []int64 {
+	var fireTimestamps []int64
+	if r.TimeoutTimeUnixSeconds != 0 {
+		fireTimestamps = append(fireTimestamps, r.TimeoutTimeUnixSeconds)
+	}
+	if r.DelayedStartTimeUnixSeconds != 0 {
+		fireTimestamps = append(fireTimestamps, r.DelayedStartTimeUnixSeconds)
+	}
+	return fireTimestamps
+}
@@ -15,14 +15,28 @@ type StateExecutionFailureJson struct {
 	Details              *string `json:"details"`
 	CompletedAttempts    *int32  `json:"completedAttempts"`
 	LastAttemptTimestamp *int64  `json:"lastAttemptTimestamp"`
+	// only set when the state execution is backing off for the next attempt
+	NextAttemptTimestamp *int64 `json:"nextAttemptTimestamp,omitempty"`
 }
 
-func CreateStateExecutionFailureBytesForBackoff(status int32, details string, completedAttempts int32) ([]byte, error) {
+func CreateStateExecutionFailureBytesForBackoff(
+	status int32, details string, completedAttempts int32, nextAttemptTimestamp *int64,
+) ([]byte, error) {
 	obj := StateExecutionFailureJson{
 		StatusCode:           &status,
 		Details:              &details,
 		CompletedAttempts:    &completedAttempts,
 		LastAttemptTimestamp: ptr.Any(time.Now().Unix()),
+		NextAttemptTimestamp: nextAttemptTimestamp,
 	}
 	return json.Marshal(obj)
 }
+
+func BytesToStateExecutionFailure(bytes []byte) (*StateExecutionFailureJson, error) {
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+	var obj StateExecutionFailureJson
+	err := json.Unmarshal(bytes, &obj)
+	return &obj, err
+}
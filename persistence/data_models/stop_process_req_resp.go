@@ -3,7 +3,10 @@
 
 package data_models
 
-import "github.com/xcherryio/apis/goapi/xcapi"
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/uuid"
+)
 
 type (
 	StopProcessRequest struct {
@@ -11,9 +14,20 @@ type (
 		ProcessId       string
 		ProcessStopType xcapi.ProcessExecutionStopType
 		NewTaskShardId  int32
+		// RequestId is recorded with the latest process execution if not empty, see GetProcessRequest
+		RequestId string
+		// Failure is set if the process is failed by a state execution failure
+		Failure *ProcessFailureJson
+		// Reason is the optional reason of stopping the process, recorded in the close record
+		Reason string
 	}
 
 	StopProcessResponse struct {
 		NotExists bool
+		// ProcessExecutionId is the latest process execution, only set if the process exists
+		ProcessExecutionId uuid.UUID
+		// DuplicateRequest is the request recorded by a concurrent request with the same request id.
+		// It's only set if the request id is recorded in between, and nothing is written by this request.
+		DuplicateRequest *GetProcessRequestResponse
 	}
 )
@@ -11,6 +11,18 @@ type TimerTaskInfoJson struct {
 	WorkerTaskBackoffInfo *WorkerTaskBackoffInfoJson `json:"workerTaskBackoffInfo"`
 	WorkerTaskType        *ImmediateTaskType         `json:"workerTaskType"`
 	TimerCommandIndex     int                        `json:"timerCommandIndex"`
+	// ScheduleInfo is only set for TimerTaskTypeFireSchedule
+	ScheduleInfo *ScheduleTimerTaskInfoJson `json:"scheduleInfo,omitempty"`
+	// CompletionCallbackInfo is only set for TimerTaskTypeWorkerTaskBackoff of the completion callbacks
+	CompletionCallbackInfo *CompletionCallbackInfoJson `json:"completionCallbackInfo,omitempty"`
+}
+
+type ScheduleTimerTaskInfoJson struct {
+	Namespace  string `json:"namespace"`
+	ScheduleId string `json:"scheduleId"`
+	// ScheduleVersion is the version of the schedule when the timer task is created,
+	// the timer task is stale if the schedule has been changed since then
+	ScheduleVersion int32 `json:"scheduleVersion"`
 }
 
 func (s *TimerTaskInfoJson) ToBytes() ([]byte, error) {
@@ -0,0 +1,17 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	// UpdateBatchOperationRequest updates the progress, status and ownership of a batch operation.
+	// The update is conditional on the BatchOperation.Version.
+	UpdateBatchOperationRequest struct {
+		BatchOperation BatchOperation
+	}
+
+	UpdateBatchOperationResponse struct {
+		// the batch operation has been updated by others since it was loaded
+		VersionConflict bool
+	}
+)
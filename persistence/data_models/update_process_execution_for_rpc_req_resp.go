@@ -14,6 +14,7 @@ type (
 		ProcessId          string
 		ProcessType        string
 		ProcessExecutionId uuid.UUID
+		RpcName            string
 
 		StateDecision       xcapi.StateDecision
 		PublishToLocalQueue []xcapi.LocalQueueMessage
@@ -21,8 +22,14 @@ type (
 		AppDatabaseConfig *InternalAppDatabaseConfig
 		AppDatabaseWrite  *xcapi.AppDatabaseWrite
 
+		UpsertSearchAttributes map[string]interface{}
+
 		WorkerUrl   string
 		TaskShardId int32
+
+		// RequestId is recorded with the RpcOutput if not empty, see GetProcessRequest
+		RequestId string
+		RpcOutput *xcapi.EncodedObject
 	}
 
 	UpdateProcessExecutionForRpcResponse struct {
@@ -30,5 +37,8 @@ type (
 		ProcessNotExists         bool
 		FailAtWritingAppDatabase bool
 		WritingAppDatabaseError  error
+		// DuplicateRequest is the request recorded by a concurrent request with the same request id.
+		// It's only set if the request id is recorded in between, and nothing is written by this request.
+		DuplicateRequest *GetProcessRequestResponse
 	}
 )
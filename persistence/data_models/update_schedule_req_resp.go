@@ -0,0 +1,22 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+type (
+	// UpdateScheduleRequest updates the definition of the schedule, and/or pauses or resumes it.
+	// The nil fields are unchanged.
+	UpdateScheduleRequest struct {
+		Namespace  string
+		ScheduleId string
+		Info       *ScheduleInfoJson
+		Paused     *bool
+	}
+
+	UpdateScheduleResponse struct {
+		NotExists bool
+		ShardId   int32
+		// zero if the schedule is paused, or will never fire
+		NextFireTimestamp int64
+	}
+)
@@ -13,4 +13,13 @@ type VisibilityInfoJson struct {
 	Status             ProcessExecutionStatus `json:"status"`
 	StartTime          *int64                 `json:"startTime"`
 	CloseTime          *int64                 `json:"closeTime"`
+	// DelayedStartTime is the time to run the start state, only set when starting a process with a delayed start
+	DelayedStartTime *int64 `json:"delayedStartTime,omitempty"`
+	// SearchAttributes is the initial search attributes when starting process,
+	// or the search attributes to upsert when the process is running
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
+	// CloseRecord is the reason why the process execution is closed, only set when closing a process
+	CloseRecord *ProcessCloseRecordJson `json:"closeRecord,omitempty"`
+	// Deleted is true when the process execution is deleted after the retention
+	Deleted bool `json:"deleted,omitempty"`
 }
@@ -0,0 +1,70 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package data_models
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+)
+
+// The structs below mirror the fields of the worker APIs that are not yet defined in the xcapi IDL,
+// which is maintained in the github.com/xcherryio/apis repo. They are encoded into and decoded from
+// the same JSON bodies as the xcapi requests and responses, and should be replaced by the typed xcapi fields
+// once the IDL defines them.
+
+// AsyncStateWaitUntilResponseExtJson is the extension of xcapi.AsyncStateWaitUntilResponse
+type AsyncStateWaitUntilResponseExtJson struct {
+	CommandRequest ProcessCompletionCommandsJson `json:"commandRequest"`
+}
+
+// AsyncStateExecuteRequestExtJson is the extension of xcapi.AsyncStateExecuteRequest
+type AsyncStateExecuteRequestExtJson struct {
+	CommandResults ProcessCompletionResultsJson `json:"commandResults"`
+}
+
+// ProcessCompletionResultsJson is the extension of xcapi.CommandResults
+type ProcessCompletionResultsJson struct {
+	ProcessCompletionResults []ProcessCompletionResultJson `json:"processCompletionResults,omitempty"`
+}
+
+// AsyncStateExecuteResponseExtJson is the extension of xcapi.AsyncStateExecuteResponse
+type AsyncStateExecuteResponseExtJson struct {
+	StateDecision StateDecisionExtJson `json:"stateDecision"`
+	// UpsertSearchAttributes updates the search attributes.
+	// A null value will remove the search attribute from the process execution.
+	UpsertSearchAttributes map[string]interface{} `json:"upsertSearchAttributes,omitempty"`
+}
+
+// StateDecisionExtJson is the extension of xcapi.StateDecision
+type StateDecisionExtJson struct {
+	// StartChildProcesses are started in the same namespace as the parent process,
+	// in the same transaction as completing the state execution
+	StartChildProcesses []xcapi.ProcessExecutionStartRequest `json:"startChildProcesses,omitempty"`
+	// ContinueAsNew is nil if the decision doesn't continue as new
+	ContinueAsNew *ContinueAsNew `json:"continueAsNew,omitempty"`
+}
+
+// ProcessRpcWorkerResponseExtJson is the extension of xcapi.ProcessRpcWorkerResponse
+type ProcessRpcWorkerResponseExtJson struct {
+	// UpsertSearchAttributes is the same as AsyncStateExecuteResponseExtJson.UpsertSearchAttributes
+	UpsertSearchAttributes map[string]interface{} `json:"upsertSearchAttributes,omitempty"`
+}
+
+// ReadWorkerResponseExt decodes the extension of the worker API response from the response body into ext.
+// The body is restored so that it can still be read for the error details.
+func ReadWorkerResponseExt(httpResp *http.Response, ext interface{}) error {
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	httpResp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, ext)
+}
@@ -5,7 +5,6 @@ package persistence
 
 import (
 	"context"
-	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/persistence/data_models"
 )
 
@@ -23,12 +22,43 @@ type (
 		DescribeLatestProcess(
 			ctx context.Context, request data_models.DescribeLatestProcessRequest,
 		) (*data_models.DescribeLatestProcessResponse, error)
+		DescribeProcessExecution(
+			ctx context.Context, request data_models.DescribeProcessExecutionRequest,
+		) (*data_models.DescribeProcessExecutionResponse, error)
+		// ListProcessExecutionRuns returns all the process executions of the processId, including the closed ones
+		// that are not deleted after the retention yet
+		ListProcessExecutionRuns(
+			ctx context.Context, request data_models.ListProcessExecutionRunsRequest,
+		) (*data_models.ListProcessExecutionRunsResponse, error)
 		RecoverFromStateExecutionFailure(
 			ctx context.Context, request data_models.RecoverFromStateExecutionFailureRequest,
 		) error
 		GetLatestProcessExecution(
 			ctx context.Context, request data_models.GetLatestProcessExecutionRequest,
 		) (*data_models.GetLatestProcessExecutionResponse, error)
+		GetProcessExecution(
+			ctx context.Context, request data_models.GetProcessExecutionRequest,
+		) (*data_models.GetProcessExecutionResponse, error)
+		ResetProcessExecution(
+			ctx context.Context, request data_models.ResetProcessExecutionRequest,
+		) (*data_models.ResetProcessExecutionResponse, error)
+		// GetProcessRequest returns the request recorded with the request id of StartProcess, StopProcess or
+		// UpdateProcessExecutionForRpc, to deduplicate the repeated requests
+		GetProcessRequest(
+			ctx context.Context, request data_models.GetProcessRequestRequest,
+		) (*data_models.GetProcessRequestResponse, error)
+		PauseProcessExecution(
+			ctx context.Context, request data_models.PauseProcessExecutionRequest,
+		) (*data_models.PauseProcessExecutionResponse, error)
+		ResumeProcessExecution(
+			ctx context.Context, request data_models.ResumeProcessExecutionRequest,
+		) (*data_models.ResumeProcessExecutionResponse, error)
+		DescribeStateExecutions(
+			ctx context.Context, request data_models.DescribeStateExecutionsRequest,
+		) (*data_models.DescribeStateExecutionsResponse, error)
+		GetProcessExecutionHistory(
+			ctx context.Context, request data_models.GetProcessExecutionHistoryRequest,
+		) (*data_models.GetProcessExecutionHistoryResponse, error)
 
 		GetImmediateTasks(
 			ctx context.Context, request data_models.GetImmediateTasksRequest,
@@ -53,6 +83,20 @@ type (
 		ProcessTimerTaskForProcessTimeout(
 			ctx context.Context, request data_models.ProcessTimerTaskRequest,
 		) (*data_models.ProcessTimerTaskResponse, error)
+		// ProcessTimerTaskForDeleteProcessExecution deletes all the data of the closed process execution,
+		// and adds a visibility task to delete the visibility record
+		ProcessTimerTaskForDeleteProcessExecution(
+			ctx context.Context, request data_models.ProcessTimerTaskRequest,
+		) (*data_models.ProcessTimerTaskResponse, error)
+		// ProcessTimerTaskForSchedule fires the schedule if the task is not stale, and adds the timer task for the next fire time
+		ProcessTimerTaskForSchedule(
+			ctx context.Context, request data_models.ProcessTimerTaskRequest,
+		) (*data_models.ProcessTimerTaskResponse, error)
+		// GetProcessExecutionForArchival loads the process execution with its state executions,
+		// local attributes and consumed local queue messages, for archiving it before the deletion
+		GetProcessExecutionForArchival(
+			ctx context.Context, request data_models.GetProcessExecutionForArchivalRequest,
+		) (*data_models.GetProcessExecutionForArchivalResponse, error)
 
 		PrepareStateExecution(
 			ctx context.Context, request data_models.PrepareStateExecutionRequest,
@@ -67,9 +111,27 @@ type (
 		PublishToLocalQueue(
 			ctx context.Context, request data_models.PublishToLocalQueueRequest,
 		) (*data_models.PublishToLocalQueueResponse, error)
+		// PublishToLocalQueueWithStart publishes the messages to the running process execution,
+		// or starts a new process execution and publishes the messages to it, in one transaction
+		PublishToLocalQueueWithStart(
+			ctx context.Context, request data_models.PublishToLocalQueueWithStartRequest,
+		) (*data_models.PublishToLocalQueueWithStartResponse, error)
 		ProcessLocalQueueMessages(
 			ctx context.Context, request data_models.ProcessLocalQueueMessagesRequest,
 		) (*data_models.ProcessLocalQueueMessagesResponse, error)
+		// ProcessProcessCompletionCommand delivers the status of a closed process to the waiting state execution
+		ProcessProcessCompletionCommand(
+			ctx context.Context, request data_models.ProcessProcessCompletionCommandRequest,
+		) (*data_models.ProcessProcessCompletionCommandResponse, error)
+		// ParkImmediateTask keeps the waitUntil/execute task of a paused process execution until it is resumed
+		ParkImmediateTask(
+			ctx context.Context, request data_models.ParkImmediateTaskRequest,
+		) (*data_models.ParkImmediateTaskResponse, error)
+		// RecordCompletionCallbackAttempt records the result of posting a completion callback,
+		// and schedules the next attempt after the backoff if needed
+		RecordCompletionCallbackAttempt(
+			ctx context.Context, request data_models.RecordCompletionCallbackAttemptRequest,
+		) error
 
 		ReadAppDatabase(
 			ctx context.Context, request data_models.AppDatabaseReadRequest,
@@ -78,17 +140,71 @@ type (
 		LoadLocalAttributes(
 			ctx context.Context, request data_models.LoadLocalAttributesRequest,
 		) (*data_models.LoadLocalAttributesResponse, error)
+		// GetLocalAttributes returns the local attributes with their versions, of a running or closed process execution
+		GetLocalAttributes(
+			ctx context.Context, request data_models.GetLocalAttributesRequest,
+		) (*data_models.GetLocalAttributesResponse, error)
+		// SetLocalAttributes writes the local attributes of a running or closed process execution,
+		// only if all the expected versions match
+		SetLocalAttributes(
+			ctx context.Context, request data_models.SetLocalAttributesRequest,
+		) (*data_models.SetLocalAttributesResponse, error)
 
 		UpdateProcessExecutionForRpc(ctx context.Context, request data_models.UpdateProcessExecutionForRpcRequest) (
 			*data_models.UpdateProcessExecutionForRpcResponse, error)
+
+		CreateBatchOperation(
+			ctx context.Context, request data_models.CreateBatchOperationRequest,
+		) (*data_models.CreateBatchOperationResponse, error)
+		DescribeBatchOperation(
+			ctx context.Context, request data_models.DescribeBatchOperationRequest,
+		) (*data_models.DescribeBatchOperationResponse, error)
+		GetRunningBatchOperations(
+			ctx context.Context, request data_models.GetRunningBatchOperationsRequest,
+		) (*data_models.GetRunningBatchOperationsResponse, error)
+		UpdateBatchOperation(
+			ctx context.Context, request data_models.UpdateBatchOperationRequest,
+		) (*data_models.UpdateBatchOperationResponse, error)
+		CancelBatchOperation(
+			ctx context.Context, request data_models.CancelBatchOperationRequest,
+		) (*data_models.CancelBatchOperationResponse, error)
+
+		CreateSchedule(
+			ctx context.Context, request data_models.CreateScheduleRequest,
+		) (*data_models.CreateScheduleResponse, error)
+		DescribeSchedule(
+			ctx context.Context, request data_models.DescribeScheduleRequest,
+		) (*data_models.DescribeScheduleResponse, error)
+		// UpdateSchedule updates the definition of the schedule, and/or pauses or resumes it
+		UpdateSchedule(
+			ctx context.Context, request data_models.UpdateScheduleRequest,
+		) (*data_models.UpdateScheduleResponse, error)
+		DeleteSchedule(
+			ctx context.Context, request data_models.DeleteScheduleRequest,
+		) (*data_models.DeleteScheduleResponse, error)
+		BackfillSchedule(
+			ctx context.Context, request data_models.BackfillScheduleRequest,
+		) (*data_models.BackfillScheduleResponse, error)
 	}
 
 	VisibilityStore interface {
 		Close() error
 		RecordProcessExecutionStatus(ctx context.Context, req data_models.RecordProcessExecutionStatusRequest) error
+		DeleteProcessExecution(ctx context.Context, req data_models.DeleteProcessExecutionVisibilityRequest) error
 		ListProcessExecutions(
-			ctx context.Context, request xcapi.ListProcessExecutionsRequest,
-		) (*xcapi.ListProcessExecutionsResponse, error)
-		// TODO: add count process executions api
+			ctx context.Context, request data_models.ListProcessExecutionsRequest,
+		) (*data_models.ListProcessExecutionsResponse, error)
+		CountProcessExecutions(
+			ctx context.Context, request data_models.CountProcessExecutionsRequest,
+		) (*data_models.CountProcessExecutionsResponse, error)
+	}
+
+	// Archiver is for archiving the closed process executions before they are deleted after the retention
+	Archiver interface {
+		// Archive writes the record into the archive, the existing record of the same process execution is overwritten
+		Archive(ctx context.Context, record data_models.ArchivedProcessExecutionJson) error
+		GetArchivedProcessExecution(
+			ctx context.Context, request data_models.GetArchivedProcessExecutionRequest,
+		) (*data_models.GetArchivedProcessExecutionResponse, error)
 	}
 )
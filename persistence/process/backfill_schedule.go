@@ -0,0 +1,92 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) BackfillSchedule(
+	ctx context.Context, request data_models.BackfillScheduleRequest,
+) (*data_models.BackfillScheduleResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doBackfillScheduleTx(ctx, tx, request)
+	if err != nil || resp.NotExists {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doBackfillScheduleTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.BackfillScheduleRequest,
+) (*data_models.BackfillScheduleResponse, error) {
+	// lock the schedule so that the backfill doesn't race with the firing of the same fire time
+	row, err := tx.SelectScheduleForUpdate(ctx, request.Namespace, request.ScheduleId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.BackfillScheduleResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	info, err := data_models.BytesToScheduleInfo(row.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &data_models.BackfillScheduleResponse{
+		ShardId: row.ShardId,
+	}
+	fireTimestamp := request.StartTimestamp
+	firings := 0
+	for {
+		fireTimestamp, err = info.Spec.GetNextFireTimestamp(fireTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		if fireTimestamp == 0 || fireTimestamp > request.EndTimestamp {
+			break
+		}
+		if firings >= request.MaxFirings {
+			// so that the next backfill continues from this fire time
+			resp.NextStartTimestamp = fireTimestamp - 1
+			break
+		}
+		firings++
+
+		startReq, startResp, err := p.startProcessForSchedule(ctx, tx, row.ShardId, row.Namespace, info, fireTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		if startResp.AlreadyStarted {
+			resp.SkippedCount++
+			continue
+		}
+		resp.StartedProcessExecutionIds = append(resp.StartedProcessExecutionIds, startResp.ProcessExecutionId)
+		if startReq.TimeoutTimeUnixSeconds != 0 {
+			resp.TimeoutTimestamps = append(resp.TimeoutTimestamps, startReq.TimeoutTimeUnixSeconds)
+		}
+	}
+
+	return resp, nil
+}
@@ -46,7 +46,8 @@ func (p sqlProcessStoreImpl) doBackoffImmediateTaskTx(
 		return fmt.Errorf("WorkerTaskBackoffInfo cannot be nil")
 	}
 	failureBytes, err := data_models.CreateStateExecutionFailureBytesForBackoff(
-		request.LastFailureStatus, request.LastFailureDetails, task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts)
+		request.LastFailureStatus, request.LastFailureDetails, task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts,
+		&request.FireTimestampSeconds)
 
 	if err != nil {
 		return err
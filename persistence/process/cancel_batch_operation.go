@@ -0,0 +1,53 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// the runner of the batch operation may update the checkpoint at the same time
+const maxCancelBatchOperationAttempts = 3
+
+func (p sqlProcessStoreImpl) CancelBatchOperation(
+	ctx context.Context, request data_models.CancelBatchOperationRequest,
+) (*data_models.CancelBatchOperationResponse, error) {
+	for i := 0; i < maxCancelBatchOperationAttempts; i++ {
+		descResp, err := p.DescribeBatchOperation(ctx, data_models.DescribeBatchOperationRequest{
+			Namespace:        request.Namespace,
+			BatchOperationId: request.BatchOperationId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if descResp.NotExists {
+			return &data_models.CancelBatchOperationResponse{
+				NotExists: true,
+			}, nil
+		}
+
+		batchOperation := descResp.BatchOperation
+		if batchOperation.Status != data_models.BatchOperationStatusRunning {
+			return &data_models.CancelBatchOperationResponse{
+				AlreadyClosed: true,
+			}, nil
+		}
+
+		batchOperation.Status = data_models.BatchOperationStatusCanceled
+		updateResp, err := p.UpdateBatchOperation(ctx, data_models.UpdateBatchOperationRequest{
+			BatchOperation: batchOperation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !updateResp.VersionConflict {
+			return &data_models.CancelBatchOperationResponse{}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to cancel batch operation %v after %v attempts because of conflicts",
+		request.BatchOperationId, maxCancelBatchOperationAttempts)
+}
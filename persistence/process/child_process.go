@@ -0,0 +1,101 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// startChildProcesses starts the child processes of the state decision in the same transaction of
+// completing the state execution, and links them to the state execution.
+// A child process is skipped if the process id is not allowed to reuse by its IdReusePolicy.
+func (p sqlProcessStoreImpl) startChildProcesses(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.CompleteExecuteExecutionRequest,
+	historyEventIdSequence *int32,
+) (bool, []int64, error) {
+	hasNewImmediateTask := false
+	var fireTimestamps []int64
+
+	parent := &data_models.ParentProcessJson{
+		ProcessId:          request.Prepare.Info.ProcessId,
+		ProcessExecutionId: request.ProcessExecutionId.String(),
+		StateId:            request.StateId,
+		StateIdSequence:    request.StateIdSequence,
+	}
+
+	for _, req := range request.StartChildProcesses {
+		req.Namespace = request.Prepare.Info.Namespace
+
+		startReq := data_models.StartProcessRequest{
+			Request:        req,
+			NewTaskShardId: request.TaskShardId,
+			ParentProcess:  parent,
+		}
+		if req.ProcessStartConfig != nil && req.ProcessStartConfig.GetTimeoutSeconds() > 0 {
+			startReq.TimeoutTimeUnixSeconds = time.Now().Unix() + int64(req.ProcessStartConfig.GetTimeoutSeconds())
+		}
+
+		resp, err := p.doStartProcessTx(ctx, tx, startReq)
+		if err != nil {
+			return false, nil, err
+		}
+		if resp.FailedAtWritingAppDatabase {
+			return false, nil, resp.AppDatabaseWritingError
+		}
+		if resp.AlreadyStarted {
+			p.logger.Warn("skip starting the child process that is already started",
+				tag.ProcessId(req.ProcessId), tag.StateExecutionId(request.GetStateExecutionId()))
+			continue
+		}
+
+		err = tx.InsertChildProcess(ctx, extensions.ChildProcessRow{
+			ParentProcessExecutionId: request.ProcessExecutionId,
+			ChildProcessExecutionId:  resp.ProcessExecutionId,
+			ProcessId:                req.ProcessId,
+			StateId:                  request.StateId,
+			StateIdSequence:          request.StateIdSequence,
+			StartTime:                time.Now(),
+		})
+		if err != nil {
+			return false, nil, err
+		}
+
+		err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, historyEventIdSequence,
+			data_models.HistoryEventTypeChildProcessStarted, request.StateExecutionId,
+			data_models.HistoryEventInfoJson{
+				ProcessId:          ptr.Any(req.ProcessId),
+				ProcessExecutionId: ptr.Any(resp.ProcessExecutionId.String()),
+			})
+		if err != nil {
+			return false, nil, err
+		}
+
+		if resp.HasNewImmediateTask {
+			hasNewImmediateTask = true
+		}
+		fireTimestamps = append(fireTimestamps, startReq.GetTimerTaskFireTimestamps()...)
+	}
+
+	return hasNewImmediateTask, fireTimestamps, nil
+}
+
+func toChildProcesses(rows []extensions.ChildProcessRow) []data_models.ChildProcess {
+	children := make([]data_models.ChildProcess, 0, len(rows))
+	for _, row := range rows {
+		children = append(children, data_models.ChildProcess{
+			ProcessId:          row.ProcessId,
+			ProcessExecutionId: row.ChildProcessExecutionId.String(),
+			StateId:            row.StateId,
+			StateIdSequence:    row.StateIdSequence,
+			StartTimestamp:     row.StartTime.Unix(),
+		})
+	}
+	return children
+}
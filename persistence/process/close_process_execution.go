@@ -0,0 +1,99 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+type closeProcessExecutionRequest struct {
+	Namespace   string
+	ProcessId   string
+	ProcessType string
+	Status      data_models.ProcessExecutionStatus
+	// CloseResult is recorded with the close timestamp set
+	CloseResult data_models.ProcessCloseResultJson
+	TaskShardId int32
+}
+
+// closeProcessExecution does the bookkeeping shared by every path closing a running process execution.
+// It sets the status and close result of the row and records the close history event, then adds the visibility task,
+// schedules the deletion after the retention, and notifies the completion waiters and callbacks.
+// The caller must write the row with UpdateProcessExecution afterwards.
+// It always adds new immediate tasks, at least the visibility task.
+func (p sqlProcessStoreImpl) closeProcessExecution(
+	ctx context.Context, tx extensions.SQLTransaction,
+	prcRow *extensions.ProcessExecutionRowForUpdate, request closeProcessExecutionRequest,
+) error {
+	closeResult := request.CloseResult
+	closeResult.CloseTimestamp = time.Now().Unix()
+	closeRecord := closeResult.ProcessCloseRecordJson
+
+	var err error
+	prcRow.Status = request.Status
+	prcRow.CloseResult, err = closeResult.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	eventInfo := data_models.HistoryEventInfoJson{
+		ProcessStatus: ptr.Any(request.Status.String()),
+	}
+	if closeRecord.StopReason != "" {
+		eventInfo.Reason = ptr.Any(closeRecord.StopReason)
+	}
+	if closeResult.ContinuedAsNewProcessExecutionId != "" {
+		eventInfo.ContinuedAsNewProcessExecutionId = ptr.Any(closeResult.ContinuedAsNewProcessExecutionId)
+	}
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeProcessExecutionClosed, data_models.StateExecutionId{}, eventInfo)
+	if err != nil {
+		return err
+	}
+
+	err = p.AddVisibilityTaskRecordProcessExecutionStatus(
+		ctx,
+		tx,
+		request.TaskShardId,
+		request.Namespace,
+		request.ProcessId,
+		request.ProcessType,
+		prcRow.ProcessExecutionId,
+		request.Status,
+		nil,
+		ptr.Any(closeResult.CloseTimestamp),
+		&closeRecord,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = p.addDeleteProcessExecutionTimerTask(ctx, tx, request.TaskShardId, request.Namespace, prcRow.ProcessExecutionId)
+	if err != nil {
+		return err
+	}
+
+	// the waiters of the processId and the completion callbacks are carried over to the new process execution
+	// if the process is continued as new, so they are notified when the new process execution is closed
+	if closeResult.ContinuedAsNewProcessExecutionId != "" {
+		return nil
+	}
+
+	_, err = p.notifyProcessCompletionWaiters(ctx, tx, request.TaskShardId,
+		request.Namespace, request.ProcessId, prcRow.ProcessExecutionId, request.Status)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.addCompletionCallbackTasks(ctx, tx, request.TaskShardId, prcRow.ProcessExecutionId,
+		newCompletionCallbackPayload(request.Namespace, request.ProcessId, request.ProcessType,
+			prcRow.ProcessExecutionId, request.Status, closeResult.CloseTimestamp, closeRecord.Failure))
+	return err
+}
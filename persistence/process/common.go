@@ -75,22 +75,30 @@ type (
 		StateDecision      xcapi.StateDecision
 		AppDatabaseConfig  *data_models.InternalAppDatabaseConfig
 		WorkerUrl          string
+		// ContinueAsNew is nil unless the decision continues the process as new
+		ContinueAsNew *data_models.ContinueAsNew
 
 		// for ProcessExecutionRowForUpdate
 		ProcessExecutionRowStateExecutionSequenceMaps *data_models.StateExecutionSequenceMapsJson
 		ProcessExecutionRowGracefulCompleteRequested  bool
 		ProcessExecutionRowStatus                     data_models.ProcessExecutionStatus
+		ProcessExecutionRowHistoryEventIdSequence     int32
 
 		TaskShardId int32
 	}
 
 	HandleStateDecisionResponse struct {
 		HasNewImmediateTask bool
+		// ContinuedAsNewProcessExecutionId is only set if the process is continued as new
+		ContinuedAsNewProcessExecutionId *uuid.UUID
+		// FireTimestamps are the timer tasks of the process execution started by continuing as new
+		FireTimestamps []int64
 
 		// for ProcessExecutionRowForUpdate to update
 		ProcessExecutionRowNewStateExecutionSequenceMaps *data_models.StateExecutionSequenceMapsJson
 		ProcessExecutionRowNewGracefulCompleteRequested  bool
 		ProcessExecutionRowNewStatus                     data_models.ProcessExecutionStatus
+		ProcessExecutionRowNewHistoryEventIdSequence     int32
 	}
 )
 
@@ -104,6 +112,7 @@ func (p sqlProcessStoreImpl) handleStateDecision(
 	sequenceMaps := request.ProcessExecutionRowStateExecutionSequenceMaps
 	procExecGracefulCompleteRequested := request.ProcessExecutionRowGracefulCompleteRequested
 	procExecStatus := request.ProcessExecutionRowStatus
+	historyEventIdSequence := request.ProcessExecutionRowHistoryEventIdSequence
 
 	if len(request.StateDecision.GetNextStates()) > 0 {
 		hasNewImmediateTask = true
@@ -139,6 +148,14 @@ func (p sqlProcessStoreImpl) handleStateDecision(
 			if err != nil {
 				return nil, err
 			}
+
+			err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &historyEventIdSequence,
+				data_models.HistoryEventTypeStateExecutionScheduled,
+				data_models.StateExecutionId{StateId: next.StateId, StateIdSequence: int32(stateIdSeq)},
+				data_models.HistoryEventInfoJson{})
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -149,9 +166,25 @@ func (p sqlProcessStoreImpl) handleStateDecision(
 	shouldGracefulComplete := procExecGracefulCompleteRequested && len(sequenceMaps.PendingExecutionMap) == 0
 
 	toAbortRunningAsyncStates := false
+	var continuedAsNewPrcExeId *uuid.UUID
+	var fireTimestamps []int64
 
 	threadDecision := request.StateDecision.GetThreadCloseDecision()
-	if !shouldGracefulComplete && request.StateDecision.HasThreadCloseDecision() {
+	if request.ContinueAsNew != nil && request.ProcessExecutionRowStatus == data_models.ProcessExecutionStatusRunning {
+		// continuing as new closes the current execution as completed, and starts a new execution atomically
+		toAbortRunningAsyncStates = len(sequenceMaps.PendingExecutionMap) > 0
+
+		procExecStatus = data_models.ProcessExecutionStatusCompleted
+		sequenceMaps.PendingExecutionMap = map[string]map[int]bool{}
+
+		prcExeId, timestamps, err := p.continueAsNew(ctx, tx, request)
+		if err != nil {
+			return nil, err
+		}
+		continuedAsNewPrcExeId = &prcExeId
+		fireTimestamps = timestamps
+		hasNewImmediateTask = true
+	} else if !shouldGracefulComplete && request.StateDecision.HasThreadCloseDecision() {
 		switch threadDecision.GetCloseType() {
 		case xcapi.GRACEFUL_COMPLETE_PROCESS:
 			procExecGracefulCompleteRequested = true
@@ -186,9 +219,13 @@ func (p sqlProcessStoreImpl) handleStateDecision(
 	}
 
 	return &HandleStateDecisionResponse{
-		HasNewImmediateTask: hasNewImmediateTask,
+		HasNewImmediateTask:              hasNewImmediateTask,
+		ContinuedAsNewProcessExecutionId: continuedAsNewPrcExeId,
+		FireTimestamps:                   fireTimestamps,
+
 		ProcessExecutionRowNewStateExecutionSequenceMaps: sequenceMaps,
 		ProcessExecutionRowNewGracefulCompleteRequested:  procExecGracefulCompleteRequested,
 		ProcessExecutionRowNewStatus:                     procExecStatus,
+		ProcessExecutionRowNewHistoryEventIdSequence:     historyEventIdSequence,
 	}, nil
 }
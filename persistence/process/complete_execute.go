@@ -6,8 +6,6 @@ package process
 import (
 	"context"
 	"fmt"
-	"github.com/xcherryio/xcherry/common/ptr"
-	"time"
 
 	"github.com/xcherryio/xcherry/persistence/data_models"
 
@@ -107,7 +105,26 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 			request.StateId, request.StateIdSequence, sequenceMaps, err)
 	}
 
-	// Step 3 - 2: add next states to PendingExecutionMap
+	err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeStateExecuteCompleted, request.StateExecutionId,
+		data_models.HistoryEventInfoJson{
+			StateDecision: &request.StateDecision,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3 - 2: start child processes
+
+	hasNewImmediateTask2, fireTimestamps, err := p.startChildProcesses(ctx, tx, request, &prcRow.HistoryEventIdSequence)
+	if err != nil {
+		return nil, err
+	}
+	if hasNewImmediateTask2 {
+		hasNewImmediateTask = true
+	}
+
+	// Step 3 - 3: add next states to PendingExecutionMap
 
 	resp, err := p.handleStateDecision(ctx, tx, HandleStateDecisionRequest{
 		Namespace:          request.Prepare.Info.Namespace,
@@ -117,10 +134,12 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 		StateDecision:      request.StateDecision,
 		AppDatabaseConfig:  request.AppDatabaseConfig,
 		WorkerUrl:          request.Prepare.Info.WorkerURL,
+		ContinueAsNew:      request.ContinueAsNew,
 
 		ProcessExecutionRowStateExecutionSequenceMaps: &sequenceMaps,
 		ProcessExecutionRowGracefulCompleteRequested:  prcRow.GracefulCompleteRequested,
 		ProcessExecutionRowStatus:                     prcRow.Status,
+		ProcessExecutionRowHistoryEventIdSequence:     prcRow.HistoryEventIdSequence,
 
 		TaskShardId: request.TaskShardId,
 	})
@@ -131,15 +150,44 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 	if resp.HasNewImmediateTask {
 		hasNewImmediateTask = true
 	}
+	fireTimestamps = append(fireTimestamps, resp.FireTimestamps...)
 
 	prcRow.GracefulCompleteRequested = resp.ProcessExecutionRowNewGracefulCompleteRequested
-	prcRow.Status = resp.ProcessExecutionRowNewStatus
+	prcRow.HistoryEventIdSequence = resp.ProcessExecutionRowNewHistoryEventIdSequence
 	prcRow.StateExecutionSequenceMaps, err = resp.ProcessExecutionRowNewStateExecutionSequenceMaps.ToBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	// Step 3 - 3: update process execution row
+	if prcRow.Status == data_models.ProcessExecutionStatusRunning &&
+		resp.ProcessExecutionRowNewStatus != data_models.ProcessExecutionStatusRunning {
+		closeResult := data_models.ProcessCloseResultJson{
+			ProcessCloseRecordJson: data_models.ProcessCloseRecordJson{
+				ReasonType: data_models.ProcessCloseReasonTypeStateDecision,
+			},
+		}
+		if request.StateDecision.HasThreadCloseDecision() {
+			closeResult.Output = request.StateDecision.GetThreadCloseDecision().CloseInput
+		}
+		if resp.ContinuedAsNewProcessExecutionId != nil {
+			closeResult.ReasonType = data_models.ProcessCloseReasonTypeContinuedAsNew
+			closeResult.ContinuedAsNewProcessExecutionId = resp.ContinuedAsNewProcessExecutionId.String()
+		}
+		err = p.closeProcessExecution(ctx, tx, prcRow, closeProcessExecutionRequest{
+			Namespace:   request.Prepare.Info.Namespace,
+			ProcessId:   request.Prepare.Info.ProcessId,
+			ProcessType: request.Prepare.Info.ProcessType,
+			Status:      resp.ProcessExecutionRowNewStatus,
+			CloseResult: closeResult,
+			TaskShardId: request.TaskShardId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		hasNewImmediateTask = true
+	}
+
+	// Step 3 - 4: update process execution row
 
 	err = tx.UpdateProcessExecution(ctx, *prcRow)
 	if err != nil {
@@ -148,7 +196,13 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 
 	// Step 4: publish to local queue
 
-	hasNewImmediateTask2, err := p.publishToLocalQueue(ctx, tx, request.ProcessExecutionId, prcRow.ShardId, request.PublishToLocalQueue)
+	// the messages go to the new process execution if continued as new,
+	// after the unconsumed messages of the current one are carried over
+	publishProcessExecutionId, publishShardId := request.ProcessExecutionId, prcRow.ShardId
+	if resp.ContinuedAsNewProcessExecutionId != nil {
+		publishProcessExecutionId, publishShardId = *resp.ContinuedAsNewProcessExecutionId, request.TaskShardId
+	}
+	hasNewImmediateTask2, err = p.publishToLocalQueue(ctx, tx, publishProcessExecutionId, publishShardId, request.PublishToLocalQueue)
 	if err != nil {
 		return nil, err
 	}
@@ -156,9 +210,9 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 		hasNewImmediateTask = true
 	}
 
-	// Step 5: record status if process is ending
-	if prcRow.Status != data_models.ProcessExecutionStatusRunning {
-		err := p.AddVisibilityTaskRecordProcessExecutionStatus(
+	// Step 5: upsert the search attributes
+	if len(request.UpsertSearchAttributes) > 0 {
+		err := p.AddVisibilityTaskUpsertSearchAttributes(
 			ctx,
 			tx,
 			request.TaskShardId,
@@ -166,9 +220,7 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 			request.Prepare.Info.ProcessId,
 			request.Prepare.Info.ProcessType,
 			request.ProcessExecutionId,
-			prcRow.Status,
-			nil,
-			ptr.Any(time.Now().Unix()),
+			request.UpsertSearchAttributes,
 		)
 		if err != nil {
 			return nil, err
@@ -188,5 +240,6 @@ func (p sqlProcessStoreImpl) doCompleteExecuteExecutionTx(
 	return &data_models.CompleteExecuteExecutionResponse{
 		HasNewImmediateTask: hasNewImmediateTask,
 		ProcessStatus:       prcRow.Status,
+		FireTimestamps:      fireTimestamps,
 	}, nil
 }
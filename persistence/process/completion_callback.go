@@ -0,0 +1,216 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// insertCompletionCallbacks registers the callback urls of the new process execution,
+// which are delivered by addCompletionCallbackTasks after the process execution is closed
+func insertCompletionCallbacks(
+	ctx context.Context, tx extensions.SQLTransaction, processExecutionId uuid.UUID,
+	urls []string, retryPolicy *xcapi.RetryPolicy,
+) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	var retryPolicyBytes []byte
+	if retryPolicy != nil {
+		var err error
+		retryPolicyBytes, err = json.Marshal(retryPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	for idx, url := range urls {
+		err := tx.InsertCompletionCallback(ctx, extensions.CompletionCallbackRow{
+			ProcessExecutionId: processExecutionId,
+			CallbackIndex:      int32(idx),
+			Url:                url,
+			RetryPolicy:        retryPolicyBytes,
+			Status:             data_models.CompletionCallbackStatusPending,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCompletionCallbackTasks adds the immediate tasks to post the payload to the callback urls
+// of the closed process execution. It returns true if there is any new immediate task.
+func (p sqlProcessStoreImpl) addCompletionCallbackTasks(
+	ctx context.Context, tx extensions.SQLTransaction, shardId int32,
+	processExecutionId uuid.UUID, payload data_models.CompletionCallbackPayloadJson,
+) (bool, error) {
+	rows, err := p.session.SelectCompletionCallbacks(ctx, processExecutionId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, row := range rows {
+		retryPolicy, err := data_models.BytesToRetryPolicy(row.RetryPolicy)
+		if err != nil {
+			return false, err
+		}
+
+		infoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(data_models.ImmediateTaskInfoJson{
+			CompletionCallbackInfo: &data_models.CompletionCallbackInfoJson{
+				CallbackIndex: row.CallbackIndex,
+				Url:           row.Url,
+				RetryPolicy:   retryPolicy,
+				Payload:       payload,
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+
+		err = tx.InsertImmediateTask(ctx, extensions.ImmediateTaskRowForInsert{
+			ShardId:            shardId,
+			TaskType:           data_models.ImmediateTaskTypeCompletionCallback,
+			ProcessExecutionId: processExecutionId,
+			Info:               infoBytes,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return len(rows) > 0, nil
+}
+
+func newCompletionCallbackPayload(
+	namespace, processId, processType string, processExecutionId uuid.UUID,
+	status data_models.ProcessExecutionStatus, closeTimestamp int64, failure *data_models.ProcessFailureJson,
+) data_models.CompletionCallbackPayloadJson {
+	return data_models.CompletionCallbackPayloadJson{
+		Namespace:          namespace,
+		ProcessId:          processId,
+		ProcessExecutionId: processExecutionId.String(),
+		ProcessType:        processType,
+		Status:             xcapi.ProcessStatus(status.String()),
+		CloseTimestamp:     closeTimestamp,
+		Failure:            failure,
+	}
+}
+
+func (p sqlProcessStoreImpl) RecordCompletionCallbackAttempt(
+	ctx context.Context, request data_models.RecordCompletionCallbackAttemptRequest,
+) error {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return err
+	}
+
+	err = p.doRecordCompletionCallbackAttemptTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return err
+		}
+	}
+	return err
+}
+
+func (p sqlProcessStoreImpl) doRecordCompletionCallbackAttemptTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.RecordCompletionCallbackAttemptRequest,
+) error {
+	task := request.Task
+	info := task.ImmediateTaskInfo.CompletionCallbackInfo
+	if info == nil {
+		return fmt.Errorf("completion callback info is not set")
+	}
+	if task.ImmediateTaskInfo.WorkerTaskBackoffInfo == nil {
+		return fmt.Errorf("WorkerTaskBackoffInfo cannot be nil")
+	}
+
+	row := extensions.CompletionCallbackRow{
+		ProcessExecutionId: task.ProcessExecutionId,
+		CallbackIndex:      info.CallbackIndex,
+		Status:             data_models.CompletionCallbackStatusDelivered,
+		CompletedAttempts:  task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts,
+	}
+	if !request.Delivered {
+		row.Status = data_models.CompletionCallbackStatusFailed
+		if request.NextAttemptTimestampSeconds != nil {
+			row.Status = data_models.CompletionCallbackStatusPending
+		}
+	}
+	if request.LastFailure != nil {
+		failureBytes, err := json.Marshal(request.LastFailure)
+		if err != nil {
+			return err
+		}
+		row.LastFailure = failureBytes
+	}
+
+	// the row is gone if the process execution is deleted after the retention, which is fine
+	err := tx.UpdateCompletionCallback(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if request.NextAttemptTimestampSeconds != nil {
+		timerInfo := data_models.TimerTaskInfoJson{
+			WorkerTaskBackoffInfo:  task.ImmediateTaskInfo.WorkerTaskBackoffInfo,
+			WorkerTaskType:         &task.TaskType,
+			CompletionCallbackInfo: info,
+		}
+		timerInfoBytes, err := timerInfo.ToBytes()
+		if err != nil {
+			return err
+		}
+		err = tx.InsertTimerTask(ctx, extensions.TimerTaskRowForInsert{
+			ShardId:             task.ShardId,
+			FireTimeUnixSeconds: *request.NextAttemptTimestampSeconds,
+			TaskType:            data_models.TimerTaskTypeWorkerTaskBackoff,
+			ProcessExecutionId:  task.ProcessExecutionId,
+			StateId:             task.StateId,
+			StateIdSequence:     task.StateIdSequence,
+			Info:                timerInfoBytes,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.DeleteImmediateTask(ctx, extensions.ImmediateTaskRowDeleteFilter{
+		ShardId:      task.ShardId,
+		TaskSequence: task.GetTaskSequence(),
+	})
+}
+
+func toCompletionCallbacks(rows []extensions.CompletionCallbackRow) ([]data_models.CompletionCallback, error) {
+	var callbacks []data_models.CompletionCallback
+	for _, row := range rows {
+		lastFailure, err := data_models.BytesToStateExecutionFailure(row.LastFailure)
+		if err != nil {
+			return nil, err
+		}
+		callbacks = append(callbacks, data_models.CompletionCallback{
+			Url:               row.Url,
+			Status:            row.Status,
+			CompletedAttempts: row.CompletedAttempts,
+			LastFailure:       lastFailure,
+		})
+	}
+	return callbacks, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// continueAsNew starts a new execution with the same processId in the same transaction of closing
+// the current execution. The timeout, the completion callbacks, the selected local attributes and
+// the unconsumed local queue messages are carried over to the new execution.
+// It returns the new process execution id and the fire timestamps of the new timer tasks.
+func (p sqlProcessStoreImpl) continueAsNew(
+	ctx context.Context, tx extensions.SQLTransaction, request HandleStateDecisionRequest,
+) (uuid.UUID, []int64, error) {
+	prcRow, err := tx.SelectProcessExecution(ctx, request.ProcessExecutionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := data_models.BytesToProcessExecutionInfo(prcRow.Info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	continueAsNew := request.ContinueAsNew
+	startReq := data_models.StartProcessRequest{
+		Request: xcapi.ProcessExecutionStartRequest{
+			Namespace:        request.Namespace,
+			ProcessId:        request.ProcessId,
+			ProcessType:      request.ProcessType,
+			WorkerUrl:        request.WorkerUrl,
+			StartStateId:     continueAsNew.StartStateId,
+			StartStateInput:  continueAsNew.StartStateInput,
+			StartStateConfig: continueAsNew.StartStateConfig,
+		},
+		NewTaskShardId:                  request.TaskShardId,
+		ParentProcess:                   info.ParentProcess,
+		ContinuedFromProcessExecutionId: request.ProcessExecutionId.String(),
+	}
+	if prcRow.TimeoutSeconds > 0 {
+		startReq.Request.ProcessStartConfig = &xcapi.ProcessStartConfig{
+			TimeoutSeconds: ptr.Any(prcRow.TimeoutSeconds),
+		}
+		startReq.TimeoutTimeUnixSeconds = time.Now().Unix() + int64(prcRow.TimeoutSeconds)
+	}
+
+	callbackRows, err := p.session.SelectCompletionCallbacks(ctx, request.ProcessExecutionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range callbackRows {
+		startReq.CompletionCallbackUrls = append(startReq.CompletionCallbackUrls, row.Url)
+		startReq.CompletionCallbackRetryPolicy, err = data_models.BytesToRetryPolicy(row.RetryPolicy)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	_, prcExeId, err := p.updateLatestAndInsertNewProcessExecution(ctx, tx, startReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if startReq.TimeoutTimeUnixSeconds != 0 {
+		err = tx.InsertTimerTask(ctx, extensions.TimerTaskRowForInsert{
+			ShardId:             request.TaskShardId,
+			FireTimeUnixSeconds: startReq.TimeoutTimeUnixSeconds,
+			TaskType:            data_models.TimerTaskTypeProcessTimeout,
+			ProcessExecutionId:  prcExeId,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(continueAsNew.LocalAttributeKeys) > 0 {
+		// copied in the transaction, so that the local attributes written by the same state execution are included
+		err = tx.CopyLocalAttributes(ctx, request.ProcessExecutionId, prcExeId, continueAsNew.LocalAttributeKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = p.carryOverUnconsumedLocalQueueMessages(
+		ctx, tx, request.ProcessExecutionId, prcRow.StateExecutionLocalQueues, prcExeId, request.TaskShardId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return prcExeId, startReq.GetTimerTaskFireTimestamps(), nil
+}
+
+// carryOverUnconsumedLocalQueueMessages publishes the unconsumed messages of the closing process execution
+// to the new process execution, with the same dedupIds and in the same order of each queue
+func (p sqlProcessStoreImpl) carryOverUnconsumedLocalQueueMessages(
+	ctx context.Context, tx extensions.SQLTransaction, fromProcessExecutionId uuid.UUID, localQueuesBytes []byte,
+	toProcessExecutionId uuid.UUID, shardId int32,
+) error {
+	localQueues, err := data_models.NewStateExecutionLocalQueuesFromBytes(localQueuesBytes)
+	if err != nil {
+		return err
+	}
+
+	var queueNames []string
+	for queueName := range localQueues.UnconsumedLocalQueueMessages {
+		queueNames = append(queueNames, queueName)
+	}
+	sort.Strings(queueNames)
+
+	var localQueueMessageInfo []data_models.LocalQueueMessageInfoJson
+	for _, queueName := range queueNames {
+		messages := localQueues.UnconsumedLocalQueueMessages[queueName]
+		dedupIdToLocalQueueMessageMap, err := p.getDedupIdToLocalQueueMessageMap(ctx, fromProcessExecutionId, messages)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			row, ok := dedupIdToLocalQueueMessageMap[message.DedupId]
+			if !ok {
+				return fmt.Errorf("the unconsumed local queue message %v is not found, maybe data is corrupted", message.DedupId)
+			}
+
+			_, err = tx.InsertLocalQueueMessage(ctx, extensions.LocalQueueMessageRow{
+				ProcessExecutionId: toProcessExecutionId,
+				QueueName:          queueName,
+				DedupId:            row.DedupId,
+				Payload:            row.Payload,
+			})
+			if err != nil {
+				return err
+			}
+
+			localQueueMessageInfo = append(localQueueMessageInfo, data_models.LocalQueueMessageInfoJson{
+				QueueName: queueName,
+				DedupId:   row.DedupId,
+			})
+		}
+	}
+
+	if len(localQueueMessageInfo) == 0 {
+		return nil
+	}
+
+	taskInfoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(
+		data_models.ImmediateTaskInfoJson{
+			LocalQueueMessageInfo: localQueueMessageInfo,
+		})
+	if err != nil {
+		return err
+	}
+
+	return tx.InsertImmediateTask(ctx, extensions.ImmediateTaskRowForInsert{
+		ShardId:            shardId,
+		TaskType:           data_models.ImmediateTaskTypeNewLocalQueueMessages,
+		ProcessExecutionId: toProcessExecutionId,
+		Info:               taskInfoBytes,
+	})
+}
@@ -45,7 +45,8 @@ func (p sqlProcessStoreImpl) doConvertTimerTaskToImmediateTaskTx(
 	currentTask := request.Task
 	timerInfo := currentTask.TimerTaskInfo
 	taskInfoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(data_models.ImmediateTaskInfoJson{
-		WorkerTaskBackoffInfo: timerInfo.WorkerTaskBackoffInfo,
+		WorkerTaskBackoffInfo:  timerInfo.WorkerTaskBackoffInfo,
+		CompletionCallbackInfo: timerInfo.CompletionCallbackInfo,
 	})
 	if err != nil {
 		return err
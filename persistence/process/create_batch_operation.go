@@ -0,0 +1,43 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"time"
+)
+
+func (p sqlProcessStoreImpl) CreateBatchOperation(
+	ctx context.Context, request data_models.CreateBatchOperationRequest,
+) (*data_models.CreateBatchOperationResponse, error) {
+	infoBytes, err := request.Info.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = p.session.InsertBatchOperation(ctx, extensions.BatchOperationRow{
+		Namespace:        request.Namespace,
+		BatchOperationId: request.BatchOperationId,
+		Status:           data_models.BatchOperationStatusRunning,
+		Info:             infoBytes,
+		Rps:              request.Rps,
+		// so that any instance can pick it up immediately
+		LeaseExpireTime: now,
+		CreateTime:      now,
+		LastUpdateTime:  now,
+	})
+	if err != nil {
+		if p.session.IsDupEntryError(err) {
+			return &data_models.CreateBatchOperationResponse{
+				AlreadyExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	return &data_models.CreateBatchOperationResponse{}, nil
+}
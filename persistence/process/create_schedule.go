@@ -0,0 +1,87 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) CreateSchedule(
+	ctx context.Context, request data_models.CreateScheduleRequest,
+) (*data_models.CreateScheduleResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doCreateScheduleTx(ctx, tx, request)
+	if err != nil || resp.AlreadyExists {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doCreateScheduleTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.CreateScheduleRequest,
+) (*data_models.CreateScheduleResponse, error) {
+	infoBytes, err := request.Info.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	nextFireTimestamp := int64(0)
+	if !request.Paused {
+		nextFireTimestamp, err = request.Info.Spec.GetNextFireTimestamp(now.Unix())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.InsertSchedule(ctx, extensions.ScheduleRow{
+		Namespace:               request.Namespace,
+		ScheduleId:              request.ScheduleId,
+		ShardId:                 request.ShardId,
+		Paused:                  request.Paused,
+		Info:                    infoBytes,
+		NextFireTimeUnixSeconds: nextFireTimestamp,
+		CreateTime:              now,
+		LastUpdateTime:          now,
+	})
+	if err != nil {
+		if p.session.IsDupEntryError(err) {
+			return &data_models.CreateScheduleResponse{
+				AlreadyExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	if nextFireTimestamp > 0 {
+		// a new schedule starts with version 1
+		err = p.addFireScheduleTimerTask(
+			ctx, tx, request.ShardId, request.Namespace, request.ScheduleId, 1, nextFireTimestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &data_models.CreateScheduleResponse{
+		NextFireTimestamp: nextFireTimestamp,
+	}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// addDeleteProcessExecutionTimerTask schedules the deletion of a closed process execution after the retention
+// of the namespace, it's a noop if the namespace keeps the closed process executions forever
+func (p sqlProcessStoreImpl) addDeleteProcessExecutionTimerTask(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	namespace string,
+	processExecutionId uuid.UUID,
+) error {
+	retention := p.retention.GetRetention(namespace)
+	if retention <= 0 {
+		return nil
+	}
+	return tx.InsertTimerTask(ctx, extensions.TimerTaskRowForInsert{
+		ShardId:             shardId,
+		FireTimeUnixSeconds: time.Now().Add(retention).Unix(),
+		TaskType:            data_models.TimerTaskTypeDeleteProcessExecution,
+		ProcessExecutionId:  processExecutionId,
+	})
+}
+
+func (p sqlProcessStoreImpl) ProcessTimerTaskForDeleteProcessExecution(
+	ctx context.Context, request data_models.ProcessTimerTaskRequest,
+) (*data_models.ProcessTimerTaskResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doProcessTimerTaskForDeleteProcessExecutionTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doProcessTimerTaskForDeleteProcessExecutionTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ProcessTimerTaskRequest,
+) (*data_models.ProcessTimerTaskResponse, error) {
+	task := request.Task
+	hasNewImmediateTask := false
+
+	prcRow, err := tx.SelectProcessExecution(ctx, task.ProcessExecutionId)
+	if err != nil && !p.session.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	// skip if already deleted, or still running which should not happen as a closed process is never reopened
+	if err == nil && prcRow.Status != data_models.ProcessExecutionStatusRunning {
+		prcInfo, err := data_models.BytesToProcessExecutionInfo(prcRow.Info)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.DeleteLatestProcessExecution(ctx, prcRow.Namespace, prcRow.ProcessId, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteAsyncStateExecutions(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteLocalQueueMessages(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteLocalAttributes(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteHistoryEvents(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteChildProcesses(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteProcessCompletionWaitersOfWaiter(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.DeleteParkedImmediateTasks(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteProcessRequests(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteCompletionCallbacks(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.DeleteProcessExecution(ctx, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.AddVisibilityTaskDeleteProcessExecution(
+			ctx, tx, task.ShardId, prcRow.Namespace, prcRow.ProcessId, prcInfo.ProcessType, prcRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		hasNewImmediateTask = true
+	}
+
+	err = tx.DeleteTimerTask(ctx, extensions.TimerTaskRowDeleteFilter{
+		ShardId:              task.ShardId,
+		FireTimeUnixSeconds:  task.FireTimestampSeconds,
+		TaskSequence:         *task.TaskSequence,
+		OptionalPartitionKey: task.OptionalPartitionKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ProcessTimerTaskResponse{HasNewImmediateTask: hasNewImmediateTask}, nil
+}
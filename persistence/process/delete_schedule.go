@@ -0,0 +1,57 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) DeleteSchedule(
+	ctx context.Context, request data_models.DeleteScheduleRequest,
+) (*data_models.DeleteScheduleResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doDeleteScheduleTx(ctx, tx, request)
+	if err != nil || resp.NotExists {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doDeleteScheduleTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.DeleteScheduleRequest,
+) (*data_models.DeleteScheduleResponse, error) {
+	_, err := tx.SelectScheduleForUpdate(ctx, request.Namespace, request.ScheduleId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.DeleteScheduleResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	// the pending timer task will find the schedule not exists, and be deleted without firing
+	err = tx.DeleteSchedule(ctx, request.Namespace, request.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+	return &data_models.DeleteScheduleResponse{}, nil
+}
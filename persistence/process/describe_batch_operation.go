@@ -0,0 +1,58 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) DescribeBatchOperation(
+	ctx context.Context, request data_models.DescribeBatchOperationRequest,
+) (*data_models.DescribeBatchOperationResponse, error) {
+	row, err := p.session.SelectBatchOperation(ctx, request.Namespace, request.BatchOperationId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.DescribeBatchOperationResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	batchOperation, err := toBatchOperation(*row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.DescribeBatchOperationResponse{
+		BatchOperation: *batchOperation,
+	}, nil
+}
+
+func toBatchOperation(row extensions.BatchOperationRow) (*data_models.BatchOperation, error) {
+	info, err := data_models.BytesToBatchOperationInfo(row.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.BatchOperation{
+		Namespace:            row.Namespace,
+		BatchOperationId:     row.BatchOperationId,
+		Status:               row.Status,
+		Info:                 info,
+		Rps:                  row.Rps,
+		NextPageToken:        row.NextPageToken,
+		SucceededCount:       row.SucceededCount,
+		FailedCount:          row.FailedCount,
+		SkippedCount:         row.SkippedCount,
+		ErrorMessage:         row.ErrorMessage,
+		Owner:                row.Owner,
+		LeaseExpireTimestamp: row.LeaseExpireTime.Unix(),
+		Version:              row.PreviousVersion,
+		CreateTimestamp:      row.CreateTime.Unix(),
+		LastUpdateTimestamp:  row.LastUpdateTime.Unix(),
+	}, nil
+}
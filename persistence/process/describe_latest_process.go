@@ -6,6 +6,7 @@ package process
 import (
 	"context"
 	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/extensions"
 	"github.com/xcherryio/xcherry/persistence/data_models"
 
 	"github.com/xcherryio/xcherry/common/ptr"
@@ -24,12 +25,44 @@ func (p sqlProcessStoreImpl) DescribeLatestProcess(
 		return nil, err
 	}
 
+	return p.describeProcessExecution(ctx, row)
+}
+
+func (p sqlProcessStoreImpl) DescribeProcessExecution(
+	ctx context.Context, request data_models.DescribeProcessExecutionRequest,
+) (*data_models.DescribeProcessExecutionResponse, error) {
+	row, err := p.session.SelectProcessExecution(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.DescribeProcessExecutionResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+	if row.Namespace != request.Namespace {
+		return &data_models.DescribeProcessExecutionResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	return p.describeProcessExecution(ctx, row)
+}
+
+func (p sqlProcessStoreImpl) describeProcessExecution(
+	ctx context.Context, row *extensions.ProcessExecutionRow,
+) (*data_models.DescribeLatestProcessResponse, error) {
 	info, err := data_models.BytesToProcessExecutionInfo(row.Info)
 	if err != nil {
 		return nil, err
 	}
 
-	return &data_models.DescribeLatestProcessResponse{
+	closeResult, err := data_models.BytesToProcessCloseResult(row.CloseResult)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &data_models.DescribeLatestProcessResponse{
 		Response: &xcapi.ProcessExecutionDescribeResponse{
 			ProcessExecutionId: ptr.Any(row.ProcessExecutionId.String()),
 			ProcessType:        &info.ProcessType,
@@ -37,5 +70,33 @@ func (p sqlProcessStoreImpl) DescribeLatestProcess(
 			StartTimestamp:     ptr.Any(int32(row.StartTime.Unix())),
 			Status:             xcapi.ProcessStatus(row.Status.String()).Ptr(),
 		},
-	}, nil
+		ProcessId:      row.ProcessId,
+		CloseTimestamp: closeResult.CloseTimestamp,
+	}
+	if info.DelayedStartTimestamp != nil {
+		resp.DelayedStartTimestamp = *info.DelayedStartTimestamp
+	}
+	resp.ParentProcess = info.ParentProcess
+	resp.ContinuedFromProcessExecutionId = info.ContinuedFromProcessExecutionId
+	resp.ContinuedAsNewProcessExecutionId = closeResult.ContinuedAsNewProcessExecutionId
+	if len(row.CloseResult) > 0 {
+		resp.CloseRecord = &closeResult.ProcessCloseRecordJson
+	}
+	resp.Paused = row.Paused
+
+	childRows, err := p.session.SelectChildProcesses(ctx, row.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	resp.ChildProcesses = toChildProcesses(childRows)
+
+	callbackRows, err := p.session.SelectCompletionCallbacks(ctx, row.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	resp.CompletionCallbacks, err = toCompletionCallbacks(callbackRows)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
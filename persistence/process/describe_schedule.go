@@ -0,0 +1,33 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) DescribeSchedule(
+	ctx context.Context, request data_models.DescribeScheduleRequest,
+) (*data_models.DescribeScheduleResponse, error) {
+	row, err := p.session.SelectSchedule(ctx, request.Namespace, request.ScheduleId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.DescribeScheduleResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	schedule, err := toSchedule(*row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.DescribeScheduleResponse{
+		Schedule: *schedule,
+	}, nil
+}
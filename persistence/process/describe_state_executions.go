@@ -0,0 +1,82 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) DescribeStateExecutions(
+	ctx context.Context, request data_models.DescribeStateExecutionsRequest,
+) (*data_models.DescribeStateExecutionsResponse, error) {
+	prcRow, err := p.selectProcessExecutionByIdOrLatest(ctx, request.Namespace, request.ProcessId, request.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	if prcRow == nil {
+		return &data_models.DescribeStateExecutionsResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	sequenceMaps, err := data_models.NewStateExecutionSequenceMapsFromBytes(prcRow.StateExecutionSequenceMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	stateRows, err := p.session.SelectAsyncStateExecutions(ctx, prcRow.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var stateExecutions []data_models.StateExecutionDescription
+	for _, stateRow := range stateRows {
+		pending := sequenceMaps.PendingExecutionMap[stateRow.StateId][int(stateRow.StateIdSequence)]
+		if request.PendingOnly && !pending {
+			continue
+		}
+
+		commands, err := data_models.BytesToCommandRequest(stateRow.WaitUntilCommands)
+		if err != nil {
+			return nil, err
+		}
+
+		var commandResults data_models.CommandResultsJson
+		if len(stateRow.WaitUntilCommandResults) > 0 {
+			commandResults, err = data_models.BytesToCommandResultsJson(stateRow.WaitUntilCommandResults)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		lastFailure, err := data_models.BytesToStateExecutionFailure(stateRow.LastFailure)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := data_models.BytesToAsyncStateExecutionInfo(stateRow.Info)
+		if err != nil {
+			return nil, err
+		}
+
+		stateExecutions = append(stateExecutions, data_models.StateExecutionDescription{
+			StateExecutionId: data_models.StateExecutionId{
+				StateId:         stateRow.StateId,
+				StateIdSequence: stateRow.StateIdSequence,
+			},
+			Status:                  stateRow.Status,
+			Pending:                 pending,
+			WaitUntilCommands:       commands,
+			WaitUntilCommandResults: commandResults,
+			LastFailure:             lastFailure,
+			Info:                    info,
+		})
+	}
+
+	return &data_models.DescribeStateExecutionsResponse{
+		ProcessExecutionId: prcRow.ProcessExecutionId,
+		StateExecutions:    stateExecutions,
+	}, nil
+}
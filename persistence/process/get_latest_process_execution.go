@@ -6,6 +6,7 @@ package process
 import (
 	"context"
 
+	"github.com/xcherryio/xcherry/extensions"
 	"github.com/xcherryio/xcherry/persistence/data_models"
 )
 
@@ -22,12 +23,49 @@ func (p sqlProcessStoreImpl) GetLatestProcessExecution(
 		return nil, err
 	}
 
+	return toGetProcessExecutionResponse(row)
+}
+
+func (p sqlProcessStoreImpl) GetProcessExecution(
+	ctx context.Context, request data_models.GetProcessExecutionRequest,
+) (*data_models.GetProcessExecutionResponse, error) {
+	row, err := p.session.SelectProcessExecution(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.GetProcessExecutionResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+	if row.Namespace != request.Namespace {
+		return &data_models.GetProcessExecutionResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	return toGetProcessExecutionResponse(row)
+}
+
+func toGetProcessExecutionResponse(
+	row *extensions.ProcessExecutionRow,
+) (*data_models.GetLatestProcessExecutionResponse, error) {
 	info, err := data_models.BytesToProcessExecutionInfo(row.Info)
 	if err != nil {
 		return nil, err
 	}
 
+	var closeResult *data_models.ProcessCloseResultJson
+	if row.Status != data_models.ProcessExecutionStatusUndefined && row.Status != data_models.ProcessExecutionStatusRunning {
+		result, err := data_models.BytesToProcessCloseResult(row.CloseResult)
+		if err != nil {
+			return nil, err
+		}
+		closeResult = &result
+	}
+
 	return &data_models.GetLatestProcessExecutionResponse{
+		ProcessId:          row.ProcessId,
 		ProcessExecutionId: row.ProcessExecutionId,
 		ShardId:            row.ShardId,
 		Status:             row.Status,
@@ -36,5 +74,6 @@ func (p sqlProcessStoreImpl) GetLatestProcessExecution(
 
 		ProcessType: info.ProcessType,
 		WorkerUrl:   info.WorkerURL,
+		CloseResult: closeResult,
 	}, nil
 }
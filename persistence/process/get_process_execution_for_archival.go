@@ -0,0 +1,161 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// archivalHistoryPageSize is the number of history events loaded at a time for the archival record
+const archivalHistoryPageSize = 1000
+
+func (p sqlProcessStoreImpl) GetProcessExecutionForArchival(
+	ctx context.Context, request data_models.GetProcessExecutionForArchivalRequest,
+) (*data_models.GetProcessExecutionForArchivalResponse, error) {
+	prcRow, err := p.session.SelectProcessExecution(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.GetProcessExecutionForArchivalResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	prcInfo, err := data_models.BytesToProcessExecutionInfo(prcRow.Info)
+	if err != nil {
+		return nil, err
+	}
+	closeResult, err := data_models.BytesToProcessCloseResult(prcRow.CloseResult)
+	if err != nil {
+		return nil, err
+	}
+
+	record := data_models.ArchivedProcessExecutionJson{
+		Namespace:          prcRow.Namespace,
+		ProcessId:          prcRow.ProcessId,
+		ProcessExecutionId: prcRow.ProcessExecutionId.String(),
+		ProcessType:        prcInfo.ProcessType,
+		WorkerUrl:          prcInfo.WorkerURL,
+		Status:             xcapi.ProcessStatus(prcRow.Status.String()),
+		StartTimestamp:     prcRow.StartTime.Unix(),
+		TimeoutSeconds:     prcRow.TimeoutSeconds,
+		CloseTimestamp:     closeResult.CloseTimestamp,
+		CloseResult:        closeResult,
+		StateExecutions:    []data_models.ArchivedStateExecutionJson{},
+		LocalAttributes:    []xcapi.KeyValue{},
+		ConsumedMessages:   []data_models.ArchivedLocalQueueMessageJson{},
+		HistoryEvents:      []data_models.ArchivedHistoryEventJson{},
+	}
+
+	stateRows, err := p.session.SelectAsyncStateExecutions(ctx, prcRow.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, stateRow := range stateRows {
+		var input xcapi.EncodedObject
+		if len(stateRow.Input) > 0 {
+			input, err = data_models.BytesToEncodedObject(stateRow.Input)
+			if err != nil {
+				return nil, err
+			}
+		}
+		lastFailure, err := data_models.BytesToStateExecutionFailure(stateRow.LastFailure)
+		if err != nil {
+			return nil, err
+		}
+		info, err := data_models.BytesToAsyncStateExecutionInfo(stateRow.Info)
+		if err != nil {
+			return nil, err
+		}
+		record.StateExecutions = append(record.StateExecutions, data_models.ArchivedStateExecutionJson{
+			StateId:         stateRow.StateId,
+			StateIdSequence: stateRow.StateIdSequence,
+			Status:          stateRow.Status.String(),
+			Input:           input,
+			LastFailure:     lastFailure,
+			Info:            info,
+		})
+	}
+
+	attributeRows, err := p.session.SelectAllLocalAttributes(ctx, prcRow.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range attributeRows {
+		value, err := data_models.BytesToEncodedObject(row.Value)
+		if err != nil {
+			return nil, err
+		}
+		record.LocalAttributes = append(record.LocalAttributes, xcapi.KeyValue{
+			Key:   row.Key,
+			Value: value,
+		})
+	}
+
+	localQueues, err := data_models.NewStateExecutionLocalQueuesFromBytes(prcRow.StateExecutionLocalQueues)
+	if err != nil {
+		return nil, err
+	}
+	unconsumedDedupIds := map[string]bool{}
+	for _, messages := range localQueues.UnconsumedLocalQueueMessages {
+		for _, message := range messages {
+			unconsumedDedupIds[message.DedupId] = true
+		}
+	}
+
+	messageRows, err := p.session.SelectAllLocalQueueMessages(ctx, prcRow.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range messageRows {
+		dedupId := row.DedupId.String()
+		if unconsumedDedupIds[dedupId] {
+			continue
+		}
+		payload, err := data_models.BytesToEncodedObject(row.Payload)
+		if err != nil {
+			return nil, err
+		}
+		record.ConsumedMessages = append(record.ConsumedMessages, data_models.ArchivedLocalQueueMessageJson{
+			QueueName: row.QueueName,
+			DedupId:   dedupId,
+			Payload:   payload,
+		})
+	}
+
+	minEventIdExclusive := int32(0)
+	for {
+		eventRows, err := p.session.SelectHistoryEvents(
+			ctx, prcRow.ProcessExecutionId, minEventIdExclusive, archivalHistoryPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range eventRows {
+			info, err := data_models.BytesToHistoryEventInfo(row.Info)
+			if err != nil {
+				return nil, err
+			}
+			record.HistoryEvents = append(record.HistoryEvents, data_models.ArchivedHistoryEventJson{
+				EventId:         row.EventId,
+				EventType:       row.EventType.String(),
+				StateId:         row.StateId,
+				StateIdSequence: row.StateIdSequence,
+				CreateTimestamp: row.CreateTime.Unix(),
+				Info:            info,
+			})
+			minEventIdExclusive = row.EventId
+		}
+		if len(eventRows) < archivalHistoryPageSize {
+			break
+		}
+	}
+
+	return &data_models.GetProcessExecutionForArchivalResponse{
+		Record: record,
+	}, nil
+}
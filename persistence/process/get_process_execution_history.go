@@ -0,0 +1,53 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) GetProcessExecutionHistory(
+	ctx context.Context, request data_models.GetProcessExecutionHistoryRequest,
+) (*data_models.GetProcessExecutionHistoryResponse, error) {
+	prcRow, err := p.selectProcessExecutionByIdOrLatest(ctx, request.Namespace, request.ProcessId, request.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	if prcRow == nil {
+		return &data_models.GetProcessExecutionHistoryResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	rows, err := p.session.SelectHistoryEvents(ctx, prcRow.ProcessExecutionId, request.MinEventIdExclusive, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []data_models.HistoryEvent
+	for _, row := range rows {
+		info, err := data_models.BytesToHistoryEventInfo(row.Info)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, data_models.HistoryEvent{
+			EventId:   row.EventId,
+			EventType: row.EventType,
+			StateExecutionId: data_models.StateExecutionId{
+				StateId:         row.StateId,
+				StateIdSequence: row.StateIdSequence,
+			},
+			CreateTimestamp: row.CreateTime.Unix(),
+			Info:            info,
+		})
+	}
+
+	return &data_models.GetProcessExecutionHistoryResponse{
+		ProcessExecutionId: prcRow.ProcessExecutionId,
+		Events:             events,
+		HasMore:            len(rows) == int(request.PageSize),
+	}, nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) GetRunningBatchOperations(
+	ctx context.Context, request data_models.GetRunningBatchOperationsRequest,
+) (*data_models.GetRunningBatchOperationsResponse, error) {
+	rows, err := p.session.SelectBatchOperationsByStatus(ctx, data_models.BatchOperationStatusRunning, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchOperations []data_models.BatchOperation
+	for _, row := range rows {
+		batchOperation, err := toBatchOperation(row)
+		if err != nil {
+			return nil, err
+		}
+		batchOperations = append(batchOperations, *batchOperation)
+	}
+
+	return &data_models.GetRunningBatchOperationsResponse{
+		BatchOperations: batchOperations,
+	}, nil
+}
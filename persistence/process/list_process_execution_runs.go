@@ -0,0 +1,85 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) ListProcessExecutionRuns(
+	ctx context.Context, request data_models.ListProcessExecutionRunsRequest,
+) (*data_models.ListProcessExecutionRunsResponse, error) {
+	filter := extensions.ProcessExecutionRunsSelectFilter{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+		PageSize:  request.PageSize,
+	}
+	if request.NextPageToken != nil {
+		paginationToken, err := data_models.ParsePaginationTokenFromString(*request.NextPageToken)
+		if err != nil {
+			return nil, err
+		}
+		if paginationToken.LastSortValue == nil {
+			return nil, fmt.Errorf("invalid next page token of the process execution runs")
+		}
+		lastStartTimeMicro, err := strconv.ParseInt(*paginationToken.LastSortValue, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next page token of the process execution runs: %w", err)
+		}
+		filter.LastStartTime = ptr.Any(time.UnixMicro(lastStartTimeMicro))
+		filter.LastProcessExecutionIdString = paginationToken.LastProcessExecutionId
+	}
+
+	rows, err := p.session.SelectProcessExecutionRuns(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	latestProcessExecutionId := ""
+	latestRow, err := p.session.SelectLatestProcessExecution(ctx, request.Namespace, request.ProcessId)
+	if err != nil && !p.session.IsNotFoundError(err) {
+		return nil, err
+	}
+	if err == nil {
+		latestProcessExecutionId = latestRow.ProcessExecutionId.String()
+	}
+
+	runs := []data_models.ProcessExecutionRun{}
+	for _, row := range rows {
+		run := data_models.ProcessExecutionRun{
+			ProcessExecutionId: row.ProcessExecutionId,
+			ProcessType:        row.ProcessType,
+			Status:             row.Status,
+			StartTimestamp:     row.StartTime.Unix(),
+			Latest:             row.ProcessExecutionId.String() == latestProcessExecutionId,
+		}
+		if row.CloseTimestamp != nil {
+			run.CloseTimestamp = *row.CloseTimestamp
+		}
+		runs = append(runs, run)
+	}
+
+	resp := &data_models.ListProcessExecutionRunsResponse{
+		Runs: runs,
+	}
+	if len(rows) == int(request.PageSize) {
+		// the start time is in microseconds, the same precision as the database
+		lastRow := rows[len(rows)-1]
+		paginationToken := data_models.NewPaginationToken(lastRow.ProcessExecutionId.String(), lastRow.StartTime.Unix())
+		paginationToken.LastSortValue = ptr.Any(strconv.FormatInt(lastRow.StartTime.UnixMicro(), 10))
+		paginationTokenString, err := paginationToken.String()
+		if err != nil {
+			return nil, err
+		}
+		resp.NextPageToken = &paginationTokenString
+	}
+	return resp, nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) GetLocalAttributes(
+	ctx context.Context, request data_models.GetLocalAttributesRequest,
+) (*data_models.GetLocalAttributesResponse, error) {
+	prcRow, err := p.selectProcessExecutionByIdOrLatest(
+		ctx, request.Namespace, request.ProcessId, request.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	if prcRow == nil {
+		return &data_models.GetLocalAttributesResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	var rows []extensions.LocalAttributeRow
+	if len(request.Keys) > 0 {
+		rows, err = p.session.SelectLocalAttributes(ctx, prcRow.ProcessExecutionId, request.Keys)
+	} else {
+		rows, err = p.session.SelectAllLocalAttributes(ctx, prcRow.ProcessExecutionId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []data_models.LocalAttribute{}
+	for _, row := range rows {
+		value, err := data_models.BytesToEncodedObject(row.Value)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, data_models.LocalAttribute{
+			Key:     row.Key,
+			Value:   value,
+			Version: row.Version,
+		})
+	}
+
+	return &data_models.GetLocalAttributesResponse{
+		ProcessExecutionId: prcRow.ProcessExecutionId,
+		Attributes:         attributes,
+	}, nil
+}
+
+func (p sqlProcessStoreImpl) SetLocalAttributes(
+	ctx context.Context, request data_models.SetLocalAttributesRequest,
+) (*data_models.SetLocalAttributesResponse, error) {
+	prcRow, err := p.selectProcessExecutionByIdOrLatest(
+		ctx, request.Namespace, request.ProcessId, request.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+	if prcRow == nil {
+		return &data_models.SetLocalAttributesResponse{
+			NotExists: true,
+		}, nil
+	}
+
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doSetLocalAttributesTx(ctx, tx, prcRow.ProcessExecutionId, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doSetLocalAttributesTx(
+	ctx context.Context, tx extensions.SQLTransaction, processExecutionId uuid.UUID,
+	request data_models.SetLocalAttributesRequest,
+) (*data_models.SetLocalAttributesResponse, error) {
+	// lock the process execution row, so that the local attributes cannot be written by
+	// CompleteExecuteExecution or another SetLocalAttributes between comparing and setting the versions
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, processExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			// the process execution has been deleted after the retention
+			return &data_models.SetLocalAttributesResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	var keys []string
+	for _, attribute := range request.Attributes {
+		keys = append(keys, attribute.Key)
+	}
+
+	currentRows, err := p.session.SelectLocalAttributes(ctx, processExecutionId, keys)
+	if err != nil {
+		return nil, err
+	}
+	currentVersions := map[string]int64{}
+	for _, row := range currentRows {
+		currentVersions[row.Key] = row.Version
+	}
+
+	var conflicts []data_models.LocalAttributeVersionConflict
+	for _, attribute := range request.Attributes {
+		if attribute.ExpectedVersion != nil && *attribute.ExpectedVersion != currentVersions[attribute.Key] {
+			conflicts = append(conflicts, data_models.LocalAttributeVersionConflict{
+				Key:             attribute.Key,
+				ExpectedVersion: *attribute.ExpectedVersion,
+				CurrentVersion:  currentVersions[attribute.Key],
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &data_models.SetLocalAttributesResponse{
+			ProcessExecutionId: processExecutionId,
+			VersionConflicts:   conflicts,
+		}, nil
+	}
+
+	var attributes []data_models.LocalAttribute
+	for _, attribute := range request.Attributes {
+		valueBytes, err := data_models.FromEncodedObjectIntoBytes(&attribute.Value)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.UpsertLocalAttribute(ctx, extensions.LocalAttributeRow{
+			ProcessExecutionId: processExecutionId,
+			Key:                attribute.Key,
+			Value:              valueBytes,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// the version is 1 for a new key, and increased by 1 for an existing key
+		currentVersions[attribute.Key]++
+		attributes = append(attributes, data_models.LocalAttribute{
+			Key:     attribute.Key,
+			Value:   attribute.Value,
+			Version: currentVersions[attribute.Key],
+		})
+	}
+
+	err = insertHistoryEvent(ctx, tx, processExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeLocalAttributesUpdated, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			Reason:             ptr.Any(request.Reason),
+			LocalAttributeKeys: keys,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.SetLocalAttributesResponse{
+		ProcessExecutionId: processExecutionId,
+		Attributes:         attributes,
+	}, nil
+}
+
+// selectProcessExecutionByIdOrLatest returns nil if the process execution doesn't exist in the namespace
+func (p sqlProcessStoreImpl) selectProcessExecutionByIdOrLatest(
+	ctx context.Context, namespace, processId string, processExecutionId *uuid.UUID,
+) (*extensions.ProcessExecutionRow, error) {
+	var prcRow *extensions.ProcessExecutionRow
+	var err error
+	if processExecutionId != nil {
+		prcRow, err = p.session.SelectProcessExecution(ctx, *processExecutionId)
+	} else {
+		prcRow, err = p.session.SelectLatestProcessExecution(ctx, namespace, processId)
+	}
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if prcRow.Namespace != namespace {
+		return nil, nil
+	}
+	return prcRow, nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) PauseProcessExecution(
+	ctx context.Context, request data_models.PauseProcessExecutionRequest,
+) (*data_models.PauseProcessExecutionResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doPauseProcessExecutionTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doPauseProcessExecutionTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.PauseProcessExecutionRequest,
+) (*data_models.PauseProcessExecutionResponse, error) {
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.PauseProcessExecutionResponse{
+				ProcessNotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	if prcRow.Status != data_models.ProcessExecutionStatusRunning {
+		return &data_models.PauseProcessExecutionResponse{
+			ProcessNotRunning: true,
+		}, nil
+	}
+
+	if prcRow.Paused {
+		return &data_models.PauseProcessExecutionResponse{
+			AlreadyPaused: true,
+		}, nil
+	}
+
+	// the waitUntil/execute tasks are parked by the immediate task processor when they are processed,
+	// while the timers and local queue messages keep being applied to the state executions
+	prcRow.Paused = true
+
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeProcessExecutionPaused, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			Reason: ptr.Any(request.Reason),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.PauseProcessExecutionResponse{}, nil
+}
+
+func (p sqlProcessStoreImpl) ResumeProcessExecution(
+	ctx context.Context, request data_models.ResumeProcessExecutionRequest,
+) (*data_models.ResumeProcessExecutionResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doResumeProcessExecutionTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doResumeProcessExecutionTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ResumeProcessExecutionRequest,
+) (*data_models.ResumeProcessExecutionResponse, error) {
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.ResumeProcessExecutionResponse{
+				ProcessNotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	if prcRow.Status != data_models.ProcessExecutionStatusRunning {
+		return &data_models.ResumeProcessExecutionResponse{
+			ProcessNotRunning: true,
+		}, nil
+	}
+
+	if !prcRow.Paused {
+		return &data_models.ResumeProcessExecutionResponse{
+			NotPaused: true,
+		}, nil
+	}
+
+	prcRow.Paused = false
+
+	// insert the parked tasks back to the immediate tasks
+	parkedRows, err := tx.DeleteParkedImmediateTasks(ctx, prcRow.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parkedRow := range parkedRows {
+		err = tx.InsertImmediateTask(ctx, extensions.ImmediateTaskRowForInsert{
+			ShardId:            prcRow.ShardId,
+			TaskType:           parkedRow.TaskType,
+			ProcessExecutionId: prcRow.ProcessExecutionId,
+			StateId:            parkedRow.StateId,
+			StateIdSequence:    parkedRow.StateIdSequence,
+			Info:               parkedRow.Info,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeProcessExecutionResumed, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			Reason: ptr.Any(request.Reason),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ResumeProcessExecutionResponse{
+		HasNewImmediateTask: len(parkedRows) > 0,
+	}, nil
+}
+
+func (p sqlProcessStoreImpl) ParkImmediateTask(
+	ctx context.Context, request data_models.ParkImmediateTaskRequest,
+) (*data_models.ParkImmediateTaskResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doParkImmediateTaskTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doParkImmediateTaskTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ParkImmediateTaskRequest,
+) (*data_models.ParkImmediateTaskResponse, error) {
+	task := request.Task
+
+	// lock the process execution row, so that the task cannot be parked after resuming
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, task.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !prcRow.Paused {
+		return &data_models.ParkImmediateTaskResponse{
+			Parked: false,
+		}, nil
+	}
+
+	infoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(task.ImmediateTaskInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.InsertParkedImmediateTask(ctx, extensions.ParkedImmediateTaskRow{
+		ProcessExecutionId: task.ProcessExecutionId,
+		StateId:            task.StateId,
+		StateIdSequence:    task.StateIdSequence,
+		TaskType:           task.TaskType,
+		Info:               infoBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.DeleteImmediateTask(ctx, extensions.ImmediateTaskRowDeleteFilter{
+		ShardId:      task.ShardId,
+		TaskSequence: task.GetTaskSequence(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ParkImmediateTaskResponse{
+		Parked: true,
+	}, nil
+}
@@ -46,12 +46,21 @@ func (p sqlProcessStoreImpl) PrepareStateExecution(
 
 	commandResults := p.prepareWaitUntilCommandResults(commandResultsJson, commandRequest)
 
+	processCompletionCommands, err := data_models.BytesToProcessCompletionCommands(stateRow.WaitUntilCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	processCompletionResults := p.prepareProcessCompletionResults(commandResultsJson, processCompletionCommands)
+
 	return &data_models.PrepareStateExecutionResponse{
-		Status:                  stateRow.Status,
-		WaitUntilCommandResults: commandResults,
-		PreviousVersion:         stateRow.PreviousVersion,
-		Info:                    info,
-		Input:                   input,
+		Status:                   stateRow.Status,
+		WaitUntilCommandResults:  commandResults,
+		ProcessCompletionResults: processCompletionResults,
+		PreviousVersion:          stateRow.PreviousVersion,
+		Info:                     info,
+		Input:                    input,
+		ProcessExecutionPaused:   stateRow.ProcessExecutionPaused,
 	}, nil
 }
 
@@ -95,3 +104,23 @@ func (p sqlProcessStoreImpl) prepareWaitUntilCommandResults(
 
 	return commandResults
 }
+
+func (p sqlProcessStoreImpl) prepareProcessCompletionResults(
+	commandResultsJson data_models.CommandResultsJson, commands []data_models.ProcessCompletionCommandJson,
+) []data_models.ProcessCompletionResultJson {
+	var results []data_models.ProcessCompletionResultJson
+
+	for idx, command := range commands {
+		result, ok := commandResultsJson.ProcessCompletionResults[idx]
+		if !ok {
+			result = data_models.ProcessCompletionResultJson{
+				Status:    xcapi.WAITING_COMMAND,
+				ProcessId: command.ProcessId,
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// waitForProcessCompletionCommands updates the results of the commands waiting for the closed processes,
+// and registers the waiters for the others, which are notified by notifyProcessCompletionWaiters
+func (p sqlProcessStoreImpl) waitForProcessCompletionCommands(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ProcessWaitUntilExecutionRequest,
+	commandResults *data_models.CommandResultsJson,
+) error {
+	namespace := request.Prepare.Info.Namespace
+
+	for idx, command := range request.ProcessCompletionCommands {
+		latestRow, err := p.session.SelectLatestProcessExecution(ctx, namespace, command.ProcessId)
+		if err != nil && !p.session.IsNotFoundError(err) {
+			return err
+		}
+
+		if err == nil {
+			// lock the process execution row, so that it cannot be closed without seeing the waiter
+			prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, latestRow.ProcessExecutionId)
+			if err != nil {
+				return err
+			}
+			if prcRow.Status != data_models.ProcessExecutionStatusRunning {
+				commandResults.ProcessCompletionResults[idx] = newProcessCompletionResult(
+					command.ProcessId, prcRow.ProcessExecutionId, prcRow.Status)
+				continue
+			}
+		}
+
+		err = tx.InsertProcessCompletionWaiter(ctx, extensions.ProcessCompletionWaiterRow{
+			Namespace:                namespace,
+			ProcessId:                command.ProcessId,
+			WaiterProcessExecutionId: request.ProcessExecutionId,
+			WaiterStateId:            request.StateId,
+			WaiterStateIdSequence:    request.StateIdSequence,
+			CommandIndex:             int32(idx),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyProcessCompletionWaiters adds the immediate tasks to deliver the status of the closed process
+// to the waiting state executions. It returns true if there is any new immediate task.
+func (p sqlProcessStoreImpl) notifyProcessCompletionWaiters(
+	ctx context.Context, tx extensions.SQLTransaction, shardId int32,
+	namespace string, processId string, processExecutionId uuid.UUID, status data_models.ProcessExecutionStatus,
+) (bool, error) {
+	waiters, err := tx.DeleteProcessCompletionWaiters(ctx, namespace, processId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, waiter := range waiters {
+		infoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(data_models.ImmediateTaskInfoJson{
+			ProcessCompletionInfo: &data_models.ProcessCompletionInfoJson{
+				CommandIndex: int(waiter.CommandIndex),
+				Result:       newProcessCompletionResult(processId, processExecutionId, status),
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+
+		err = tx.InsertImmediateTask(ctx, extensions.ImmediateTaskRowForInsert{
+			ShardId:            shardId,
+			TaskType:           data_models.ImmediateTaskTypeProcessCompletionCommand,
+			ProcessExecutionId: waiter.WaiterProcessExecutionId,
+			StateId:            waiter.WaiterStateId,
+			StateIdSequence:    waiter.WaiterStateIdSequence,
+			Info:               infoBytes,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return len(waiters) > 0, nil
+}
+
+func newProcessCompletionResult(
+	processId string, processExecutionId uuid.UUID, status data_models.ProcessExecutionStatus,
+) data_models.ProcessCompletionResultJson {
+	return data_models.ProcessCompletionResultJson{
+		Status:             xcapi.COMPLETED_COMMAND,
+		ProcessId:          processId,
+		ProcessExecutionId: ptr.Any(processExecutionId.String()),
+		ProcessStatus:      xcapi.ProcessStatus(status.String()).Ptr(),
+	}
+}
+
+func (p sqlProcessStoreImpl) ProcessProcessCompletionCommand(
+	ctx context.Context, request data_models.ProcessProcessCompletionCommandRequest,
+) (*data_models.ProcessProcessCompletionCommandResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doProcessProcessCompletionCommandTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doProcessProcessCompletionCommandTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ProcessProcessCompletionCommandRequest,
+) (*data_models.ProcessProcessCompletionCommandResponse, error) {
+	task := request.Task
+	hasNewImmediateTask := false
+
+	resp, err := p.updateProcessCompletionCommandResult(ctx, tx, task)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		hasNewImmediateTask = resp.HasNewImmediateTask
+	}
+
+	err = tx.DeleteImmediateTask(ctx, extensions.ImmediateTaskRowDeleteFilter{
+		ShardId:      task.ShardId,
+		TaskSequence: task.GetTaskSequence(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ProcessProcessCompletionCommandResponse{
+		HasNewImmediateTask: hasNewImmediateTask,
+	}, nil
+}
+
+// updateProcessCompletionCommandResult returns nil if the state execution is no longer waiting for the commands
+func (p sqlProcessStoreImpl) updateProcessCompletionCommandResult(
+	ctx context.Context, tx extensions.SQLTransaction, task data_models.ImmediateTask,
+) (*data_models.ProcessProcessCompletionCommandResponse, error) {
+	info := task.ImmediateTaskInfo.ProcessCompletionInfo
+	if info == nil {
+		p.logger.Warn("process completion info is not set", tag.ID(task.GetTaskId()))
+		return nil, nil
+	}
+
+	// Step 1: get localQueues from the process execution row
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, task.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			// the process execution has been closed and deleted after the retention
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	localQueues, err := data_models.NewStateExecutionLocalQueuesFromBytes(prcRow.StateExecutionLocalQueues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: update the state execution row
+	stateRow, err := tx.SelectAsyncStateExecutionForUpdate(ctx, extensions.AsyncStateExecutionSelectFilter{
+		ProcessExecutionId: task.ProcessExecutionId,
+		StateId:            task.StateId,
+		StateIdSequence:    task.StateIdSequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// early stop if the state is not waiting commands
+	if stateRow.Status != data_models.StateExecutionStatusWaitUntilWaiting {
+		return nil, nil
+	}
+
+	commandRequest, err := data_models.BytesToCommandRequest(stateRow.WaitUntilCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	processCompletionCommands, err := data_models.BytesToProcessCompletionCommands(stateRow.WaitUntilCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	commandResults, err := data_models.BytesToCommandResultsJson(stateRow.WaitUntilCommandResults)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := commandResults.ProcessCompletionResults[info.CommandIndex]; ok {
+		return nil, nil
+	}
+	commandResults.ProcessCompletionResults[info.CommandIndex] = info.Result
+
+	err = insertHistoryEvent(ctx, tx, task.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeWaitingProcessClosed, task.StateExecutionId,
+		data_models.HistoryEventInfoJson{
+			ProcessId:                     &info.Result.ProcessId,
+			ProcessExecutionId:            info.Result.ProcessExecutionId,
+			ProcessStatus:                 (*string)(info.Result.ProcessStatus),
+			ProcessCompletionCommandIndex: &info.CommandIndex,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	stateRow.LastFailure = nil
+
+	hasNewImmediateTask := false
+
+	if p.hasCompletedWaitUntilWaiting(commandRequest, processCompletionCommands, commandResults) {
+		hasNewImmediateTask = true
+
+		err = p.updateWhenCompletedWaitUntilWaiting(ctx, tx, task.ShardId, &localQueues, stateRow)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stateRow.WaitUntilCommandResults, err = data_models.FromCommandResultsJsonToBytes(commandResults)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateAsyncStateExecution(ctx, *stateRow)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: update process execution row
+	prcRow.StateExecutionLocalQueues, err = localQueues.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ProcessProcessCompletionCommandResponse{
+		HasNewImmediateTask: hasNewImmediateTask,
+	}, nil
+}
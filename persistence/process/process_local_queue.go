@@ -50,6 +50,15 @@ func (p sqlProcessStoreImpl) doProcessLocalQueueMessagesTx(
 		return nil, err
 	}
 
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeLocalQueueMessagesReceived, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			LocalQueueMessages: request.Messages,
+		})
+	if err != nil {
+		return nil, err
+	}
+
 	for _, message := range request.Messages {
 		assignedStateExecutionIdString, idx, consumedMessages := localQueues.AddMessageAndTryConsume(message)
 
@@ -95,6 +104,11 @@ func (p sqlProcessStoreImpl) doProcessLocalQueueMessagesTx(
 				return nil, err
 			}
 
+			err = insertHistoryEventForConsumedMessages(ctx, tx, prcRow, *stateExecutionId, consumedMessagesMap)
+			if err != nil {
+				return nil, err
+			}
+
 			stateRow.LastFailure = nil
 
 			commandRequest, err := data_models.BytesToCommandRequest(stateRow.WaitUntilCommands)
@@ -102,6 +116,11 @@ func (p sqlProcessStoreImpl) doProcessLocalQueueMessagesTx(
 				return nil, err
 			}
 
+			processCompletionCommands, err := data_models.BytesToProcessCompletionCommands(stateRow.WaitUntilCommands)
+			if err != nil {
+				return nil, err
+			}
+
 			commandResults, err := data_models.BytesToCommandResultsJson(stateRow.WaitUntilCommandResults)
 			if err != nil {
 				return nil, err
@@ -112,7 +131,7 @@ func (p sqlProcessStoreImpl) doProcessLocalQueueMessagesTx(
 				return nil, err
 			}
 
-			if p.hasCompletedWaitUntilWaiting(commandRequest, commandResults) {
+			if p.hasCompletedWaitUntilWaiting(commandRequest, processCompletionCommands, commandResults) {
 				hasNewImmediateTask = true
 
 				err = p.updateWhenCompletedWaitUntilWaiting(ctx, tx, request.TaskShardId, &localQueues, stateRow)
@@ -0,0 +1,90 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) GetProcessRequest(
+	ctx context.Context, request data_models.GetProcessRequestRequest,
+) (*data_models.GetProcessRequestResponse, error) {
+	row, err := p.session.SelectProcessRequest(ctx, request.Namespace, request.ProcessId, request.RequestId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.GetProcessRequestResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	response, err := data_models.BytesToProcessRequestResponse(row.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.GetProcessRequestResponse{
+		RequestType:        row.RequestType,
+		ProcessExecutionId: row.ProcessExecutionId,
+		Response:           response,
+	}, nil
+}
+
+// insertProcessRequest records the request id, so that the repeated requests return the same response.
+// It's a noop if the request id is empty.
+func insertProcessRequest(
+	ctx context.Context, tx extensions.SQLTransaction,
+	namespace, processId, requestId string, requestType data_models.ProcessRequestType,
+	processExecutionId uuid.UUID, response data_models.ProcessRequestResponseJson,
+) error {
+	if requestId == "" {
+		return nil
+	}
+
+	responseBytes, err := response.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	return tx.InsertProcessRequest(ctx, extensions.ProcessRequestRow{
+		Namespace:          namespace,
+		ProcessId:          processId,
+		RequestId:          requestId,
+		RequestType:        requestType,
+		ProcessExecutionId: processExecutionId,
+		Response:           responseBytes,
+		CreateTime:         time.Now(),
+	})
+}
+
+// getConcurrentProcessRequest returns the request recorded by a concurrent request with the same request id,
+// if the error is caused by recording the request id again. Both requests miss the check before the transaction,
+// and the primary key of the request id lets only one of them commit.
+// It must be called after the transaction is rolled back, and returns nil if it's not the case.
+func (p sqlProcessStoreImpl) getConcurrentProcessRequest(
+	ctx context.Context, err error, namespace, processId, requestId string,
+) (*data_models.GetProcessRequestResponse, error) {
+	if requestId == "" || !p.session.IsDupEntryError(err) {
+		return nil, nil
+	}
+
+	resp, err := p.GetProcessRequest(ctx, data_models.GetProcessRequestRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		RequestId: requestId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.NotExists {
+		return nil, nil
+	}
+	return resp, nil
+}
@@ -52,18 +52,22 @@ func (p sqlProcessStoreImpl) doProcessTimerTaskForProcessTimeoutTx(
 ) (*data_models.ProcessTimerTaskResponse, error) {
 	p.logger.Debug("doProcessTimerTaskForProcessTimeoutTx", tag.Value(request.Task))
 	processExecution, err := tx.SelectProcessExecution(ctx, request.Task.ProcessExecutionId)
-	if err != nil {
+	if err != nil && !p.session.IsNotFoundError(err) {
 		return nil, err
 	}
 
-	if processExecution.Status == data_models.ProcessExecutionStatusRunning {
+	// the process execution may have been closed and deleted after the retention
+	if err == nil && processExecution.Status == data_models.ProcessExecutionStatusRunning {
 		resp, err := p.doStopProcessTx(
 			ctx,
 			tx,
 			processExecution.Namespace,
 			processExecution.ProcessId,
 			request.Task.ShardId,
-			data_models.ProcessExecutionStatusTimeout)
+			data_models.ProcessExecutionStatusTimeout,
+			data_models.ProcessCloseRecordJson{
+				ReasonType: data_models.ProcessCloseReasonTypeTimeout,
+			})
 		if err != nil {
 			return nil, err
 		}
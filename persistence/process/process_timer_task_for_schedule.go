@@ -0,0 +1,160 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) ProcessTimerTaskForSchedule(
+	ctx context.Context, request data_models.ProcessTimerTaskRequest,
+) (*data_models.ProcessTimerTaskResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doProcessTimerTaskForScheduleTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doProcessTimerTaskForScheduleTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ProcessTimerTaskRequest,
+) (*data_models.ProcessTimerTaskResponse, error) {
+	task := request.Task
+	resp := &data_models.ProcessTimerTaskResponse{}
+
+	scheduleInfo := task.TimerTaskInfo.ScheduleInfo
+	if scheduleInfo != nil {
+		row, err := tx.SelectScheduleForUpdate(ctx, scheduleInfo.Namespace, scheduleInfo.ScheduleId)
+		if err != nil && !p.session.IsNotFoundError(err) {
+			return nil, err
+		}
+
+		// skip the stale task if the schedule has been deleted, paused or updated since the task was created
+		if err == nil && !row.Paused && row.PreviousVersion == scheduleInfo.ScheduleVersion {
+			resp, err = p.fireSchedule(ctx, tx, *row, task.FireTimestampSeconds)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := tx.DeleteTimerTask(ctx, extensions.TimerTaskRowDeleteFilter{
+		ShardId:              task.ShardId,
+		FireTimeUnixSeconds:  task.FireTimestampSeconds,
+		TaskSequence:         *task.TaskSequence,
+		OptionalPartitionKey: task.OptionalPartitionKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// fireSchedule starts the process execution of the fire time based on the overlap policy,
+// and adds the timer task for the next fire time
+func (p sqlProcessStoreImpl) fireSchedule(
+	ctx context.Context, tx extensions.SQLTransaction, row extensions.ScheduleRow, fireTimestamp int64,
+) (*data_models.ProcessTimerTaskResponse, error) {
+	info, err := data_models.BytesToScheduleInfo(row.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &data_models.ProcessTimerTaskResponse{}
+	shouldStart := true
+	if row.LastProcessId != "" && info.OverlapPolicy != data_models.ScheduleOverlapPolicyAllowAll {
+		lastPrcRow, err := p.session.SelectLatestProcessExecution(ctx, row.Namespace, row.LastProcessId)
+		if err != nil && !p.session.IsNotFoundError(err) {
+			return nil, err
+		}
+		if err == nil && lastPrcRow.Status == data_models.ProcessExecutionStatusRunning {
+			if info.OverlapPolicy == data_models.ScheduleOverlapPolicyTerminatePrevious {
+				_, err = p.doStopProcessTx(ctx, tx, row.Namespace, row.LastProcessId, row.ShardId,
+					data_models.ProcessExecutionStatusTerminated, data_models.ProcessCloseRecordJson{
+						ReasonType: data_models.ProcessCloseReasonTypeStopRequest,
+						StopReason: fmt.Sprintf("terminated by schedule %v with the %v overlap policy",
+							row.ScheduleId, info.OverlapPolicy),
+					})
+				if err != nil {
+					return nil, err
+				}
+				// for the visibility task
+				resp.HasNewImmediateTask = true
+			} else {
+				shouldStart = false
+			}
+		}
+	}
+
+	if shouldStart {
+		startReq, startResp, err := p.startProcessForSchedule(ctx, tx, row.ShardId, row.Namespace, info, fireTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		if startResp.AlreadyStarted {
+			row.SkippedCount++
+		} else {
+			row.FiredCount++
+			row.LastProcessId = startReq.Request.ProcessId
+			resp.HasNewImmediateTask = resp.HasNewImmediateTask || startResp.HasNewImmediateTask
+			resp.ProcessExecutionId = &startResp.ProcessExecutionId
+			if startReq.TimeoutTimeUnixSeconds != 0 {
+				resp.NewTimerTaskFireTimestamps = append(resp.NewTimerTaskFireTimestamps, startReq.TimeoutTimeUnixSeconds)
+			}
+		}
+	} else {
+		row.SkippedCount++
+	}
+
+	// the missed fire times are not caught up if the timer task fires late, use the backfill for them instead
+	now := time.Now()
+	nextAfter := fireTimestamp
+	if now.Unix() > nextAfter {
+		nextAfter = now.Unix()
+	}
+	row.NextFireTimeUnixSeconds, err = info.Spec.GetNextFireTimestamp(nextAfter)
+	if err != nil {
+		return nil, err
+	}
+	row.LastFireTimeUnixSeconds = fireTimestamp
+	row.LastUpdateTime = now
+
+	err = tx.UpdateSchedule(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if row.NextFireTimeUnixSeconds > 0 {
+		err = p.addFireScheduleTimerTask(
+			ctx, tx, row.ShardId, row.Namespace, row.ScheduleId, row.PreviousVersion+1, row.NextFireTimeUnixSeconds)
+		if err != nil {
+			return nil, err
+		}
+		resp.NewTimerTaskFireTimestamps = append(resp.NewTimerTaskFireTimestamps, row.NextFireTimeUnixSeconds)
+	}
+
+	return resp, nil
+}
@@ -45,6 +45,17 @@ func (p sqlProcessStoreImpl) doProcessTimerTaskForTimerCommandTx(
 	// Step 1: get localQueues from the process execution row
 	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, task.ProcessExecutionId)
 	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			// the process execution has been closed and deleted after the retention
+			return &data_models.ProcessTimerTaskResponse{
+				HasNewImmediateTask: false,
+			}, tx.DeleteTimerTask(ctx, extensions.TimerTaskRowDeleteFilter{
+				ShardId:              task.ShardId,
+				FireTimeUnixSeconds:  task.FireTimestampSeconds,
+				TaskSequence:         *task.TaskSequence,
+				OptionalPartitionKey: task.OptionalPartitionKey,
+			})
+		}
 		return nil, err
 	}
 
@@ -70,6 +81,15 @@ func (p sqlProcessStoreImpl) doProcessTimerTaskForTimerCommandTx(
 		}, nil
 	}
 
+	err = insertHistoryEvent(ctx, tx, task.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeTimerFired, task.StateExecutionId,
+		data_models.HistoryEventInfoJson{
+			TimerCommandIndex: &timerCommandIndex,
+		})
+	if err != nil {
+		return nil, err
+	}
+
 	stateRow.LastFailure = nil
 
 	commandRequest, err := data_models.BytesToCommandRequest(stateRow.WaitUntilCommands)
@@ -77,6 +97,11 @@ func (p sqlProcessStoreImpl) doProcessTimerTaskForTimerCommandTx(
 		return nil, err
 	}
 
+	processCompletionCommands, err := data_models.BytesToProcessCompletionCommands(stateRow.WaitUntilCommands)
+	if err != nil {
+		return nil, err
+	}
+
 	commandResults, err := data_models.BytesToCommandResultsJson(stateRow.WaitUntilCommandResults)
 	if err != nil {
 		return nil, err
@@ -86,7 +111,7 @@ func (p sqlProcessStoreImpl) doProcessTimerTaskForTimerCommandTx(
 
 	hasNewImmediateTask := false
 
-	if p.hasCompletedWaitUntilWaiting(commandRequest, commandResults) {
+	if p.hasCompletedWaitUntilWaiting(commandRequest, processCompletionCommands, commandResults) {
 		hasNewImmediateTask = true
 
 		err = p.updateWhenCompletedWaitUntilWaiting(ctx, tx, task.ShardId, &localQueues, stateRow)
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) PublishToLocalQueueWithStart(
+	ctx context.Context, request data_models.PublishToLocalQueueWithStartRequest,
+) (*data_models.PublishToLocalQueueWithStartResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doPublishToLocalQueueWithStartTx(ctx, tx, request)
+	if err != nil || resp.AlreadyStarted || resp.FailedAtWritingAppDatabase {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doPublishToLocalQueueWithStartTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.PublishToLocalQueueWithStartRequest,
+) (*data_models.PublishToLocalQueueWithStartResponse, error) {
+	startReq := request.StartRequest.Request
+
+	// lock the latest process execution, so that it can't be closed or replaced concurrently
+	latestRow, found, err := tx.SelectLatestProcessExecutionForUpdate(ctx, startReq.Namespace, startReq.ProcessId)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, latestRow.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+		if prcRow.Status == data_models.ProcessExecutionStatusRunning {
+			hasNewImmediateTask, err := p.publishToLocalQueue(
+				ctx, tx, latestRow.ProcessExecutionId, prcRow.ShardId, request.Messages)
+			if err != nil {
+				return nil, err
+			}
+			return &data_models.PublishToLocalQueueWithStartResponse{
+				ProcessExecutionId:  latestRow.ProcessExecutionId,
+				ShardId:             prcRow.ShardId,
+				HasNewImmediateTask: hasNewImmediateTask,
+			}, nil
+		}
+	}
+
+	// the process is not running, start a new process execution with the IdReusePolicy
+	startResp, err := p.doStartProcessTx(ctx, tx, request.StartRequest)
+	if err != nil {
+		return nil, err
+	}
+	if startResp.AlreadyStarted || startResp.FailedAtWritingAppDatabase {
+		return &data_models.PublishToLocalQueueWithStartResponse{
+			AlreadyStarted:             startResp.AlreadyStarted,
+			FailedAtWritingAppDatabase: startResp.FailedAtWritingAppDatabase,
+			AppDatabaseWritingError:    startResp.AppDatabaseWritingError,
+		}, nil
+	}
+
+	hasNewImmediateTask, err := p.publishToLocalQueue(
+		ctx, tx, startResp.ProcessExecutionId, request.StartRequest.NewTaskShardId, request.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.PublishToLocalQueueWithStartResponse{
+		ProcessExecutionId:  startResp.ProcessExecutionId,
+		ShardId:             request.StartRequest.NewTaskShardId,
+		Started:             true,
+		HasNewImmediateTask: startResp.HasNewImmediateTask || hasNewImmediateTask,
+	}, nil
+}
@@ -20,21 +20,32 @@ func (p sqlProcessStoreImpl) AddVisibilityTaskRecordProcessExecutionStatus(
 	processExecutionId uuid.UUID,
 	status data_models.ProcessExecutionStatus,
 	startTime *int64,
-	endTime *int64) error {
+	endTime *int64,
+	closeRecord *data_models.ProcessCloseRecordJson,
+	searchAttributes map[string]interface{}) error {
 
-	visibilityTaskInfo := data_models.ImmediateTaskInfoJson{
-		VisibilityInfo: &data_models.VisibilityInfoJson{
-			Namespace:          namespace,
-			ProcessId:          processId,
-			ProcessType:        processType,
-			ProcessExecutionId: processExecutionId,
-			Status:             status,
-			StartTime:          startTime,
-			CloseTime:          endTime,
-		},
-	}
+	return p.addVisibilityTask(ctx, tx, shardId, data_models.VisibilityInfoJson{
+		Namespace:          namespace,
+		ProcessId:          processId,
+		ProcessType:        processType,
+		ProcessExecutionId: processExecutionId,
+		Status:             status,
+		StartTime:          startTime,
+		CloseTime:          endTime,
+		CloseRecord:        closeRecord,
+		SearchAttributes:   searchAttributes,
+	})
+}
 
-	visibilityTaskInfoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(visibilityTaskInfo)
+func (p sqlProcessStoreImpl) addVisibilityTask(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	visibilityInfo data_models.VisibilityInfoJson) error {
+
+	visibilityTaskInfoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(data_models.ImmediateTaskInfoJson{
+		VisibilityInfo: &visibilityInfo,
+	})
 	if err != nil {
 		return err
 	}
@@ -42,10 +53,65 @@ func (p sqlProcessStoreImpl) AddVisibilityTaskRecordProcessExecutionStatus(
 
 		ShardId:            shardId,
 		TaskType:           data_models.ImmediateTaskTypeVisibility,
-		ProcessExecutionId: processExecutionId,
+		ProcessExecutionId: visibilityInfo.ProcessExecutionId,
 		Info:               visibilityTaskInfoBytes,
 	}
 	// TODO: upsert for starting process, update for closing process
 	err = tx.InsertImmediateTask(ctx, visibilityTask)
 	return err
 }
+
+// AddVisibilityTaskUpsertSearchAttributes adds a visibility task to upsert the search attributes of a running process
+func (p sqlProcessStoreImpl) AddVisibilityTaskUpsertSearchAttributes(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	namespace string,
+	processId string,
+	processType string,
+	processExecutionId uuid.UUID,
+	searchAttributes map[string]interface{}) error {
+	return p.AddVisibilityTaskRecordProcessExecutionStatus(
+		ctx,
+		tx,
+		shardId,
+		namespace,
+		processId,
+		processType,
+		processExecutionId,
+		data_models.ProcessExecutionStatusRunning,
+		nil,
+		nil,
+		nil,
+		searchAttributes)
+}
+
+// AddVisibilityTaskDeleteProcessExecution adds a visibility task to delete the record of a deleted process execution
+func (p sqlProcessStoreImpl) AddVisibilityTaskDeleteProcessExecution(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	namespace string,
+	processId string,
+	processType string,
+	processExecutionId uuid.UUID) error {
+
+	visibilityTaskInfoBytes, err := data_models.FromImmediateTaskInfoIntoBytes(data_models.ImmediateTaskInfoJson{
+		VisibilityInfo: &data_models.VisibilityInfoJson{
+			Namespace:          namespace,
+			ProcessId:          processId,
+			ProcessType:        processType,
+			ProcessExecutionId: processExecutionId,
+			Deleted:            true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return tx.InsertImmediateTask(ctx, extensions.ImmediateTaskRowForInsert{
+		ShardId:            shardId,
+		TaskType:           data_models.ImmediateTaskTypeVisibility,
+		ProcessExecutionId: processExecutionId,
+		Info:               visibilityTaskInfoBytes,
+	})
+}
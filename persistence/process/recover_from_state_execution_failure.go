@@ -51,7 +51,7 @@ func (p sqlProcessStoreImpl) doRecoverFromStateExecutionFailureTx(
 
 	// mark the current state as failed
 	failureBytes, err := data_models.CreateStateExecutionFailureBytesForBackoff(
-		request.LastFailureStatus, request.LastFailureDetails, request.LastFailureCompletedAttempts)
+		request.LastFailureStatus, request.LastFailureDetails, request.LastFailureCompletedAttempts, nil)
 	if err != nil {
 		return err
 	}
@@ -73,6 +73,19 @@ func (p sqlProcessStoreImpl) doRecoverFromStateExecutionFailureTx(
 		return err
 	}
 
+	err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeStateExecutionFailed, request.SourceStateExecutionId,
+		data_models.HistoryEventInfoJson{
+			Failure: &data_models.StateExecutionFailureJson{
+				StatusCode:        &request.LastFailureStatus,
+				Details:           &request.LastFailureDetails,
+				CompletedAttempts: &request.LastFailureCompletedAttempts,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
 	// update process info
 	sequenceMaps, err := data_models.NewStateExecutionSequenceMapsFromBytes(prcRow.StateExecutionSequenceMaps)
 	if err != nil {
@@ -111,6 +124,14 @@ func (p sqlProcessStoreImpl) doRecoverFromStateExecutionFailureTx(
 		return err
 	}
 
+	err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeStateExecutionScheduled,
+		data_models.StateExecutionId{StateId: nextStateId, StateIdSequence: int32(nextStateIdSeq)},
+		data_models.HistoryEventInfoJson{})
+	if err != nil {
+		return err
+	}
+
 	// update process execution row
 	prcRow.StateExecutionSequenceMaps, err = sequenceMaps.ToBytes()
 	if err != nil {
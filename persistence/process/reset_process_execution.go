@@ -0,0 +1,176 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) ResetProcessExecution(
+	ctx context.Context, request data_models.ResetProcessExecutionRequest,
+) (*data_models.ResetProcessExecutionResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doResetProcessExecutionTx(ctx, tx, request)
+	if err != nil {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doResetProcessExecutionTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.ResetProcessExecutionRequest,
+) (*data_models.ResetProcessExecutionResponse, error) {
+	// lock process execution row first
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, request.ProcessExecutionId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.ResetProcessExecutionResponse{
+				ProcessNotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	if prcRow.Status != data_models.ProcessExecutionStatusRunning {
+		return &data_models.ResetProcessExecutionResponse{
+			ProcessNotRunning: true,
+		}, nil
+	}
+
+	resetToStateRow, err := tx.SelectAsyncStateExecutionForUpdate(ctx, extensions.AsyncStateExecutionSelectFilter{
+		ProcessExecutionId: request.ProcessExecutionId,
+		StateId:            request.ResetToStateExecutionId.StateId,
+		StateIdSequence:    request.ResetToStateExecutionId.StateIdSequence,
+	})
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.ResetProcessExecutionResponse{
+				StateExecutionNotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	stateInfo, err := data_models.BytesToAsyncStateExecutionInfo(resetToStateRow.Info)
+	if err != nil {
+		return nil, err
+	}
+	// the new state execution is not a recovery of the original one
+	stateInfo.RecoverFromStateExecutionId = nil
+	stateInfo.RecoverFromApi = nil
+	stateInfoBytes, err := stateInfo.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: abort all the pending state executions, and stop them from consuming the local queue messages.
+	// The unconsumed messages are kept for the new state execution, while the messages that were
+	// already consumed by an aborted state execution are not returned to the queues.
+
+	sequenceMaps, err := data_models.NewStateExecutionSequenceMapsFromBytes(prcRow.StateExecutionSequenceMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	localQueues, err := data_models.NewStateExecutionLocalQueuesFromBytes(prcRow.StateExecutionLocalQueues)
+	if err != nil {
+		return nil, err
+	}
+
+	for stateId, stateIdSeqs := range sequenceMaps.PendingExecutionMap {
+		for stateIdSeq := range stateIdSeqs {
+			localQueues.CleanupFor(data_models.StateExecutionId{
+				StateId:         stateId,
+				StateIdSequence: int32(stateIdSeq),
+			})
+		}
+	}
+
+	if len(sequenceMaps.PendingExecutionMap) > 0 {
+		err = tx.BatchUpdateAsyncStateExecutionsToAbortRunning(ctx, request.ProcessExecutionId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sequenceMaps.PendingExecutionMap = map[string]map[int]bool{}
+	// the graceful completion requested by the aborted state executions is discarded with them
+	prcRow.GracefulCompleteRequested = false
+
+	// Step 2: start a new sequence of the state with the original input
+
+	stateId := request.ResetToStateExecutionId.StateId
+	stateIdSeq := sequenceMaps.StartNewStateExecution(stateId)
+	newStateExecutionId := data_models.StateExecutionId{
+		StateId:         stateId,
+		StateIdSequence: int32(stateIdSeq),
+	}
+
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeProcessExecutionReset, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			ResetToStateExecutionId: ptr.Any(request.ResetToStateExecutionId.GetStateExecutionId()),
+			ResetReason:             ptr.Any(request.Reason),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAsyncStateExecution(
+		ctx, tx, prcRow.ProcessExecutionId, stateId, stateIdSeq, stateInfo.StateConfig,
+		resetToStateRow.Input, stateInfoBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertImmediateTask(ctx, tx, prcRow.ProcessExecutionId, stateId, stateIdSeq, stateInfo.StateConfig, prcRow.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeStateExecutionScheduled, newStateExecutionId, data_models.HistoryEventInfoJson{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: update process execution row
+
+	prcRow.StateExecutionSequenceMaps, err = sequenceMaps.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	prcRow.StateExecutionLocalQueues, err = localQueues.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.ResetProcessExecutionResponse{
+		HasNewImmediateTask: true,
+		NewStateExecutionId: newStateExecutionId,
+	}, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// addFireScheduleTimerTask adds the timer task to fire the schedule, with the version of the schedule
+// after the current change, so that the task can be detected as stale if the schedule changes again
+func (p sqlProcessStoreImpl) addFireScheduleTimerTask(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	namespace string,
+	scheduleId string,
+	scheduleVersion int32,
+	fireTimestamp int64,
+) error {
+	timerInfo := data_models.TimerTaskInfoJson{
+		ScheduleInfo: &data_models.ScheduleTimerTaskInfoJson{
+			Namespace:       namespace,
+			ScheduleId:      scheduleId,
+			ScheduleVersion: scheduleVersion,
+		},
+	}
+	timerInfoBytes, err := timerInfo.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	return tx.InsertTimerTask(ctx, extensions.TimerTaskRowForInsert{
+		ShardId:             shardId,
+		FireTimeUnixSeconds: fireTimestamp,
+		TaskType:            data_models.TimerTaskTypeFireSchedule,
+		Info:                timerInfoBytes,
+	})
+}
+
+// startProcessForSchedule starts the process execution of the fire time with the start request template.
+// It returns AlreadyStarted if the fire time has already started a process execution, e.g. by a backfill.
+func (p sqlProcessStoreImpl) startProcessForSchedule(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	shardId int32,
+	namespace string,
+	info data_models.ScheduleInfoJson,
+	fireTimestamp int64,
+) (*data_models.StartProcessRequest, *data_models.StartProcessResponse, error) {
+	req := info.StartRequest
+	req.Namespace = namespace
+	req.ProcessId = info.GetProcessIdForFireTimestamp(fireTimestamp)
+
+	startConfig := xcapi.ProcessStartConfig{}
+	if req.ProcessStartConfig != nil {
+		startConfig = *req.ProcessStartConfig
+	}
+	startConfig.IdReusePolicy = ptr.Any(xcapi.DISALLOW_REUSE)
+	req.ProcessStartConfig = &startConfig
+
+	// check it first, because the start would still write the initial local attributes if already started
+	_, found, err := tx.SelectLatestProcessExecutionForUpdate(ctx, namespace, req.ProcessId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if found {
+		return nil, &data_models.StartProcessResponse{
+			AlreadyStarted: true,
+		}, nil
+	}
+
+	startReq := data_models.StartProcessRequest{
+		Request:          req,
+		NewTaskShardId:   shardId,
+		SearchAttributes: info.SearchAttributes,
+	}
+	if startConfig.GetTimeoutSeconds() > 0 {
+		startReq.TimeoutTimeUnixSeconds = time.Now().Unix() + int64(startConfig.GetTimeoutSeconds())
+	}
+
+	resp, err := p.doStartProcessTx(ctx, tx, startReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &startReq, resp, nil
+}
+
+func toSchedule(row extensions.ScheduleRow) (*data_models.Schedule, error) {
+	info, err := data_models.BytesToScheduleInfo(row.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data_models.Schedule{
+		Namespace:           row.Namespace,
+		ScheduleId:          row.ScheduleId,
+		ShardId:             row.ShardId,
+		Paused:              row.Paused,
+		Info:                info,
+		NextFireTimestamp:   row.NextFireTimeUnixSeconds,
+		LastFireTimestamp:   row.LastFireTimeUnixSeconds,
+		LastProcessId:       row.LastProcessId,
+		FiredCount:          row.FiredCount,
+		SkippedCount:        row.SkippedCount,
+		Version:             row.PreviousVersion,
+		CreateTimestamp:     row.CreateTime.Unix(),
+		LastUpdateTimestamp: row.LastUpdateTime.Unix(),
+	}, nil
+}
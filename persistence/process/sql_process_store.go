@@ -12,19 +12,23 @@ import (
 )
 
 type sqlProcessStoreImpl struct {
-	session extensions.SQLDBSession
-	logger  log.Logger
+	session   extensions.SQLDBSession
+	retention config.RetentionConfig
+	logger    log.Logger
 }
 
 var defaultTxOpts *sql.TxOptions = &sql.TxOptions{
 	Isolation: sql.LevelReadCommitted,
 }
 
-func NewSQLProcessStore(sqlConfig config.SQL, logger log.Logger) (persistence.ProcessStore, error) {
+func NewSQLProcessStore(
+	sqlConfig config.SQL, retention config.RetentionConfig, logger log.Logger,
+) (persistence.ProcessStore, error) {
 	session, err := extensions.NewSQLSession(&sqlConfig)
 	return &sqlProcessStoreImpl{
-		session: session,
-		logger:  logger,
+		session:   session,
+		retention: retention,
+		logger:    logger,
 	}, err
 }
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/persistence/data_models"
+	"sort"
+	"time"
 
 	"github.com/xcherryio/xcherry/common/uuid"
 	"github.com/xcherryio/xcherry/extensions"
@@ -83,6 +85,100 @@ func insertImmediateTask(
 	return tx.InsertImmediateTask(ctx, immediateTaskRow)
 }
 
+// insertDelayedStartTimerTask inserts the timer task to convert into the immediate task of the start state
+// at the delayed start time
+func insertDelayedStartTimerTask(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	processExecutionId uuid.UUID,
+	stateId string,
+	stateIdSeq int,
+	stateConfig *xcapi.AsyncStateConfig,
+	shardId int32,
+	fireTimestamp int64,
+) error {
+	taskType := data_models.ImmediateTaskTypeWaitUntil
+	if stateConfig.GetSkipWaitUntil() {
+		taskType = data_models.ImmediateTaskTypeExecute
+	}
+	timerInfoBytes, err := data_models.CreateTimerTaskInfoBytes(nil, &taskType)
+	if err != nil {
+		return err
+	}
+
+	return tx.InsertTimerTask(ctx, extensions.TimerTaskRowForInsert{
+		ShardId:             shardId,
+		FireTimeUnixSeconds: fireTimestamp,
+		TaskType:            data_models.TimerTaskTypeDelayedStart,
+		ProcessExecutionId:  processExecutionId,
+		StateId:             stateId,
+		StateIdSequence:     int32(stateIdSeq),
+		Info:                timerInfoBytes,
+	})
+}
+
+// insertHistoryEvent appends a history event for the process execution. The event id is allocated from
+// historyEventIdSequence, so the caller must hold the lock of the process execution row,
+// and is responsible for writing the increased sequence back to the row in the same transaction.
+func insertHistoryEvent(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	processExecutionId uuid.UUID,
+	historyEventIdSequence *int32,
+	eventType data_models.HistoryEventType,
+	stateExecutionId data_models.StateExecutionId,
+	info data_models.HistoryEventInfoJson,
+) error {
+	infoBytes, err := info.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	*historyEventIdSequence++
+
+	return tx.InsertHistoryEvent(ctx, extensions.HistoryEventRow{
+		ProcessExecutionId: processExecutionId,
+		EventId:            *historyEventIdSequence,
+		EventType:          eventType,
+		StateId:            stateExecutionId.StateId,
+		StateIdSequence:    stateExecutionId.StateIdSequence,
+		CreateTime:         time.Now(),
+		Info:               infoBytes,
+	})
+}
+
+// insertHistoryEventForConsumedMessages records the local queue messages consumed by the state execution, if any
+func insertHistoryEventForConsumedMessages(
+	ctx context.Context,
+	tx extensions.SQLTransaction,
+	prcRow *extensions.ProcessExecutionRowForUpdate,
+	stateExecutionId data_models.StateExecutionId,
+	consumedMessagesMap map[int][]data_models.InternalLocalQueueMessage,
+) error {
+	var commandIndexes []int
+	for idx := range consumedMessagesMap {
+		commandIndexes = append(commandIndexes, idx)
+	}
+	sort.Ints(commandIndexes)
+
+	var dedupIds []string
+	for _, idx := range commandIndexes {
+		for _, consumedMessage := range consumedMessagesMap[idx] {
+			dedupIds = append(dedupIds, consumedMessage.DedupId)
+		}
+	}
+
+	if len(dedupIds) == 0 {
+		return nil
+	}
+
+	return insertHistoryEvent(ctx, tx, prcRow.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeLocalQueueMessagesConsumed, stateExecutionId,
+		data_models.HistoryEventInfoJson{
+			ConsumedDedupIds: dedupIds,
+		})
+}
+
 // publishToLocalQueue inserts len(valid_messages) rows into xcherry_sys_local_queue_messages,
 // and inserts only one row into xcherry_sys_immediate_tasks with all the dedupIds for these messages.
 // publishToLocalQueue returns (HasNewImmediateTask, error).
@@ -226,14 +322,18 @@ func (p sqlProcessStoreImpl) updateCommandResultsWithFiredTimerCommand(
 }
 
 func (p sqlProcessStoreImpl) hasCompletedWaitUntilWaiting(
-	commandRequest xcapi.CommandRequest, commandResults data_models.CommandResultsJson,
+	commandRequest xcapi.CommandRequest,
+	processCompletionCommands []data_models.ProcessCompletionCommandJson,
+	commandResults data_models.CommandResultsJson,
 ) bool {
+	completed := len(commandResults.LocalQueueResults) + len(commandResults.TimerResults) +
+		len(commandResults.ProcessCompletionResults)
 	switch commandRequest.GetWaitingType() {
 	case xcapi.ANY_OF_COMPLETION:
-		return len(commandResults.LocalQueueResults)+len(commandResults.TimerResults) > 0
+		return completed > 0
 	case xcapi.ALL_OF_COMPLETION:
-		return len(commandResults.LocalQueueResults)+len(commandResults.TimerResults) ==
-			len(commandRequest.LocalQueueCommands)+len(commandRequest.TimerCommands)
+		return completed == len(commandRequest.LocalQueueCommands)+len(commandRequest.TimerCommands)+
+			len(processCompletionCommands)
 	case xcapi.EMPTY_COMMAND:
 		return true
 	default:
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+func SQLBatchOperationTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	batchOperationId := fmt.Sprintf("test-batch-%v", time.Now().String())
+
+	info := data_models.BatchOperationInfoJson{
+		Filter: data_models.BatchOperationFilterJson{
+			ProcessType:       ptr.Any(testProcessType),
+			Status:            ptr.Any(xcapi.RUNNING),
+			EarliestStartTime: 0,
+			LatestStartTime:   time.Now().Unix(),
+		},
+		Action: data_models.BatchOperationActionJson{
+			Type:     data_models.BatchOperationActionTypeStop,
+			StopType: ptr.Any(xcapi.TERMINATE),
+		},
+	}
+
+	createResp, err := store.CreateBatchOperation(ctx, data_models.CreateBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+		Info:             info,
+		Rps:              10,
+	})
+	require.NoError(t, err)
+	ass.False(createResp.AlreadyExists)
+
+	createResp, err = store.CreateBatchOperation(ctx, data_models.CreateBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+		Info:             info,
+		Rps:              10,
+	})
+	require.NoError(t, err)
+	ass.True(createResp.AlreadyExists)
+
+	descResp, err := store.DescribeBatchOperation(ctx, data_models.DescribeBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+	})
+	require.NoError(t, err)
+	ass.False(descResp.NotExists)
+	batchOperation := descResp.BatchOperation
+	ass.Equal(data_models.BatchOperationStatusRunning, batchOperation.Status)
+	ass.Equal(info, batchOperation.Info)
+	ass.Equal(int32(10), batchOperation.Rps)
+	ass.Equal("", batchOperation.NextPageToken)
+	ass.Equal("", batchOperation.Owner)
+
+	runningResp, err := store.GetRunningBatchOperations(ctx, data_models.GetRunningBatchOperationsRequest{
+		PageSize: 1000,
+	})
+	require.NoError(t, err)
+	ass.True(containsBatchOperation(runningResp.BatchOperations, batchOperationId))
+
+	// claim and checkpoint
+	batchOperation.Owner = "test-owner"
+	batchOperation.NextPageToken = "test-token"
+	batchOperation.SucceededCount = 3
+	batchOperation.SkippedCount = 1
+	updateResp, err := store.UpdateBatchOperation(ctx, data_models.UpdateBatchOperationRequest{
+		BatchOperation: batchOperation,
+	})
+	require.NoError(t, err)
+	ass.False(updateResp.VersionConflict)
+
+	// update with a stale version
+	updateResp, err = store.UpdateBatchOperation(ctx, data_models.UpdateBatchOperationRequest{
+		BatchOperation: batchOperation,
+	})
+	require.NoError(t, err)
+	ass.True(updateResp.VersionConflict)
+
+	descResp, err = store.DescribeBatchOperation(ctx, data_models.DescribeBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+	})
+	require.NoError(t, err)
+	ass.Equal("test-owner", descResp.BatchOperation.Owner)
+	ass.Equal("test-token", descResp.BatchOperation.NextPageToken)
+	ass.Equal(int32(3), descResp.BatchOperation.SucceededCount)
+	ass.Equal(int32(1), descResp.BatchOperation.SkippedCount)
+	ass.Equal(batchOperation.Version+1, descResp.BatchOperation.Version)
+
+	// cancel
+	cancelResp, err := store.CancelBatchOperation(ctx, data_models.CancelBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+	})
+	require.NoError(t, err)
+	ass.False(cancelResp.NotExists)
+	ass.False(cancelResp.AlreadyClosed)
+
+	cancelResp, err = store.CancelBatchOperation(ctx, data_models.CancelBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: batchOperationId,
+	})
+	require.NoError(t, err)
+	ass.True(cancelResp.AlreadyClosed)
+
+	runningResp, err = store.GetRunningBatchOperations(ctx, data_models.GetRunningBatchOperationsRequest{
+		PageSize: 1000,
+	})
+	require.NoError(t, err)
+	ass.False(containsBatchOperation(runningResp.BatchOperations, batchOperationId))
+
+	// non-existing batch operation
+	descResp, err = store.DescribeBatchOperation(ctx, data_models.DescribeBatchOperationRequest{
+		Namespace:        namespace,
+		BatchOperationId: "some-wrong-id",
+	})
+	require.NoError(t, err)
+	ass.True(descResp.NotExists)
+}
+
+func containsBatchOperation(batchOperations []data_models.BatchOperation, batchOperationId string) bool {
+	for _, batchOperation := range batchOperations {
+		if batchOperation.BatchOperationId == batchOperationId {
+			return true
+		}
+	}
+	return false
+}
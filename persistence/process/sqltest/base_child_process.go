@@ -0,0 +1,150 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLChildProcessTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	childProcessId := processId + "-child"
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// start the child process along with moving to the next state
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	compResp, err := store.CompleteExecuteExecution(ctx, data_models.CompleteExecuteExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		StateExecutionId: data_models.StateExecutionId{
+			StateId:         task.StateId,
+			StateIdSequence: task.StateIdSequence,
+		},
+		Prepare: *prep,
+		StateDecision: xcapi.StateDecision{
+			NextStates: []xcapi.StateMovement{
+				{
+					StateId: stateId2,
+				},
+			},
+		},
+		TaskShardId: defaultShardId,
+		StartChildProcesses: []xcapi.ProcessExecutionStartRequest{
+			createStartRequest("", childProcessId, input, nil, nil),
+		},
+	})
+	require.NoError(t, err)
+	ass.True(compResp.HasNewImmediateTask)
+	ass.Equal(1, len(compResp.FireTimestamps))
+
+	// the wait until task of the parent, and the wait until and visibility tasks of the child
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 3)
+	var waitingTask data_models.ImmediateTask
+	for _, immediateTask := range immediateTasks {
+		if immediateTask.ProcessExecutionId.String() == prcExeId.String() {
+			waitingTask = immediateTask
+		}
+	}
+	verifyImmediateTaskNoInfo(ass, waitingTask, data_models.ImmediateTaskTypeWaitUntil, stateId2+"-1")
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	descResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Nil(descResp.ParentProcess)
+	ass.Equal(1, len(descResp.ChildProcesses))
+	ass.Equal(childProcessId, descResp.ChildProcesses[0].ProcessId)
+	ass.Equal(stateId1, descResp.ChildProcesses[0].StateId)
+	ass.Equal(int32(1), descResp.ChildProcesses[0].StateIdSequence)
+
+	childDescResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: childProcessId,
+	})
+	require.NoError(t, err)
+	ass.False(childDescResp.NotExists)
+	ass.Equal(xcapi.RUNNING, childDescResp.Response.GetStatus())
+	ass.Equal(&data_models.ParentProcessJson{
+		ProcessId:          processId,
+		ProcessExecutionId: prcExeId.String(),
+		StateId:            stateId1,
+		StateIdSequence:    1,
+	}, childDescResp.ParentProcess)
+	ass.Equal(descResp.ChildProcesses[0].ProcessExecutionId, childDescResp.Response.GetProcessExecutionId())
+
+	// wait for the child process to close
+	prep = prepareStateExecution(ctx, t, store, prcExeId, waitingTask.StateId, waitingTask.StateIdSequence)
+	waitResp, err := store.ProcessWaitUntilExecution(ctx, data_models.ProcessWaitUntilExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		StateExecutionId: data_models.StateExecutionId{
+			StateId:         waitingTask.StateId,
+			StateIdSequence: waitingTask.StateIdSequence,
+		},
+		Prepare: *prep,
+		CommandRequest: xcapi.CommandRequest{
+			WaitingType: xcapi.ALL_OF_COMPLETION,
+		},
+		TaskShardId: defaultShardId,
+		ProcessCompletionCommands: []data_models.ProcessCompletionCommandJson{
+			{ProcessId: childProcessId},
+		},
+	})
+	require.NoError(t, err)
+	ass.False(waitResp.HasNewImmediateTask)
+
+	// closing the child process notifies the waiting state execution
+	terminateProcess(ctx, t, ass, store, namespace, childProcessId)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	var completionTask data_models.ImmediateTask
+	for _, immediateTask := range immediateTasks {
+		if immediateTask.TaskType == data_models.ImmediateTaskTypeProcessCompletionCommand {
+			completionTask = immediateTask
+		}
+	}
+	verifyImmediateTaskNoInfo(ass, completionTask, data_models.ImmediateTaskTypeProcessCompletionCommand, stateId2+"-1")
+	ass.Equal(prcExeId, completionTask.ProcessExecutionId)
+	ass.Equal(0, completionTask.ImmediateTaskInfo.ProcessCompletionInfo.CommandIndex)
+	ass.Equal(xcapi.TERMINATED.Ptr(), completionTask.ImmediateTaskInfo.ProcessCompletionInfo.Result.ProcessStatus)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	completionResp, err := store.ProcessProcessCompletionCommand(ctx, data_models.ProcessProcessCompletionCommandRequest{
+		Task: completionTask,
+	})
+	require.NoError(t, err)
+	ass.True(completionResp.HasNewImmediateTask)
+
+	_, _, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	verifyImmediateTaskNoInfo(ass, immediateTasks[0], data_models.ImmediateTaskTypeExecute, stateId2+"-1")
+
+	prep = prepareStateExecution(ctx, t, store, prcExeId, stateId2, 1)
+	ass.Equal(1, len(prep.ProcessCompletionResults))
+	ass.Equal(xcapi.COMPLETED_COMMAND, prep.ProcessCompletionResults[0].Status)
+	ass.Equal(childProcessId, prep.ProcessCompletionResults[0].ProcessId)
+	ass.Equal(xcapi.TERMINATED.Ptr(), prep.ProcessCompletionResults[0].ProcessStatus)
+}
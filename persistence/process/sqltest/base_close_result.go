@@ -0,0 +1,154 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLProcessCloseResultTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	input := createTestInput()
+
+	// the output of the completed process is recorded
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	getResp, err := store.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.False(getResp.NotExists)
+	ass.Equal(processId, getResp.ProcessId)
+	ass.Equal(data_models.ProcessExecutionStatusRunning, getResp.Status)
+	ass.Nil(getResp.CloseResult)
+
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	output := xcapi.EncodedObject{
+		Encoding: "test-encoding",
+		Data:     "test-output",
+	}
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeExecuteExecution(ctx, t, ass, store, prcExeId, task, prep, xcapi.StateDecision{
+		ThreadCloseDecision: &xcapi.ThreadCloseDecision{
+			CloseType:  xcapi.FORCE_COMPLETE_PROCESS,
+			CloseInput: &output,
+		},
+	}, true)
+
+	latestResp, err := store.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(data_models.ProcessExecutionStatusCompleted, latestResp.Status)
+	require.NotNil(t, latestResp.CloseResult)
+	ass.Equal(&output, latestResp.CloseResult.Output)
+	ass.Nil(latestResp.CloseResult.Failure)
+	ass.Equal(data_models.ProcessCloseReasonTypeStateDecision, latestResp.CloseResult.ReasonType)
+
+	// the close record is also recorded in the visibility
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(&data_models.ProcessCloseRecordJson{
+		ReasonType: data_models.ProcessCloseReasonTypeStateDecision,
+	}, immediateTasks[0].ImmediateTaskInfo.VisibilityInfo.CloseRecord)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the failure of the failed process is recorded
+	processId = fmt.Sprintf("test-prcid-%v", time.Now().String())
+	prcExeId = startProcess(ctx, t, ass, store, namespace, processId, input)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	failure := &data_models.ProcessFailureJson{
+		StateExecutionId: stateId1 + "-1",
+		Failure: data_models.StateExecutionFailureJson{
+			StatusCode:        ptr.Any(int32(500)),
+			Details:           ptr.Any("test-details"),
+			CompletedAttempts: ptr.Any(int32(3)),
+		},
+	}
+	stopResp, err := store.StopProcess(ctx, data_models.StopProcessRequest{
+		Namespace:       namespace,
+		ProcessId:       processId,
+		ProcessStopType: xcapi.FAIL,
+		Failure:         failure,
+	})
+	require.NoError(t, err)
+	ass.False(stopResp.NotExists)
+
+	getResp, err = store.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.Equal(data_models.ProcessExecutionStatusFailed, getResp.Status)
+	require.NotNil(t, getResp.CloseResult)
+	ass.Nil(getResp.CloseResult.Output)
+	ass.Equal(failure, getResp.CloseResult.Failure)
+	ass.Equal(data_models.ProcessCloseReasonTypeStateFailure, getResp.CloseResult.ReasonType)
+
+	// the process execution is not visible from another namespace
+	getResp, err = store.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+		Namespace:          "another-" + namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(getResp.NotExists)
+
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the reason of the stop request is recorded
+	processId = fmt.Sprintf("test-prcid-%v", time.Now().String())
+	startProcess(ctx, t, ass, store, namespace, processId, input)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	stopResp, err = store.StopProcess(ctx, data_models.StopProcessRequest{
+		Namespace:       namespace,
+		ProcessId:       processId,
+		ProcessStopType: xcapi.TERMINATE,
+		Reason:          "test-reason",
+	})
+	require.NoError(t, err)
+	ass.False(stopResp.NotExists)
+
+	closeRecord := &data_models.ProcessCloseRecordJson{
+		ReasonType: data_models.ProcessCloseReasonTypeStopRequest,
+		StopReason: "test-reason",
+	}
+	describeResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(xcapi.TERMINATED, describeResp.Response.GetStatus())
+	ass.Equal(closeRecord, describeResp.CloseRecord)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(closeRecord, immediateTasks[0].ImmediateTaskInfo.VisibilityInfo.CloseRecord)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLCompletionCallbackTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+	callbackUrls := []string{"http://localhost:8080/callback1", "http://localhost:8080/callback2"}
+
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:                createStartRequest(namespace, processId, input, nil, nil),
+		NewTaskShardId:         defaultShardId,
+		CompletionCallbackUrls: callbackUrls,
+		CompletionCallbackRetryPolicy: &xcapi.RetryPolicy{
+			MaximumAttempts: ptr.Any(int32(2)),
+		},
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+
+	minSeq, maxSeq, _ := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	describeResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal([]data_models.CompletionCallback{
+		{Url: callbackUrls[0], Status: data_models.CompletionCallbackStatusPending},
+		{Url: callbackUrls[1], Status: data_models.CompletionCallbackStatusPending},
+	}, describeResp.CompletionCallbacks)
+
+	// closing the process adds a task for each callback url, besides the visibility task
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 3)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	var callbackTasks []data_models.ImmediateTask
+	for idx, task := range immediateTasks[1:] {
+		ass.Equal(data_models.ImmediateTaskTypeCompletionCallback, task.TaskType)
+		ass.Equal(prcExeId.String(), task.ProcessExecutionId.String())
+		info := task.ImmediateTaskInfo.CompletionCallbackInfo
+		require.NotNil(t, info)
+		ass.Equal(int32(idx), info.CallbackIndex)
+		ass.Equal(callbackUrls[idx], info.Url)
+		ass.Equal(int32(2), info.RetryPolicy.GetMaximumAttempts())
+		ass.Equal(namespace, info.Payload.Namespace)
+		ass.Equal(processId, info.Payload.ProcessId)
+		ass.Equal(prcExeId.String(), info.Payload.ProcessExecutionId)
+		ass.Equal(xcapi.TERMINATED, info.Payload.Status)
+		ass.True(info.Payload.CloseTimestamp > 0)
+		ass.Nil(info.Payload.Failure)
+		callbackTasks = append(callbackTasks, task)
+	}
+
+	// the first callback is delivered
+	task := callbackTasks[0]
+	task.ImmediateTaskInfo.WorkerTaskBackoffInfo = &data_models.WorkerTaskBackoffInfoJson{
+		CompletedAttempts:            1,
+		FirstAttemptTimestampSeconds: time.Now().Unix(),
+	}
+	err = store.RecordCompletionCallbackAttempt(ctx, data_models.RecordCompletionCallbackAttemptRequest{
+		Task:      task,
+		Delivered: true,
+	})
+	require.NoError(t, err)
+
+	// the second callback is failed, and retried after the backoff
+	task = callbackTasks[1]
+	task.ImmediateTaskInfo.WorkerTaskBackoffInfo = &data_models.WorkerTaskBackoffInfoJson{
+		CompletedAttempts:            1,
+		FirstAttemptTimestampSeconds: time.Now().Unix(),
+	}
+	nextAttemptTimestamp := time.Now().Unix() + 10
+	lastFailure := &data_models.StateExecutionFailureJson{
+		StatusCode:           ptr.Any(int32(500)),
+		Details:              ptr.Any("test"),
+		CompletedAttempts:    ptr.Any(int32(1)),
+		LastAttemptTimestamp: ptr.Any(time.Now().Unix()),
+		NextAttemptTimestamp: &nextAttemptTimestamp,
+	}
+	err = store.RecordCompletionCallbackAttempt(ctx, data_models.RecordCompletionCallbackAttemptRequest{
+		Task:                        task,
+		LastFailure:                 lastFailure,
+		NextAttemptTimestampSeconds: &nextAttemptTimestamp,
+	})
+	require.NoError(t, err)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	describeResp, err = store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal([]data_models.CompletionCallback{
+		{Url: callbackUrls[0], Status: data_models.CompletionCallbackStatusDelivered, CompletedAttempts: 1},
+		{Url: callbackUrls[1], Status: data_models.CompletionCallbackStatusPending, CompletedAttempts: 1,
+			LastFailure: lastFailure},
+	}, describeResp.CompletionCallbacks)
+
+	// the backoff timer task is converted back to the callback task
+	_, _, timerTasks := getAndCheckTimerTasksUpForTimestamps(ctx, t, ass, store, 1, []int64{nextAttemptTimestamp}, 0)
+	ass.Equal(data_models.TimerTaskTypeWorkerTaskBackoff, timerTasks[0].TaskType)
+	_, err = store.ConvertTimerTaskToImmediateTask(ctx, data_models.ProcessTimerTaskRequest{
+		Task: timerTasks[0],
+	})
+	require.NoError(t, err)
+
+	_, _, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	ass.Equal(data_models.ImmediateTaskTypeCompletionCallback, task.TaskType)
+	ass.Equal(callbackTasks[1].ImmediateTaskInfo.CompletionCallbackInfo, task.ImmediateTaskInfo.CompletionCallbackInfo)
+	ass.Equal(int32(1), task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts)
+
+	// the retry policy is exhausted
+	task.ImmediateTaskInfo.WorkerTaskBackoffInfo.CompletedAttempts++
+	err = store.RecordCompletionCallbackAttempt(ctx, data_models.RecordCompletionCallbackAttemptRequest{
+		Task:        task,
+		LastFailure: lastFailure,
+	})
+	require.NoError(t, err)
+	checkAndGetImmediateTasks(ctx, t, ass, store, 0)
+
+	describeResp, err = store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(data_models.CompletionCallbackStatusFailed, describeResp.CompletionCallbacks[1].Status)
+	ass.Equal(int32(2), describeResp.CompletionCallbacks[1].CompletedAttempts)
+}
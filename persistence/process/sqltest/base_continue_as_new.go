@@ -0,0 +1,172 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLContinueAsNewTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+	value1 := xcapi.EncodedObject{Encoding: "test-encoding", Data: "value1"}
+	value2 := xcapi.EncodedObject{Encoding: "test-encoding", Data: "value2"}
+
+	startReq := createStartRequest(namespace, processId, input, nil, nil)
+	startReq.ProcessStartConfig.LocalAttributeConfig = &xcapi.LocalAttributeConfig{
+		InitialWrite: []xcapi.KeyValue{
+			{Key: "key1", Value: value1},
+			{Key: "key2", Value: value2},
+		},
+	}
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        startReq,
+		NewTaskShardId: defaultShardId,
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the message is unconsumed because no state is waiting for the queue
+	dedupId := uuid.MustNewUUID()
+	publishResp, err := store.PublishToLocalQueue(ctx, data_models.PublishToLocalQueueRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		Messages: []xcapi.LocalQueueMessage{
+			{QueueName: "test-queue", DedupId: ptr.Any(dedupId.String()), Payload: &value1},
+		},
+	})
+	require.NoError(t, err)
+	ass.True(publishResp.HasNewImmediateTask)
+
+	_, _, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	_, err = store.ProcessLocalQueueMessages(ctx, data_models.ProcessLocalQueueMessagesRequest{
+		TaskShardId:        defaultShardId,
+		TaskSequence:       immediateTasks[0].GetTaskSequence(),
+		ProcessExecutionId: prcExeId,
+		Messages:           immediateTasks[0].ImmediateTaskInfo.LocalQueueMessageInfo,
+	})
+	require.NoError(t, err)
+	checkAndGetImmediateTasks(ctx, t, ass, store, 0)
+
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// continue as new, with only key1 carried over, and a message published along with the decision
+	dedupId2 := uuid.MustNewUUID()
+	newInput := xcapi.EncodedObject{Encoding: "test-encoding", Data: "test-new-input"}
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	compResp, err := store.CompleteExecuteExecution(ctx, data_models.CompleteExecuteExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		StateExecutionId:   task.StateExecutionId,
+		Prepare:            *prep,
+		StateDecision:      xcapi.StateDecision{},
+		TaskShardId:        defaultShardId,
+		TaskSequence:       task.GetTaskSequence(),
+		ContinueAsNew: &data_models.ContinueAsNew{
+			StartStateId:       ptr.Any(stateId2),
+			StartStateInput:    &newInput,
+			LocalAttributeKeys: []string{"key1"},
+		},
+		PublishToLocalQueue: []xcapi.LocalQueueMessage{
+			{QueueName: "test-queue", DedupId: ptr.Any(dedupId2.String()), Payload: &value2},
+		},
+	})
+	require.NoError(t, err)
+	ass.True(compResp.HasNewImmediateTask)
+	ass.Equal(data_models.ProcessExecutionStatusCompleted, compResp.ProcessStatus)
+	ass.Equal(1, len(compResp.FireTimestamps))
+
+	oldDescResp, err := store.DescribeProcessExecution(ctx, data_models.DescribeProcessExecutionRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.Equal(xcapi.COMPLETED, oldDescResp.Response.GetStatus())
+	require.NotEmpty(t, oldDescResp.ContinuedAsNewProcessExecutionId)
+	ass.Empty(oldDescResp.ContinuedFromProcessExecutionId)
+	newPrcExeId := uuid.MustParseUUID(oldDescResp.ContinuedAsNewProcessExecutionId)
+
+	newDescResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(xcapi.RUNNING, newDescResp.Response.GetStatus())
+	ass.Equal(newPrcExeId.String(), newDescResp.Response.GetProcessExecutionId())
+	ass.Equal(prcExeId.String(), newDescResp.ContinuedFromProcessExecutionId)
+	ass.Empty(newDescResp.ContinuedAsNewProcessExecutionId)
+
+	// the new execution starts from the new start state, receives the unconsumed message again,
+	// and then the published message
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 5)
+	var stateTask *data_models.ImmediateTask
+	var localQueueTasks []data_models.ImmediateTask
+	for i, immediateTask := range immediateTasks {
+		if immediateTask.ProcessExecutionId.String() != newPrcExeId.String() {
+			continue
+		}
+		switch immediateTask.TaskType {
+		case data_models.ImmediateTaskTypeWaitUntil:
+			stateTask = &immediateTasks[i]
+		case data_models.ImmediateTaskTypeNewLocalQueueMessages:
+			localQueueTasks = append(localQueueTasks, immediateTask)
+		}
+	}
+	require.NotNil(t, stateTask)
+	ass.Equal(stateId2, stateTask.StateId)
+	require.Equal(t, 2, len(localQueueTasks))
+	messages := localQueueTasks[0].ImmediateTaskInfo.LocalQueueMessageInfo
+	require.Equal(t, 1, len(messages))
+	ass.Equal("test-queue", messages[0].QueueName)
+	ass.Equal(dedupId.String(), messages[0].DedupId.String())
+	messages = localQueueTasks[1].ImmediateTaskInfo.LocalQueueMessageInfo
+	require.Equal(t, 1, len(messages))
+	ass.Equal(dedupId2.String(), messages[0].DedupId.String())
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep = prepareStateExecution(ctx, t, store, newPrcExeId, stateId2, 1)
+	verifyStateExecution(ass, prep, processId, newInput, data_models.StateExecutionStatusWaitUntilRunning)
+
+	getResp, err := store.GetLocalAttributes(ctx, data_models.GetLocalAttributesRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(newPrcExeId.String(), getResp.ProcessExecutionId.String())
+	ass.Equal([]data_models.LocalAttribute{
+		{Key: "key1", Value: value1, Version: 1},
+	}, getResp.Attributes)
+
+	closeResp, err := store.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, closeResp.CloseResult)
+	ass.Equal(newPrcExeId.String(), closeResp.CloseResult.ContinuedAsNewProcessExecutionId)
+
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLDelayedStartTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	delayedStartTime := time.Now().Add(time.Hour).Unix()
+
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:                     createStartRequest(namespace, processId, createTestInput(), nil, nil),
+		NewTaskShardId:              defaultShardId,
+		TimeoutTimeUnixSeconds:      delayedStartTime + 100,
+		DelayedStartTimeUnixSeconds: delayedStartTime,
+	})
+	require.NoError(t, err)
+	ass.False(startResp.AlreadyStarted)
+	prcExeId := startResp.ProcessExecutionId
+
+	descResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(xcapi.RUNNING, descResp.Response.GetStatus())
+	ass.Equal(delayedStartTime, descResp.DelayedStartTimestamp)
+
+	// only the visibility task, as the start state waits for the delayed start
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	ass.Equal(ptr.Any(delayedStartTime), immediateTasks[0].ImmediateTaskInfo.VisibilityInfo.DelayedStartTime)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	_, _, timerTasks := getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 2, delayedStartTime+100)
+	task := timerTasks[0]
+	verifyTimerTask(ass, task, data_models.TimerTaskTypeDelayedStart, stateId1+"-1", data_models.TimerTaskInfoJson{
+		WorkerTaskType: ptr.Any(data_models.ImmediateTaskTypeWaitUntil),
+	})
+	ass.Equal(prcExeId, task.ProcessExecutionId)
+	ass.Equal(delayedStartTime, task.FireTimestampSeconds)
+	ass.Equal(data_models.TimerTaskTypeProcessTimeout, timerTasks[1].TaskType)
+	ass.Equal(delayedStartTime+100, timerTasks[1].FireTimestampSeconds)
+
+	// the delayed start is converted into the immediate task of the start state
+	resp, err := store.ConvertTimerTaskToImmediateTask(ctx, data_models.ProcessTimerTaskRequest{
+		Task: task,
+	})
+	require.NoError(t, err)
+	ass.True(resp.HasNewImmediateTask)
+	_, _, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	verifyImmediateTaskNoInfo(ass, immediateTasks[0], data_models.ImmediateTaskTypeWaitUntil, stateId1+"-1")
+	ass.Equal(prcExeId, immediateTasks[0].ProcessExecutionId)
+	getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 1, delayedStartTime+100)
+}
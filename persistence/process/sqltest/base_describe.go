@@ -0,0 +1,164 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+func SQLDescribeStateExecutionsTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	resp, err := store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.False(resp.NotExists)
+	ass.Equal(prcExeId, resp.ProcessExecutionId)
+	ass.Equal(1, len(resp.StateExecutions))
+	ass.Equal(stateId1+"-1", resp.StateExecutions[0].GetStateExecutionId())
+	ass.Equal(data_models.StateExecutionStatusWaitUntilRunning, resp.StateExecutions[0].Status)
+	ass.True(resp.StateExecutions[0].Pending)
+	ass.Nil(resp.StateExecutions[0].LastFailure)
+
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+
+	resp, err = store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: &prcExeId,
+	})
+	require.NoError(t, err)
+	ass.Equal(1, len(resp.StateExecutions))
+	ass.False(resp.StateExecutions[0].Pending)
+
+	resp, err = store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace:   namespace,
+		ProcessId:   processId,
+		PendingOnly: true,
+	})
+	require.NoError(t, err)
+	ass.Equal(0, len(resp.StateExecutions))
+
+	// non-existing process
+	resp, err = store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace: namespace,
+		ProcessId: "some-wrong-id",
+	})
+	require.NoError(t, err)
+	ass.True(resp.NotExists)
+
+	// the process execution in another namespace
+	resp, err = store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace:          "some-wrong-namespace",
+		ProcessExecutionId: &prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(resp.NotExists)
+}
+
+func SQLProcessExecutionRunsTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId1 := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	// restart the process, the first run is terminated
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        createStartRequestWithTerminateIfRunningPolicy(namespace, processId, input),
+		NewTaskShardId: defaultShardId,
+	})
+	require.NoError(t, err)
+	ass.False(startResp.AlreadyStarted)
+	prcExeId2 := startResp.ProcessExecutionId
+
+	runsResp, err := store.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(runsResp.Runs))
+	ass.Nil(runsResp.NextPageToken)
+	run1, run2 := runsResp.Runs[0], runsResp.Runs[1]
+
+	// the runs are paged in the same order
+	runsResp, err = store.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		PageSize:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(runsResp.Runs))
+	ass.Equal(run1, runsResp.Runs[0])
+	require.NotNil(t, runsResp.NextPageToken)
+	runsResp, err = store.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace:     namespace,
+		ProcessId:     processId,
+		PageSize:      1,
+		NextPageToken: runsResp.NextPageToken,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(runsResp.Runs))
+	ass.Equal(run2, runsResp.Runs[0])
+	require.NotNil(t, runsResp.NextPageToken)
+	runsResp, err = store.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace:     namespace,
+		ProcessId:     processId,
+		PageSize:      1,
+		NextPageToken: runsResp.NextPageToken,
+	})
+	require.NoError(t, err)
+	ass.Equal(0, len(runsResp.Runs))
+	ass.Nil(runsResp.NextPageToken)
+	ass.Equal(prcExeId1.String(), run1.ProcessExecutionId.String())
+	ass.Equal(data_models.ProcessExecutionStatusTerminated, run1.Status)
+	ass.True(run1.CloseTimestamp >= run1.StartTimestamp)
+	ass.False(run1.Latest)
+	ass.Equal(prcExeId2.String(), run2.ProcessExecutionId.String())
+	ass.Equal(data_models.ProcessExecutionStatusRunning, run2.Status)
+	ass.Equal(int64(0), run2.CloseTimestamp)
+	ass.True(run2.Latest)
+
+	// the earlier run can be described by the id
+	descResp, err := store.DescribeProcessExecution(ctx, data_models.DescribeProcessExecutionRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId1,
+	})
+	require.NoError(t, err)
+	ass.False(descResp.NotExists)
+	ass.Equal(processId, descResp.ProcessId)
+	ass.Equal(prcExeId1.String(), descResp.Response.GetProcessExecutionId())
+	ass.Equal(xcapi.TERMINATED, descResp.Response.GetStatus())
+	ass.Equal(run1.CloseTimestamp, descResp.CloseTimestamp)
+
+	descResp, err = store.DescribeProcessExecution(ctx, data_models.DescribeProcessExecutionRequest{
+		Namespace:          "another-" + namespace,
+		ProcessExecutionId: prcExeId1,
+	})
+	require.NoError(t, err)
+	ass.True(descResp.NotExists)
+
+	runsResp, err = store.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace: namespace,
+		ProcessId: "some-wrong-id",
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	ass.Equal(0, len(runsResp.Runs))
+}
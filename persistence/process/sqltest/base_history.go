@@ -0,0 +1,80 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+func SQLHistoryTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+
+	// read the first page
+	resp, err := store.GetProcessExecutionHistory(ctx, data_models.GetProcessExecutionHistoryRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		PageSize:  2,
+	})
+	require.NoError(t, err)
+	ass.False(resp.NotExists)
+	ass.Equal(prcExeId, resp.ProcessExecutionId)
+	ass.True(resp.HasMore)
+	ass.Equal(2, len(resp.Events))
+
+	ass.Equal(int32(1), resp.Events[0].EventId)
+	ass.Equal(data_models.HistoryEventTypeProcessExecutionStarted, resp.Events[0].EventType)
+	ass.Equal(testProcessType, *resp.Events[0].Info.ProcessType)
+	ass.Equal(testWorkerUrl, *resp.Events[0].Info.WorkerURL)
+
+	ass.Equal(int32(2), resp.Events[1].EventId)
+	ass.Equal(data_models.HistoryEventTypeStateExecutionScheduled, resp.Events[1].EventType)
+	ass.Equal(stateId1+"-1", resp.Events[1].StateExecutionId.GetStateExecutionId())
+
+	// read the next page by the execution id
+	resp, err = store.GetProcessExecutionHistory(ctx, data_models.GetProcessExecutionHistoryRequest{
+		Namespace:           namespace,
+		ProcessExecutionId:  &prcExeId,
+		MinEventIdExclusive: 2,
+		PageSize:            2,
+	})
+	require.NoError(t, err)
+	ass.False(resp.HasMore)
+	ass.Equal(1, len(resp.Events))
+
+	ass.Equal(int32(3), resp.Events[0].EventId)
+	ass.Equal(data_models.HistoryEventTypeProcessExecutionClosed, resp.Events[0].EventType)
+	ass.Equal(string(xcapi.TERMINATED), *resp.Events[0].Info.ProcessStatus)
+
+	// non-existing process
+	resp, err = store.GetProcessExecutionHistory(ctx, data_models.GetProcessExecutionHistoryRequest{
+		Namespace: namespace,
+		ProcessId: "some-wrong-id",
+		PageSize:  2,
+	})
+	require.NoError(t, err)
+	ass.True(resp.NotExists)
+
+	// the process execution in another namespace
+	resp, err = store.GetProcessExecutionHistory(ctx, data_models.GetProcessExecutionHistoryRequest{
+		Namespace:          "some-wrong-namespace",
+		ProcessExecutionId: &prcExeId,
+		PageSize:           2,
+	})
+	require.NoError(t, err)
+	ass.True(resp.NotExists)
+}
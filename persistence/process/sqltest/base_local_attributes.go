@@ -0,0 +1,145 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLLocalAttributesTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+	value1 := xcapi.EncodedObject{Encoding: "test-encoding", Data: "value1"}
+	value2 := xcapi.EncodedObject{Encoding: "test-encoding", Data: "value2"}
+
+	startReq := createStartRequest(namespace, processId, input, nil, nil)
+	startReq.ProcessStartConfig.LocalAttributeConfig = &xcapi.LocalAttributeConfig{
+		InitialWrite: []xcapi.KeyValue{
+			{Key: "key1", Value: value1},
+		},
+	}
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        startReq,
+		NewTaskShardId: defaultShardId,
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+
+	minSeq, maxSeq, _ := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	getResp, err := store.GetLocalAttributes(ctx, data_models.GetLocalAttributesRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.False(getResp.NotExists)
+	ass.Equal(prcExeId.String(), getResp.ProcessExecutionId.String())
+	ass.Equal([]data_models.LocalAttribute{
+		{Key: "key1", Value: value1, Version: 1},
+	}, getResp.Attributes)
+
+	// the compare-and-set fails if any expected version doesn't match, and nothing is written
+	setResp, err := store.SetLocalAttributes(ctx, data_models.SetLocalAttributesRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: &prcExeId,
+		Attributes: []data_models.LocalAttributeWrite{
+			{Key: "key1", Value: value2, ExpectedVersion: ptr.Any(int64(1))},
+			{Key: "key2", Value: value2, ExpectedVersion: ptr.Any(int64(1))},
+		},
+	})
+	require.NoError(t, err)
+	ass.Equal([]data_models.LocalAttributeVersionConflict{
+		{Key: "key2", ExpectedVersion: 1, CurrentVersion: 0},
+	}, setResp.VersionConflicts)
+	ass.Empty(setResp.Attributes)
+
+	getResp, err = store.GetLocalAttributes(ctx, data_models.GetLocalAttributesRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		Keys:      []string{"key1", "key2"},
+	})
+	require.NoError(t, err)
+	ass.Equal([]data_models.LocalAttribute{
+		{Key: "key1", Value: value1, Version: 1},
+	}, getResp.Attributes)
+
+	// the compare-and-set succeeds, and the versions are increased
+	setResp, err = store.SetLocalAttributes(ctx, data_models.SetLocalAttributesRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		Attributes: []data_models.LocalAttributeWrite{
+			{Key: "key1", Value: value2, ExpectedVersion: ptr.Any(int64(1))},
+			{Key: "key2", Value: value2, ExpectedVersion: ptr.Any(int64(0))},
+		},
+		Reason: "test-reason",
+	})
+	require.NoError(t, err)
+	ass.Empty(setResp.VersionConflicts)
+	ass.Equal([]data_models.LocalAttribute{
+		{Key: "key1", Value: value2, Version: 2},
+		{Key: "key2", Value: value2, Version: 1},
+	}, setResp.Attributes)
+
+	// the local attributes of a closed process can be written without the compare-and-set
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	setResp, err = store.SetLocalAttributes(ctx, data_models.SetLocalAttributesRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		Attributes: []data_models.LocalAttributeWrite{
+			{Key: "key1", Value: value1},
+		},
+	})
+	require.NoError(t, err)
+	ass.Empty(setResp.VersionConflicts)
+
+	getResp, err = store.GetLocalAttributes(ctx, data_models.GetLocalAttributesRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: &prcExeId,
+	})
+	require.NoError(t, err)
+	ass.Equal([]data_models.LocalAttribute{
+		{Key: "key1", Value: value1, Version: 3},
+		{Key: "key2", Value: value2, Version: 1},
+	}, getResp.Attributes)
+
+	historyResp, err := store.GetProcessExecutionHistory(ctx, data_models.GetProcessExecutionHistoryRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		PageSize:  100,
+	})
+	require.NoError(t, err)
+	var updatedEvents []data_models.HistoryEventInfoJson
+	for _, event := range historyResp.Events {
+		if event.EventType == data_models.HistoryEventTypeLocalAttributesUpdated {
+			updatedEvents = append(updatedEvents, event.Info)
+		}
+	}
+	require.Equal(t, 2, len(updatedEvents))
+	ass.Equal([]string{"key1", "key2"}, updatedEvents[0].LocalAttributeKeys)
+	ass.Equal("test-reason", *updatedEvents[0].Reason)
+	ass.Equal([]string{"key1"}, updatedEvents[1].LocalAttributeKeys)
+
+	// the process execution is not visible from another namespace
+	getResp, err = store.GetLocalAttributes(ctx, data_models.GetLocalAttributesRequest{
+		Namespace:          "another-" + namespace,
+		ProcessExecutionId: &prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(getResp.NotExists)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLPauseProcessExecutionTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	_, _, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	verifyImmediateTaskNoInfo(ass, task, data_models.ImmediateTaskTypeWaitUntil, stateId1+"-1")
+
+	pauseResp, err := store.PauseProcessExecution(ctx, data_models.PauseProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		Reason:             "test",
+	})
+	require.NoError(t, err)
+	ass.Equal(data_models.PauseProcessExecutionResponse{}, *pauseResp)
+
+	pauseResp, err = store.PauseProcessExecution(ctx, data_models.PauseProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(pauseResp.AlreadyPaused)
+
+	descResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.True(descResp.Paused)
+
+	// the task of the paused process execution is parked instead of calling the worker
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	ass.True(prep.ProcessExecutionPaused)
+
+	parkResp, err := store.ParkImmediateTask(ctx, data_models.ParkImmediateTaskRequest{
+		Task: task,
+	})
+	require.NoError(t, err)
+	ass.True(parkResp.Parked)
+
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the parked task is inserted back on resume
+	resumeResp, err := store.ResumeProcessExecution(ctx, data_models.ResumeProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		Reason:             "test",
+	})
+	require.NoError(t, err)
+	ass.True(resumeResp.HasNewImmediateTask)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	verifyImmediateTaskNoInfo(ass, task, data_models.ImmediateTaskTypeWaitUntil, stateId1+"-1")
+	ass.Equal(prcExeId, task.ProcessExecutionId)
+
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	ass.False(prep.ProcessExecutionPaused)
+
+	resumeResp, err = store.ResumeProcessExecution(ctx, data_models.ResumeProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(resumeResp.NotPaused)
+
+	// the task is not parked if the process execution is resumed after preparing the state execution
+	parkResp, err = store.ParkImmediateTask(ctx, data_models.ParkImmediateTaskRequest{
+		Task: task,
+	})
+	require.NoError(t, err)
+	ass.False(parkResp.Parked)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+
+	pauseResp, err = store.PauseProcessExecution(ctx, data_models.PauseProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(pauseResp.ProcessNotRunning)
+}
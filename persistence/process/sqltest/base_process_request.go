@@ -0,0 +1,108 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLProcessRequestTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        createStartRequest(namespace, processId, input, nil, nil),
+		NewTaskShardId: defaultShardId,
+		RequestId:      "start-request",
+	})
+	require.NoError(t, err)
+	ass.False(startResp.AlreadyStarted)
+	prcExeId := startResp.ProcessExecutionId
+
+	getResp, err := store.GetProcessRequest(ctx, data_models.GetProcessRequestRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		RequestId: "start-request",
+	})
+	require.NoError(t, err)
+	ass.False(getResp.NotExists)
+	ass.Equal(data_models.ProcessRequestTypeStart, getResp.RequestType)
+	ass.Equal(startResp.ProcessExecutionId.String(), getResp.ProcessExecutionId.String())
+
+	// the request ids are not recorded for the failed starts
+	startResp, err = store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        createStartRequest(namespace, processId, input, nil, nil),
+		NewTaskShardId: defaultShardId,
+		RequestId:      "another-start-request",
+	})
+	require.NoError(t, err)
+	ass.True(startResp.AlreadyStarted)
+
+	getResp, err = store.GetProcessRequest(ctx, data_models.GetProcessRequestRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		RequestId: "another-start-request",
+	})
+	require.NoError(t, err)
+	ass.True(getResp.NotExists)
+
+	stopResp, err := store.StopProcess(ctx, data_models.StopProcessRequest{
+		Namespace:       namespace,
+		ProcessId:       processId,
+		ProcessStopType: xcapi.TERMINATE,
+		RequestId:       "stop-request",
+	})
+	require.NoError(t, err)
+	ass.False(stopResp.NotExists)
+
+	getResp, err = store.GetProcessRequest(ctx, data_models.GetProcessRequestRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		RequestId: "stop-request",
+	})
+	require.NoError(t, err)
+	ass.False(getResp.NotExists)
+	ass.Equal(data_models.ProcessRequestTypeStop, getResp.RequestType)
+	ass.Equal(stopResp.ProcessExecutionId.String(), getResp.ProcessExecutionId.String())
+
+	// the concurrent requests with the same request id return the recorded request, without writing anything
+	stopResp, err = store.StopProcess(ctx, data_models.StopProcessRequest{
+		Namespace:       namespace,
+		ProcessId:       processId,
+		ProcessStopType: xcapi.TERMINATE,
+		RequestId:       "stop-request",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, stopResp.DuplicateRequest)
+	ass.Equal(data_models.ProcessRequestTypeStop, stopResp.DuplicateRequest.RequestType)
+	ass.Equal(getResp.ProcessExecutionId.String(), stopResp.ProcessExecutionId.String())
+
+	startResp, err = store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        createStartRequest(namespace, processId, input, nil, nil),
+		NewTaskShardId: defaultShardId,
+		RequestId:      "start-request",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, startResp.DuplicateRequest)
+	ass.Equal(data_models.ProcessRequestTypeStart, startResp.DuplicateRequest.RequestType)
+	ass.Equal(prcExeId.String(), startResp.ProcessExecutionId.String())
+
+	latestResp, err := store.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(prcExeId.String(), latestResp.ProcessExecutionId.String())
+	ass.Equal(data_models.ProcessExecutionStatusTerminated, latestResp.Status)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLPublishToLocalQueueWithStartTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+	messages := []xcapi.LocalQueueMessage{
+		{QueueName: "q1", Payload: ptr.Any(input)},
+	}
+
+	// start a new process execution as the process doesn't exist
+	resp, err := store.PublishToLocalQueueWithStart(ctx, data_models.PublishToLocalQueueWithStartRequest{
+		StartRequest: data_models.StartProcessRequest{
+			Request:        createStartRequest(namespace, processId, input, nil, nil),
+			NewTaskShardId: defaultShardId,
+		},
+		Messages: messages,
+	})
+	require.NoError(t, err)
+	ass.True(resp.Started)
+	ass.False(resp.AlreadyStarted)
+	ass.True(resp.HasNewImmediateTask)
+	prcExeId := resp.ProcessExecutionId
+	// the worker task and visibility task of the start, and the local queue task
+	minSeq, maxSeq, tasks := checkAndGetImmediateTasks(ctx, t, ass, store, 3)
+	ass.Equal(data_models.ImmediateTaskTypeNewLocalQueueMessages, tasks[2].TaskType)
+	ass.Equal(prcExeId, tasks[2].ProcessExecutionId)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// publish to the running process execution
+	resp, err = store.PublishToLocalQueueWithStart(ctx, data_models.PublishToLocalQueueWithStartRequest{
+		StartRequest: data_models.StartProcessRequest{
+			Request:        createStartRequest(namespace, processId, input, nil, nil),
+			NewTaskShardId: defaultShardId,
+		},
+		Messages: messages,
+	})
+	require.NoError(t, err)
+	ass.False(resp.Started)
+	ass.True(resp.HasNewImmediateTask)
+	ass.Equal(prcExeId, resp.ProcessExecutionId)
+	minSeq, maxSeq, tasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(data_models.ImmediateTaskTypeNewLocalQueueMessages, tasks[0].TaskType)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the IdReusePolicy disallows starting a new process execution
+	resp, err = store.PublishToLocalQueueWithStart(ctx, data_models.PublishToLocalQueueWithStartRequest{
+		StartRequest: data_models.StartProcessRequest{
+			Request:        createStartRequestWithDisallowReusePolicy(namespace, processId, input),
+			NewTaskShardId: defaultShardId,
+		},
+		Messages: messages,
+	})
+	require.NoError(t, err)
+	ass.True(resp.AlreadyStarted)
+	ass.False(resp.Started)
+	checkAndGetImmediateTasks(ctx, t, ass, store, 0)
+
+	// start a new process execution as the previous one is closed
+	resp, err = store.PublishToLocalQueueWithStart(ctx, data_models.PublishToLocalQueueWithStartRequest{
+		StartRequest: data_models.StartProcessRequest{
+			Request:        createStartRequest(namespace, processId, input, nil, nil),
+			NewTaskShardId: defaultShardId,
+		},
+		Messages: messages,
+	})
+	require.NoError(t, err)
+	ass.True(resp.Started)
+	ass.NotEqual(prcExeId, resp.ProcessExecutionId)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 3)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+}
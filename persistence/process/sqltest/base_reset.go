@@ -0,0 +1,152 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+func SQLResetProcessExecutionTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+
+	// reset to a non-existing state execution
+	resp, err := store.ResetProcessExecution(ctx, data_models.ResetProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		ResetToStateExecutionId: data_models.StateExecutionId{
+			StateId:         stateId1,
+			StateIdSequence: 100,
+		},
+	})
+	require.NoError(t, err)
+	ass.True(resp.StateExecutionNotExists)
+
+	resp, err = store.ResetProcessExecution(ctx, data_models.ResetProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		ResetToStateExecutionId: data_models.StateExecutionId{
+			StateId:         stateId1,
+			StateIdSequence: 1,
+		},
+		Reason: "test",
+	})
+	require.NoError(t, err)
+	ass.True(resp.HasNewImmediateTask)
+	ass.Equal(stateId1+"-2", resp.NewStateExecutionId.GetStateExecutionId())
+
+	descResp, err := store.DescribeStateExecutions(ctx, data_models.DescribeStateExecutionsRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: &prcExeId,
+	})
+	require.NoError(t, err)
+	ass.Equal(2, len(descResp.StateExecutions))
+
+	ass.Equal(stateId1+"-1", descResp.StateExecutions[0].GetStateExecutionId())
+	ass.Equal(data_models.StateExecutionStatusAborted, descResp.StateExecutions[0].Status)
+	ass.False(descResp.StateExecutions[0].Pending)
+
+	ass.Equal(stateId1+"-2", descResp.StateExecutions[1].GetStateExecutionId())
+	ass.Equal(data_models.StateExecutionStatusWaitUntilRunning, descResp.StateExecutions[1].Status)
+	ass.True(descResp.StateExecutions[1].Pending)
+
+	// a closed process can not be reset
+	terminateProcess(ctx, t, ass, store, namespace, processId)
+
+	resp, err = store.ResetProcessExecution(ctx, data_models.ResetProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		ResetToStateExecutionId: data_models.StateExecutionId{
+			StateId:         stateId1,
+			StateIdSequence: 1,
+		},
+	})
+	require.NoError(t, err)
+	ass.True(resp.ProcessNotRunning)
+}
+
+func SQLResetGracefulCompleteTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, namespace, processId, input)
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeExecuteExecution(ctx, t, ass, store, prcExeId, task, prep, xcapi.StateDecision{
+		NextStates: []xcapi.StateMovement{
+			{
+				StateId:     stateId2,
+				StateConfig: &xcapi.AsyncStateConfig{SkipWaitUntil: ptr.Any(true)},
+			},
+			{
+				StateId:     stateId1,
+				StateConfig: &xcapi.AsyncStateConfig{SkipWaitUntil: ptr.Any(true)},
+			},
+		},
+	}, true)
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task = immediateTasks[1]
+	verifyImmediateTaskNoInfo(ass, task, data_models.ImmediateTaskTypeExecute, stateId1+"-2")
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the bad branch requests the graceful completion while stateId2 is still running
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeExecuteExecution(ctx, t, ass, store, prcExeId, task, prep, xcapi.StateDecision{
+		ThreadCloseDecision: &xcapi.ThreadCloseDecision{
+			CloseType: xcapi.GRACEFUL_COMPLETE_PROCESS,
+		},
+	}, false)
+
+	resp, err := store.ResetProcessExecution(ctx, data_models.ResetProcessExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		ResetToStateExecutionId: data_models.StateExecutionId{
+			StateId:         stateId1,
+			StateIdSequence: 1,
+		},
+		Reason: "test",
+	})
+	require.NoError(t, err)
+	ass.True(resp.HasNewImmediateTask)
+	ass.Equal(stateId1+"-3", resp.NewStateExecutionId.GetStateExecutionId())
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	verifyImmediateTaskNoInfo(ass, task, data_models.ImmediateTaskTypeWaitUntil, stateId1+"-3")
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	verifyStateExecution(ass, prep, processId, input, data_models.StateExecutionStatusWaitUntilRunning)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the reset branch ends in a dead end without completing the process
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeExecuteExecution(ctx, t, ass, store, prcExeId, task, prep, xcapi.StateDecision{
+		ThreadCloseDecision: &xcapi.ThreadCloseDecision{
+			CloseType: xcapi.DEAD_END,
+		},
+	}, false)
+	describeProcess(ctx, t, ass, store, namespace, processId, xcapi.RUNNING)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+// RetentionTestNamespace is the namespace that the store for testing should be configured with RetentionTestDuration
+const RetentionTestNamespace = "test-ns-retention"
+const RetentionTestDuration = time.Hour
+
+func SQLRetentionTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	prcExeId := startProcess(ctx, t, ass, store, RetentionTestNamespace, processId, input)
+	minSeq, maxSeq, _ := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// closing the process schedules the deletion after the retention
+	terminateProcess(ctx, t, ass, store, RetentionTestNamespace, processId)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	upToTimestamp := time.Now().Add(RetentionTestDuration).Unix() + 1
+	// the process timeout timer task, and the deletion timer task
+	_, _, timerTasks := getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 2, upToTimestamp)
+	timeoutTask := timerTasks[0]
+	deletionTask := timerTasks[1]
+	ass.Equal(data_models.TimerTaskTypeProcessTimeout, timeoutTask.TaskType)
+	ass.Equal(data_models.TimerTaskTypeDeleteProcessExecution, deletionTask.TaskType)
+	ass.Equal(prcExeId, deletionTask.ProcessExecutionId)
+	ass.True(deletionTask.FireTimestampSeconds >= time.Now().Add(RetentionTestDuration).Unix()-60)
+
+	// the closed process execution can be loaded for archival before the deletion
+	archivalResp, err := store.GetProcessExecutionForArchival(ctx, data_models.GetProcessExecutionForArchivalRequest{
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.False(archivalResp.NotExists)
+	record := archivalResp.Record
+	ass.Equal(RetentionTestNamespace, record.Namespace)
+	ass.Equal(processId, record.ProcessId)
+	ass.Equal(prcExeId.String(), record.ProcessExecutionId)
+	ass.Equal("test-type", record.ProcessType)
+	ass.Equal(xcapi.TERMINATED, record.Status)
+	ass.True(record.CloseTimestamp >= record.StartTimestamp)
+	ass.Equal(record.CloseTimestamp, record.CloseResult.CloseTimestamp)
+	ass.Equal(data_models.ProcessCloseReasonTypeStopRequest, record.CloseResult.ReasonType)
+	ass.Equal(1, len(record.StateExecutions))
+	ass.Equal(stateId1, record.StateExecutions[0].StateId)
+	ass.Equal(input, record.StateExecutions[0].Input)
+	ass.Equal(data_models.StateExecutionStatusAborted.String(), record.StateExecutions[0].Status)
+	ass.Equal(0, len(record.ConsumedMessages))
+	require.True(t, len(record.HistoryEvents) >= 2)
+	firstEvent, lastEvent := record.HistoryEvents[0], record.HistoryEvents[len(record.HistoryEvents)-1]
+	ass.Equal(int32(1), firstEvent.EventId)
+	ass.Equal(data_models.HistoryEventTypeProcessExecutionStarted.String(), firstEvent.EventType)
+	ass.Equal(int32(len(record.HistoryEvents)), lastEvent.EventId)
+	ass.Equal(data_models.HistoryEventTypeProcessExecutionClosed.String(), lastEvent.EventType)
+
+	resp, err := store.ProcessTimerTaskForDeleteProcessExecution(ctx, data_models.ProcessTimerTaskRequest{
+		Task: deletionTask,
+	})
+	require.NoError(t, err)
+	ass.True(resp.HasNewImmediateTask)
+
+	latestResp, err := store.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: RetentionTestNamespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.True(latestResp.NotExists)
+
+	descResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: RetentionTestNamespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.True(descResp.NotExists)
+
+	archivalResp, err = store.GetProcessExecutionForArchival(ctx, data_models.GetProcessExecutionForArchivalRequest{
+		ProcessExecutionId: prcExeId,
+	})
+	require.NoError(t, err)
+	ass.True(archivalResp.NotExists)
+
+	// the visibility record is deleted by the visibility task
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	ass.Equal(prcExeId, immediateTasks[0].ProcessExecutionId)
+	ass.True(immediateTasks[0].ImmediateTaskInfo.VisibilityInfo.Deleted)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+	getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 1, upToTimestamp)
+
+	// the timer tasks of the deleted process execution are noop
+	resp, err = store.ProcessTimerTaskForProcessTimeout(ctx, data_models.ProcessTimerTaskRequest{
+		Task: timeoutTask,
+	})
+	require.NoError(t, err)
+	ass.False(resp.HasNewImmediateTask)
+	resp, err = store.ProcessTimerTaskForDeleteProcessExecution(ctx, data_models.ProcessTimerTaskRequest{
+		Task: deletionTask,
+	})
+	require.NoError(t, err)
+	ass.False(resp.HasNewImmediateTask)
+	getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 0, upToTimestamp)
+
+	// terminating the running process by the id reuse policy also schedules the deletion
+	processId = fmt.Sprintf("test-prcid-%v", time.Now().String())
+	prcExeId = startProcess(ctx, t, ass, store, RetentionTestNamespace, processId, input)
+	startProcessWithTerminateIfRunningPolicy(ctx, t, ass, store, RetentionTestNamespace, processId, input)
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 5)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the process timeout timer tasks of both process executions, and the deletion timer task
+	_, _, timerTasks = getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 3, time.Now().Add(RetentionTestDuration).Unix()+1)
+	ass.Equal(data_models.TimerTaskTypeDeleteProcessExecution, timerTasks[2].TaskType)
+	ass.Equal(prcExeId, timerTasks[2].ProcessExecutionId)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLRpcCloseTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	waiterProcessId := processId + "-waiter"
+	input := createTestInput()
+	callbackUrl := "http://localhost:8080/callback"
+
+	// the state execution of the waiter process waits for the process to close
+	waiterPrcExeId := startProcess(ctx, t, ass, store, RetentionTestNamespace, waiterProcessId, input)
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep := prepareStateExecution(ctx, t, store, waiterPrcExeId, task.StateId, task.StateIdSequence)
+	waitResp, err := store.ProcessWaitUntilExecution(ctx, data_models.ProcessWaitUntilExecutionRequest{
+		ProcessExecutionId: waiterPrcExeId,
+		StateExecutionId: data_models.StateExecutionId{
+			StateId:         task.StateId,
+			StateIdSequence: task.StateIdSequence,
+		},
+		Prepare: *prep,
+		CommandRequest: xcapi.CommandRequest{
+			WaitingType: xcapi.ALL_OF_COMPLETION,
+		},
+		TaskShardId: defaultShardId,
+		ProcessCompletionCommands: []data_models.ProcessCompletionCommandJson{
+			{ProcessId: processId},
+		},
+	})
+	require.NoError(t, err)
+	ass.False(waitResp.HasNewImmediateTask)
+
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:                createStartRequest(RetentionTestNamespace, processId, input, nil, nil),
+		NewTaskShardId:         defaultShardId,
+		CompletionCallbackUrls: []string{callbackUrl},
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+	minSeq, maxSeq, _ = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// closing the process by the state decision of an Rpc
+	rpcResp, err := store.UpdateProcessExecutionForRpc(ctx, data_models.UpdateProcessExecutionForRpcRequest{
+		Namespace:          RetentionTestNamespace,
+		ProcessId:          processId,
+		ProcessType:        "test-type",
+		ProcessExecutionId: prcExeId,
+		RpcName:            "test-rpc",
+		StateDecision: xcapi.StateDecision{
+			ThreadCloseDecision: &xcapi.ThreadCloseDecision{
+				CloseType: xcapi.FORCE_FAIL_PROCESS,
+			},
+		},
+		WorkerUrl:   "test-url",
+		TaskShardId: defaultShardId,
+	})
+	require.NoError(t, err)
+	ass.False(rpcResp.ProcessNotExists)
+	ass.True(rpcResp.HasNewImmediateTask)
+
+	describeResp, err := store.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
+		Namespace: RetentionTestNamespace,
+		ProcessId: processId,
+	})
+	require.NoError(t, err)
+	ass.Equal(xcapi.FAILED, describeResp.Response.GetStatus())
+
+	// the visibility task, the task notifying the waiter, and the completion callback task
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 3)
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, immediateTasks[0].TaskType)
+	ass.Equal(prcExeId, immediateTasks[0].ProcessExecutionId)
+	visibilityInfo := immediateTasks[0].ImmediateTaskInfo.VisibilityInfo
+	ass.Equal(data_models.ProcessExecutionStatusFailed, visibilityInfo.Status)
+	// the close time signals the requests waiting for the completion, see signalProcessCompletion in the engine
+	ass.NotNil(visibilityInfo.CloseTime)
+	ass.Equal(&data_models.ProcessCloseRecordJson{
+		ReasonType: data_models.ProcessCloseReasonTypeStateDecision,
+	}, visibilityInfo.CloseRecord)
+
+	completionTask := immediateTasks[1]
+	verifyImmediateTaskNoInfo(ass, completionTask, data_models.ImmediateTaskTypeProcessCompletionCommand, stateId1+"-1")
+	ass.Equal(waiterPrcExeId, completionTask.ProcessExecutionId)
+	ass.Equal(xcapi.FAILED.Ptr(), completionTask.ImmediateTaskInfo.ProcessCompletionInfo.Result.ProcessStatus)
+
+	callbackTask := immediateTasks[2]
+	ass.Equal(data_models.ImmediateTaskTypeCompletionCallback, callbackTask.TaskType)
+	ass.Equal(prcExeId, callbackTask.ProcessExecutionId)
+	ass.Equal(callbackUrl, callbackTask.ImmediateTaskInfo.CompletionCallbackInfo.Url)
+	ass.Equal(xcapi.FAILED, callbackTask.ImmediateTaskInfo.CompletionCallbackInfo.Payload.Status)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// the process timeout timer tasks of both processes, and the deletion timer task
+	upToTimestamp := time.Now().Add(RetentionTestDuration).Unix() + 1
+	_, _, timerTasks := getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 3, upToTimestamp)
+	ass.Equal(data_models.TimerTaskTypeDeleteProcessExecution, timerTasks[2].TaskType)
+	ass.Equal(prcExeId, timerTasks[2].ProcessExecutionId)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLScheduleTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	scheduleId := fmt.Sprintf("test-scheduleid-%v", time.Now().String())
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().UnixNano())
+	interval := int64(3600)
+	info := data_models.ScheduleInfoJson{
+		Spec: data_models.ScheduleSpecJson{
+			IntervalSeconds: ptr.Any(interval),
+		},
+		StartRequest:  createStartRequest(namespace, processId, createTestInput(), nil, nil),
+		OverlapPolicy: data_models.ScheduleOverlapPolicySkip,
+	}
+
+	createResp, err := store.CreateSchedule(ctx, data_models.CreateScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+		ShardId:    defaultShardId,
+		Info:       info,
+	})
+	require.NoError(t, err)
+	ass.False(createResp.AlreadyExists)
+	firstFireTimestamp := createResp.NextFireTimestamp
+	ass.True(firstFireTimestamp > time.Now().Unix())
+	ass.Equal(int64(0), firstFireTimestamp%interval)
+
+	createResp, err = store.CreateSchedule(ctx, data_models.CreateScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+		ShardId:    defaultShardId,
+		Info:       info,
+	})
+	require.NoError(t, err)
+	ass.True(createResp.AlreadyExists)
+
+	schedule := describeSchedule(ctx, t, ass, store, scheduleId)
+	ass.False(schedule.Paused)
+	ass.Equal(info.Spec, schedule.Info.Spec)
+	ass.Equal(processId, schedule.Info.StartRequest.ProcessId)
+	ass.Equal(firstFireTimestamp, schedule.NextFireTimestamp)
+	ass.Equal(int32(1), schedule.Version)
+
+	_, _, timerTasks := getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 1, firstFireTimestamp)
+	task := timerTasks[0]
+	ass.Equal(data_models.TimerTaskTypeFireSchedule, task.TaskType)
+	ass.Equal(firstFireTimestamp, task.FireTimestampSeconds)
+	ass.Equal(&data_models.ScheduleTimerTaskInfoJson{
+		Namespace:       namespace,
+		ScheduleId:      scheduleId,
+		ScheduleVersion: 1,
+	}, task.TimerTaskInfo.ScheduleInfo)
+
+	// the firing starts the process with the deterministic processId
+	resp, err := store.ProcessTimerTaskForSchedule(ctx, data_models.ProcessTimerTaskRequest{Task: task})
+	require.NoError(t, err)
+	ass.True(resp.HasNewImmediateTask)
+	require.NotNil(t, resp.ProcessExecutionId)
+	secondFireTimestamp := firstFireTimestamp + interval
+	// the process timeout, and the next firing
+	ass.Equal(2, len(resp.NewTimerTaskFireTimestamps))
+	ass.Equal(secondFireTimestamp, resp.NewTimerTaskFireTimestamps[1])
+
+	firstProcessId := info.GetProcessIdForFireTimestamp(firstFireTimestamp)
+	describeProcess(ctx, t, ass, store, namespace, firstProcessId, xcapi.RUNNING)
+	minSeq, maxSeq, _ := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	schedule = describeSchedule(ctx, t, ass, store, scheduleId)
+	ass.Equal(secondFireTimestamp, schedule.NextFireTimestamp)
+	ass.Equal(firstFireTimestamp, schedule.LastFireTimestamp)
+	ass.Equal(firstProcessId, schedule.LastProcessId)
+	ass.Equal(int32(1), schedule.FiredCount)
+	ass.Equal(int32(2), schedule.Version)
+
+	// the next firing is skipped as the previous process is still running
+	_, _, timerTasks = getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 2, secondFireTimestamp)
+	ass.Equal(data_models.TimerTaskTypeProcessTimeout, timerTasks[0].TaskType)
+	task = timerTasks[1]
+	ass.Equal(data_models.TimerTaskTypeFireSchedule, task.TaskType)
+	ass.Equal(int32(2), task.TimerTaskInfo.ScheduleInfo.ScheduleVersion)
+	resp, err = store.ProcessTimerTaskForSchedule(ctx, data_models.ProcessTimerTaskRequest{Task: task})
+	require.NoError(t, err)
+	ass.False(resp.HasNewImmediateTask)
+	ass.Nil(resp.ProcessExecutionId)
+	ass.Equal([]int64{secondFireTimestamp + interval}, resp.NewTimerTaskFireTimestamps)
+	checkAndGetImmediateTasks(ctx, t, ass, store, 0)
+
+	schedule = describeSchedule(ctx, t, ass, store, scheduleId)
+	ass.Equal(firstProcessId, schedule.LastProcessId)
+	ass.Equal(int32(1), schedule.FiredCount)
+	ass.Equal(int32(1), schedule.SkippedCount)
+
+	// the pending timer task becomes stale after pausing
+	_, _, timerTasks = getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 2, secondFireTimestamp+interval)
+	task = timerTasks[1]
+	updateResp, err := store.UpdateSchedule(ctx, data_models.UpdateScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+		Paused:     ptr.Any(true),
+	})
+	require.NoError(t, err)
+	ass.False(updateResp.NotExists)
+	ass.Equal(int64(0), updateResp.NextFireTimestamp)
+	schedule = describeSchedule(ctx, t, ass, store, scheduleId)
+	ass.True(schedule.Paused)
+	ass.Equal(int64(0), schedule.NextFireTimestamp)
+
+	resp, err = store.ProcessTimerTaskForSchedule(ctx, data_models.ProcessTimerTaskRequest{Task: task})
+	require.NoError(t, err)
+	ass.False(resp.HasNewImmediateTask)
+	ass.Equal(0, len(resp.NewTimerTaskFireTimestamps))
+	// only the process timeout is left
+	getAndCheckTimerTasksUpToTs(ctx, t, ass, store, 1, secondFireTimestamp+interval)
+	schedule = describeSchedule(ctx, t, ass, store, scheduleId)
+	ass.Equal(int32(1), schedule.SkippedCount)
+
+	// the backfill skips the fire time already started, and stops at the max firings
+	backfillResp, err := store.BackfillSchedule(ctx, data_models.BackfillScheduleRequest{
+		Namespace:      namespace,
+		ScheduleId:     scheduleId,
+		StartTimestamp: firstFireTimestamp - 1,
+		EndTimestamp:   firstFireTimestamp + 2*interval,
+		MaxFirings:     2,
+	})
+	require.NoError(t, err)
+	ass.Equal(int32(1), backfillResp.SkippedCount)
+	ass.Equal(1, len(backfillResp.StartedProcessExecutionIds))
+	ass.Equal(1, len(backfillResp.TimeoutTimestamps))
+	ass.Equal(firstFireTimestamp+2*interval-1, backfillResp.NextStartTimestamp)
+	describeProcess(ctx, t, ass, store, namespace, info.GetProcessIdForFireTimestamp(secondFireTimestamp), xcapi.RUNNING)
+
+	backfillResp, err = store.BackfillSchedule(ctx, data_models.BackfillScheduleRequest{
+		Namespace:      namespace,
+		ScheduleId:     scheduleId,
+		StartTimestamp: backfillResp.NextStartTimestamp,
+		EndTimestamp:   firstFireTimestamp + 2*interval,
+		MaxFirings:     2,
+	})
+	require.NoError(t, err)
+	ass.Equal(int32(0), backfillResp.SkippedCount)
+	ass.Equal(1, len(backfillResp.StartedProcessExecutionIds))
+	ass.Equal(int64(0), backfillResp.NextStartTimestamp)
+	describeProcess(ctx, t, ass, store, namespace, info.GetProcessIdForFireTimestamp(secondFireTimestamp+interval), xcapi.RUNNING)
+
+	// resuming schedules the next firing again
+	updateResp, err = store.UpdateSchedule(ctx, data_models.UpdateScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+		Paused:     ptr.Any(false),
+	})
+	require.NoError(t, err)
+	ass.Equal(firstFireTimestamp, updateResp.NextFireTimestamp)
+
+	deleteResp, err := store.DeleteSchedule(ctx, data_models.DeleteScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+	})
+	require.NoError(t, err)
+	ass.False(deleteResp.NotExists)
+	describeResp, err := store.DescribeSchedule(ctx, data_models.DescribeScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+	})
+	require.NoError(t, err)
+	ass.True(describeResp.NotExists)
+	deleteResp, err = store.DeleteSchedule(ctx, data_models.DeleteScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+	})
+	require.NoError(t, err)
+	ass.True(deleteResp.NotExists)
+}
+
+func describeSchedule(
+	ctx context.Context, t *testing.T, ass *assert.Assertions, store persistence.ProcessStore, scheduleId string,
+) data_models.Schedule {
+	resp, err := store.DescribeSchedule(ctx, data_models.DescribeScheduleRequest{
+		Namespace:  namespace,
+		ScheduleId: scheduleId,
+	})
+	require.NoError(t, err)
+	ass.False(resp.NotExists)
+	return resp.Schedule
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/persistence"
+)
+
+func SQLSearchAttributesTest(t *testing.T, ass *assert.Assertions, store persistence.ProcessStore) {
+	ctx := context.Background()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	input := createTestInput()
+
+	startResp, err := store.StartProcess(ctx, data_models.StartProcessRequest{
+		Request:        createStartRequest(namespace, processId, input, nil, nil),
+		NewTaskShardId: defaultShardId,
+		SearchAttributes: map[string]interface{}{
+			"customerId": "customer-1",
+		},
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+
+	// the initial search attributes are recorded with the visibility task of starting process
+	minSeq, maxSeq, immediateTasks := checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	task := immediateTasks[0]
+	visibilityTask := immediateTasks[1]
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, visibilityTask.TaskType)
+	ass.Equal(data_models.ProcessExecutionStatusRunning, visibilityTask.ImmediateTaskInfo.VisibilityInfo.Status)
+	ass.NotNil(visibilityTask.ImmediateTaskInfo.VisibilityInfo.StartTime)
+	ass.Equal(map[string]interface{}{"customerId": "customer-1"},
+		visibilityTask.ImmediateTaskInfo.VisibilityInfo.SearchAttributes)
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	prep := prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	completeWaitUntilExecution(ctx, t, ass, store, prcExeId, task, prep)
+
+	minSeq, maxSeq, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 1)
+	task = immediateTasks[0]
+	deleteAndVerifyImmediateTasksDeleted(ctx, t, ass, store, minSeq, maxSeq)
+
+	// upsert the search attributes from the state decision
+	prep = prepareStateExecution(ctx, t, store, prcExeId, task.StateId, task.StateIdSequence)
+	compResp, err := store.CompleteExecuteExecution(ctx, data_models.CompleteExecuteExecutionRequest{
+		ProcessExecutionId: prcExeId,
+		StateExecutionId: data_models.StateExecutionId{
+			StateId:         task.StateId,
+			StateIdSequence: task.StateIdSequence,
+		},
+		Prepare: *prep,
+		StateDecision: xcapi.StateDecision{
+			NextStates: []xcapi.StateMovement{
+				{
+					StateId:     stateId2,
+					StateConfig: &xcapi.AsyncStateConfig{SkipWaitUntil: ptr.Any(true)},
+				},
+			},
+		},
+		TaskShardId: defaultShardId,
+		UpsertSearchAttributes: map[string]interface{}{
+			"customerId": nil,
+			"orderId":    "order-1",
+		},
+	})
+	require.NoError(t, err)
+	ass.True(compResp.HasNewImmediateTask)
+
+	_, _, immediateTasks = checkAndGetImmediateTasks(ctx, t, ass, store, 2)
+	verifyImmediateTaskNoInfo(ass, immediateTasks[0], data_models.ImmediateTaskTypeExecute, stateId2+"-1")
+	visibilityTask = immediateTasks[1]
+	ass.Equal(data_models.ImmediateTaskTypeVisibility, visibilityTask.TaskType)
+	ass.Equal(data_models.ProcessExecutionStatusRunning, visibilityTask.ImmediateTaskInfo.VisibilityInfo.Status)
+	ass.Nil(visibilityTask.ImmediateTaskInfo.VisibilityInfo.StartTime)
+	ass.Equal(map[string]interface{}{"customerId": nil, "orderId": "order-1"},
+		visibilityTask.ImmediateTaskInfo.VisibilityInfo.SearchAttributes)
+}
@@ -38,6 +38,20 @@ func (p sqlProcessStoreImpl) StartProcess(
 			return nil, err
 		}
 	}
+
+	if err != nil {
+		duplicateRequest, err2 := p.getConcurrentProcessRequest(
+			ctx, err, request.Request.Namespace, request.Request.ProcessId, request.RequestId)
+		if err2 != nil {
+			return nil, err2
+		}
+		if duplicateRequest != nil {
+			return &data_models.StartProcessResponse{
+				ProcessExecutionId: duplicateRequest.ProcessExecutionId,
+				DuplicateRequest:   duplicateRequest,
+			}, nil
+		}
+	}
 	return resp, err
 }
 
@@ -81,9 +95,17 @@ func (p sqlProcessStoreImpl) doStartProcessTx(
 		return nil, errStartProcess
 	}
 
-	err = p.handleInitialLocalAttributesWrite(ctx, tx, req, *resp)
-	if err != nil {
-		return nil, err
+	if !resp.AlreadyStarted {
+		err = p.handleInitialLocalAttributesWrite(ctx, tx, req, *resp)
+		if err != nil {
+			return nil, err
+		}
+
+		err = insertProcessRequest(ctx, tx, req.Namespace, req.ProcessId, request.RequestId,
+			data_models.ProcessRequestTypeStart, resp.ProcessExecutionId, data_models.ProcessRequestResponseJson{})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return resp, nil
@@ -299,28 +321,23 @@ func (p sqlProcessStoreImpl) applyTerminateIfRunningPolicy(
 		}
 		// mark the process as terminated
 		if processExecutionRowForUpdate.Status == data_models.ProcessExecutionStatusRunning {
-			err = tx.UpdateProcessExecution(ctx, extensions.ProcessExecutionRowForUpdate{
-				ProcessExecutionId:         processExecutionRowForUpdate.ProcessExecutionId,
-				Status:                     data_models.ProcessExecutionStatusTerminated,
-				HistoryEventIdSequence:     processExecutionRowForUpdate.HistoryEventIdSequence,
-				StateExecutionSequenceMaps: processExecutionRowForUpdate.StateExecutionSequenceMaps,
-				StateExecutionLocalQueues:  processExecutionRowForUpdate.StateExecutionLocalQueues,
-				GracefulCompleteRequested:  processExecutionRowForUpdate.GracefulCompleteRequested,
+			err = p.closeProcessExecution(ctx, tx, processExecutionRowForUpdate, closeProcessExecutionRequest{
+				Namespace:   request.Request.Namespace,
+				ProcessId:   request.Request.ProcessId,
+				ProcessType: request.Request.ProcessType,
+				Status:      data_models.ProcessExecutionStatusTerminated,
+				CloseResult: data_models.ProcessCloseResultJson{
+					ProcessCloseRecordJson: data_models.ProcessCloseRecordJson{
+						ReasonType: data_models.ProcessCloseReasonTypeIdReusePolicy,
+					},
+				},
+				TaskShardId: request.NewTaskShardId,
 			})
 			if err != nil {
 				return nil, err
 			}
-			err = p.AddVisibilityTaskRecordProcessExecutionStatus(
-				ctx,
-				tx,
-				request.NewTaskShardId,
-				request.Request.Namespace,
-				request.Request.ProcessId,
-				request.Request.ProcessType,
-				processExecutionRowForUpdate.ProcessExecutionId,
-				data_models.ProcessExecutionStatusTerminated,
-				nil,
-				ptr.Any(time.Now().Unix()))
+
+			err = tx.UpdateProcessExecution(ctx, *processExecutionRowForUpdate)
 			if err != nil {
 				return nil, err
 			}
@@ -443,7 +460,18 @@ func (p sqlProcessStoreImpl) insertProcessExecution(
 		timeoutSeconds = sc.GetTimeoutSeconds()
 	}
 
-	processExeInfoBytes, err := data_models.FromStartRequestToProcessInfoBytes(req)
+	processExeInfoBytes, err := data_models.FromStartRequestToProcessInfoBytes(request)
+	if err != nil {
+		return false, err
+	}
+
+	historyEventIdSequence := int32(0)
+	err = insertHistoryEvent(ctx, tx, processExecutionId, &historyEventIdSequence,
+		data_models.HistoryEventTypeProcessExecutionStarted, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			ProcessType: ptr.Any(req.GetProcessType()),
+			WorkerURL:   ptr.Any(req.GetWorkerUrl()),
+		})
 	if err != nil {
 		return false, err
 	}
@@ -469,7 +497,20 @@ func (p sqlProcessStoreImpl) insertProcessExecution(
 			return false, err
 		}
 
-		err = insertImmediateTask(ctx, tx, processExecutionId, stateId, 1, stateConfig, request.NewTaskShardId)
+		if request.DelayedStartTimeUnixSeconds != 0 {
+			err = insertDelayedStartTimerTask(ctx, tx, processExecutionId, stateId, 1, stateConfig,
+				request.NewTaskShardId, request.DelayedStartTimeUnixSeconds)
+		} else {
+			err = insertImmediateTask(ctx, tx, processExecutionId, stateId, 1, stateConfig, request.NewTaskShardId)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		err = insertHistoryEvent(ctx, tx, processExecutionId, &historyEventIdSequence,
+			data_models.HistoryEventTypeStateExecutionScheduled,
+			data_models.StateExecutionId{StateId: stateId, StateIdSequence: int32(stateIdSeq)},
+			data_models.HistoryEventInfoJson{})
 		if err != nil {
 			return false, err
 		}
@@ -494,7 +535,7 @@ func (p sqlProcessStoreImpl) insertProcessExecution(
 
 		ShardId:                    request.NewTaskShardId,
 		Status:                     data_models.ProcessExecutionStatusRunning,
-		HistoryEventIdSequence:     0,
+		HistoryEventIdSequence:     historyEventIdSequence,
 		StateExecutionSequenceMaps: sequenceMapsBytes,
 		StateExecutionLocalQueues:  localQueuesBytes,
 		Namespace:                  req.Namespace,
@@ -511,17 +552,25 @@ func (p sqlProcessStoreImpl) insertProcessExecution(
 		return hasNewImmediateTask, err
 	}
 
-	err = p.AddVisibilityTaskRecordProcessExecutionStatus(
-		ctx,
-		tx,
-		request.NewTaskShardId,
-		request.Request.Namespace,
-		request.Request.ProcessId,
-		request.Request.ProcessType,
-		processExecutionId,
-		data_models.ProcessExecutionStatusRunning,
-		ptr.Any(startTime.Unix()),
-		nil)
+	err = insertCompletionCallbacks(ctx, tx, processExecutionId,
+		request.CompletionCallbackUrls, request.CompletionCallbackRetryPolicy)
+	if err != nil {
+		return hasNewImmediateTask, err
+	}
+
+	visibilityInfo := data_models.VisibilityInfoJson{
+		Namespace:          request.Request.Namespace,
+		ProcessId:          request.Request.ProcessId,
+		ProcessType:        request.Request.ProcessType,
+		ProcessExecutionId: processExecutionId,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		StartTime:          ptr.Any(startTime.Unix()),
+		SearchAttributes:   request.SearchAttributes,
+	}
+	if request.DelayedStartTimeUnixSeconds != 0 {
+		visibilityInfo.DelayedStartTime = ptr.Any(request.DelayedStartTimeUnixSeconds)
+	}
+	err = p.addVisibilityTask(ctx, tx, request.NewTaskShardId, visibilityInfo)
 	if err != nil {
 		return hasNewImmediateTask, err
 	}
@@ -6,9 +6,7 @@ package process
 import (
 	"context"
 	"github.com/xcherryio/apis/goapi/xcapi"
-	"github.com/xcherryio/xcherry/common/ptr"
 	"github.com/xcherryio/xcherry/persistence/data_models"
-	"time"
 
 	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/extensions"
@@ -29,7 +27,22 @@ func (p sqlProcessStoreImpl) StopProcess(
 		status = data_models.ProcessExecutionStatusFailed
 	}
 
-	resp, err := p.doStopProcessTx(ctx, tx, namespace, processId, request.NewTaskShardId, status)
+	closeRecord := data_models.ProcessCloseRecordJson{
+		ReasonType: data_models.ProcessCloseReasonTypeStopRequest,
+		StopReason: request.Reason,
+	}
+	if request.Failure != nil {
+		closeRecord = data_models.ProcessCloseRecordJson{
+			ReasonType: data_models.ProcessCloseReasonTypeStateFailure,
+			Failure:    request.Failure,
+		}
+	}
+
+	resp, err := p.doStopProcessTx(ctx, tx, namespace, processId, request.NewTaskShardId, status, closeRecord)
+	if err == nil && !resp.NotExists {
+		err = insertProcessRequest(ctx, tx, namespace, processId, request.RequestId,
+			data_models.ProcessRequestTypeStop, resp.ProcessExecutionId, data_models.ProcessRequestResponseJson{})
+	}
 	if err != nil {
 		err2 := tx.Rollback()
 		if err2 != nil {
@@ -43,12 +56,24 @@ func (p sqlProcessStoreImpl) StopProcess(
 		}
 	}
 
+	if err != nil {
+		duplicateRequest, err2 := p.getConcurrentProcessRequest(ctx, err, namespace, processId, request.RequestId)
+		if err2 != nil {
+			return nil, err2
+		}
+		if duplicateRequest != nil {
+			return &data_models.StopProcessResponse{
+				ProcessExecutionId: duplicateRequest.ProcessExecutionId,
+				DuplicateRequest:   duplicateRequest,
+			}, nil
+		}
+	}
 	return resp, err
 }
 
 func (p sqlProcessStoreImpl) doStopProcessTx(
 	ctx context.Context, tx extensions.SQLTransaction, namespace string, processId string, newTaskShardId int32,
-	status data_models.ProcessExecutionStatus,
+	status data_models.ProcessExecutionStatus, closeRecord data_models.ProcessCloseRecordJson,
 ) (*data_models.StopProcessResponse, error) {
 	curProcExecRow, err := p.session.SelectLatestProcessExecution(ctx, namespace, processId)
 	if err != nil {
@@ -69,7 +94,8 @@ func (p sqlProcessStoreImpl) doStopProcessTx(
 
 	if procExecRow.Status != data_models.ProcessExecutionStatusRunning {
 		return &data_models.StopProcessResponse{
-			NotExists: false,
+			NotExists:          false,
+			ProcessExecutionId: curProcExecRow.ProcessExecutionId,
 		}, nil
 	}
 
@@ -86,7 +112,24 @@ func (p sqlProcessStoreImpl) doStopProcessTx(
 		return nil, err
 	}
 
-	procExecRow.Status = status
+	procExecInfoJson, err := data_models.BytesToProcessExecutionInfo(curProcExecRow.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.closeProcessExecution(ctx, tx, procExecRow, closeProcessExecutionRequest{
+		Namespace:   namespace,
+		ProcessId:   processId,
+		ProcessType: procExecInfoJson.ProcessType,
+		Status:      status,
+		CloseResult: data_models.ProcessCloseResultJson{
+			ProcessCloseRecordJson: closeRecord,
+		},
+		TaskShardId: newTaskShardId,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	err = tx.UpdateProcessExecution(ctx, *procExecRow)
 	if err != nil {
@@ -103,28 +146,8 @@ func (p sqlProcessStoreImpl) doStopProcessTx(
 		}
 	}
 
-	procExecInfoJson, err := data_models.BytesToProcessExecutionInfo(curProcExecRow.Info)
-	if err != nil {
-		return nil, err
-	}
-
-	err = p.AddVisibilityTaskRecordProcessExecutionStatus(
-		ctx,
-		tx,
-		newTaskShardId,
-		namespace,
-		processId,
-		procExecInfoJson.ProcessType,
-		curProcExecRow.ProcessExecutionId,
-		status,
-		nil,
-		ptr.Any(time.Now().Unix()),
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	return &data_models.StopProcessResponse{
-		NotExists: false,
+		NotExists:          false,
+		ProcessExecutionId: curProcExecRow.ProcessExecutionId,
 	}, nil
 }
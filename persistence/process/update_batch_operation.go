@@ -0,0 +1,42 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"time"
+)
+
+func (p sqlProcessStoreImpl) UpdateBatchOperation(
+	ctx context.Context, request data_models.UpdateBatchOperationRequest,
+) (*data_models.UpdateBatchOperationResponse, error) {
+	batchOperation := request.BatchOperation
+
+	err := p.session.UpdateBatchOperation(ctx, extensions.BatchOperationRow{
+		Namespace:        batchOperation.Namespace,
+		BatchOperationId: batchOperation.BatchOperationId,
+		Status:           batchOperation.Status,
+		NextPageToken:    batchOperation.NextPageToken,
+		SucceededCount:   batchOperation.SucceededCount,
+		FailedCount:      batchOperation.FailedCount,
+		SkippedCount:     batchOperation.SkippedCount,
+		ErrorMessage:     batchOperation.ErrorMessage,
+		Owner:            batchOperation.Owner,
+		LeaseExpireTime:  time.Unix(batchOperation.LeaseExpireTimestamp, 0),
+		PreviousVersion:  batchOperation.Version,
+		LastUpdateTime:   time.Now(),
+	})
+	if err != nil {
+		if p.session.IsConditionalUpdateFailure(err) {
+			return &data_models.UpdateBatchOperationResponse{
+				VersionConflict: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	return &data_models.UpdateBatchOperationResponse{}, nil
+}
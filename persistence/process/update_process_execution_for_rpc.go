@@ -5,6 +5,7 @@ package process
 
 import (
 	"context"
+
 	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/extensions"
 	"github.com/xcherryio/xcherry/persistence/data_models"
@@ -34,6 +35,17 @@ func (p sqlProcessStoreImpl) UpdateProcessExecutionForRpc(
 		}
 	}
 
+	if err != nil {
+		duplicateRequest, err2 := p.getConcurrentProcessRequest(ctx, err, request.Namespace, request.ProcessId, request.RequestId)
+		if err2 != nil {
+			return nil, err2
+		}
+		if duplicateRequest != nil {
+			return &data_models.UpdateProcessExecutionForRpcResponse{
+				DuplicateRequest: duplicateRequest,
+			}, nil
+		}
+	}
 	return resp, err
 }
 
@@ -73,6 +85,16 @@ func (p sqlProcessStoreImpl) doUpdateProcessExecutionForRpcTx(
 		return nil, err
 	}
 
+	err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeRpcDecisionApplied, data_models.StateExecutionId{},
+		data_models.HistoryEventInfoJson{
+			RpcName:       &request.RpcName,
+			StateDecision: &request.StateDecision,
+		})
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := p.handleStateDecision(ctx, tx, HandleStateDecisionRequest{
 		Namespace:          request.Namespace,
 		ProcessId:          request.ProcessId,
@@ -85,6 +107,7 @@ func (p sqlProcessStoreImpl) doUpdateProcessExecutionForRpcTx(
 		ProcessExecutionRowStateExecutionSequenceMaps: &sequenceMaps,
 		ProcessExecutionRowGracefulCompleteRequested:  prcRow.GracefulCompleteRequested,
 		ProcessExecutionRowStatus:                     prcRow.Status,
+		ProcessExecutionRowHistoryEventIdSequence:     prcRow.HistoryEventIdSequence,
 
 		TaskShardId: request.TaskShardId,
 	})
@@ -96,12 +119,35 @@ func (p sqlProcessStoreImpl) doUpdateProcessExecutionForRpcTx(
 	}
 
 	prcRow.GracefulCompleteRequested = resp.ProcessExecutionRowNewGracefulCompleteRequested
-	prcRow.Status = resp.ProcessExecutionRowNewStatus
+	prcRow.HistoryEventIdSequence = resp.ProcessExecutionRowNewHistoryEventIdSequence
 	prcRow.StateExecutionSequenceMaps, err = resp.ProcessExecutionRowNewStateExecutionSequenceMaps.ToBytes()
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.ProcessExecutionRowNewStatus != data_models.ProcessExecutionStatusRunning {
+		closeResult := data_models.ProcessCloseResultJson{
+			ProcessCloseRecordJson: data_models.ProcessCloseRecordJson{
+				ReasonType: data_models.ProcessCloseReasonTypeStateDecision,
+			},
+		}
+		if request.StateDecision.HasThreadCloseDecision() {
+			closeResult.Output = request.StateDecision.GetThreadCloseDecision().CloseInput
+		}
+		err = p.closeProcessExecution(ctx, tx, prcRow, closeProcessExecutionRequest{
+			Namespace:   request.Namespace,
+			ProcessId:   request.ProcessId,
+			ProcessType: request.ProcessType,
+			Status:      resp.ProcessExecutionRowNewStatus,
+			CloseResult: closeResult,
+			TaskShardId: request.TaskShardId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		hasNewImmediateTask = true
+	}
+
 	err = tx.UpdateProcessExecution(ctx, *prcRow)
 	if err != nil {
 		return nil, err
@@ -117,6 +163,34 @@ func (p sqlProcessStoreImpl) doUpdateProcessExecutionForRpcTx(
 		hasNewImmediateTask = true
 	}
 
+	// Step 4: upsert the search attributes
+
+	if len(request.UpsertSearchAttributes) > 0 {
+		err = p.AddVisibilityTaskUpsertSearchAttributes(
+			ctx,
+			tx,
+			request.TaskShardId,
+			request.Namespace,
+			request.ProcessId,
+			request.ProcessType,
+			request.ProcessExecutionId,
+			request.UpsertSearchAttributes)
+		if err != nil {
+			return nil, err
+		}
+		hasNewImmediateTask = true
+	}
+
+	// Step 5: record the request id with the output
+
+	err = insertProcessRequest(ctx, tx, request.Namespace, request.ProcessId, request.RequestId,
+		data_models.ProcessRequestTypeRpc, request.ProcessExecutionId, data_models.ProcessRequestResponseJson{
+			RpcOutput: request.RpcOutput,
+		})
+	if err != nil {
+		return nil, err
+	}
+
 	return &data_models.UpdateProcessExecutionForRpcResponse{
 		HasNewImmediateTask: hasNewImmediateTask,
 	}, nil
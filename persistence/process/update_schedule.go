@@ -0,0 +1,95 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/extensions"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func (p sqlProcessStoreImpl) UpdateSchedule(
+	ctx context.Context, request data_models.UpdateScheduleRequest,
+) (*data_models.UpdateScheduleResponse, error) {
+	tx, err := p.session.StartTransaction(ctx, defaultTxOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.doUpdateScheduleTx(ctx, tx, request)
+	if err != nil || resp.NotExists {
+		err2 := tx.Rollback()
+		if err2 != nil {
+			p.logger.Error("error on rollback transaction", tag.Error(err2))
+		}
+	} else {
+		err = tx.Commit()
+		if err != nil {
+			p.logger.Error("error on committing transaction", tag.Error(err))
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+func (p sqlProcessStoreImpl) doUpdateScheduleTx(
+	ctx context.Context, tx extensions.SQLTransaction, request data_models.UpdateScheduleRequest,
+) (*data_models.UpdateScheduleResponse, error) {
+	row, err := tx.SelectScheduleForUpdate(ctx, request.Namespace, request.ScheduleId)
+	if err != nil {
+		if p.session.IsNotFoundError(err) {
+			return &data_models.UpdateScheduleResponse{
+				NotExists: true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	info, err := data_models.BytesToScheduleInfo(row.Info)
+	if err != nil {
+		return nil, err
+	}
+	if request.Info != nil {
+		info = *request.Info
+		row.Info, err = info.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if request.Paused != nil {
+		row.Paused = *request.Paused
+	}
+
+	// the next fire time is always recalculated, and the existing timer task becomes stale because of the new version
+	now := time.Now()
+	row.NextFireTimeUnixSeconds = 0
+	if !row.Paused {
+		row.NextFireTimeUnixSeconds, err = info.Spec.GetNextFireTimestamp(now.Unix())
+		if err != nil {
+			return nil, err
+		}
+	}
+	row.LastUpdateTime = now
+
+	err = tx.UpdateSchedule(ctx, *row)
+	if err != nil {
+		return nil, err
+	}
+
+	if row.NextFireTimeUnixSeconds > 0 {
+		err = p.addFireScheduleTimerTask(
+			ctx, tx, row.ShardId, row.Namespace, row.ScheduleId, row.PreviousVersion+1, row.NextFireTimeUnixSeconds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &data_models.UpdateScheduleResponse{
+		ShardId:           row.ShardId,
+		NextFireTimestamp: row.NextFireTimeUnixSeconds,
+	}, nil
+}
@@ -43,6 +43,21 @@ func (p sqlProcessStoreImpl) doProcessWaitUntilExecutionTx(
 	hasNewImmediateTask := false
 	var fireTimestamps []int64
 
+	// lock process execution row first
+	prcRow, err := tx.SelectProcessExecutionForUpdate(ctx, request.ProcessExecutionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertHistoryEvent(ctx, tx, request.ProcessExecutionId, &prcRow.HistoryEventIdSequence,
+		data_models.HistoryEventTypeStateWaitUntilCompleted, request.StateExecutionId,
+		data_models.HistoryEventInfoJson{
+			CommandRequest: &request.CommandRequest,
+		})
+	if err != nil {
+		return nil, err
+	}
+
 	if request.CommandRequest.GetWaitingType() == xcapi.EMPTY_COMMAND {
 		hasNewImmediateTask = true
 		err := p.completeWaitUntilExecution(ctx, tx, data_models.CompleteWaitUntilExecutionRequest{
@@ -55,7 +70,7 @@ func (p sqlProcessStoreImpl) doProcessWaitUntilExecutionTx(
 			return nil, err
 		}
 	} else {
-		resp, err := p.updateWaitUntilExecution(ctx, tx, request)
+		resp, err := p.updateWaitUntilExecution(ctx, tx, prcRow, request)
 		if err != nil {
 			return nil, err
 		}
@@ -66,6 +81,11 @@ func (p sqlProcessStoreImpl) doProcessWaitUntilExecutionTx(
 		fireTimestamps = resp.FireTimestamps
 	}
 
+	err = tx.UpdateProcessExecution(ctx, *prcRow)
+	if err != nil {
+		return nil, err
+	}
+
 	hasNewImmediateTask2, err := p.publishToLocalQueue(ctx, tx, request.ProcessExecutionId, request.TaskShardId, request.PublishToLocalQueue)
 	if err != nil {
 		return nil, err
@@ -117,25 +137,21 @@ func (p sqlProcessStoreImpl) completeWaitUntilExecution(
 	})
 }
 
+// updateWaitUntilExecution expects the process execution row to be locked by the caller,
+// and the caller is responsible for writing the changes of the row back.
 func (p sqlProcessStoreImpl) updateWaitUntilExecution(
-	ctx context.Context, tx extensions.SQLTransaction, request data_models.ProcessWaitUntilExecutionRequest,
+	ctx context.Context, tx extensions.SQLTransaction, prcRow *extensions.ProcessExecutionRowForUpdate,
+	request data_models.ProcessWaitUntilExecutionRequest,
 ) (*data_models.ProcessWaitUntilExecutionResponse, error) {
 	hasLocalQueueCommands := len(request.CommandRequest.GetLocalQueueCommands()) > 0
 
-	var prcRow *extensions.ProcessExecutionRowForUpdate
 	var localQueues data_models.StateExecutionLocalQueuesJson
 	var consumedMessagesMap map[int][]data_models.InternalLocalQueueMessage
 
 	// Step 1: get localQueues from the process execution row,
 	// update it with commands, and try to consume for the state execution
 	if hasLocalQueueCommands {
-		prcRow2, err := tx.SelectProcessExecutionForUpdate(ctx, request.ProcessExecutionId)
-		if err != nil {
-			return nil, err
-		}
-
-		prcRow = prcRow2
-
+		var err error
 		localQueues, err = data_models.NewStateExecutionLocalQueuesFromBytes(prcRow.StateExecutionLocalQueues)
 		if err != nil {
 			return nil, err
@@ -145,6 +161,12 @@ func (p sqlProcessStoreImpl) updateWaitUntilExecution(
 
 		consumedMessagesMap = localQueues.TryConsumeForStateExecution(
 			request.StateExecutionId, request.CommandRequest.GetWaitingType())
+
+		err = insertHistoryEventForConsumedMessages(
+			ctx, tx, prcRow, request.StateExecutionId, consumedMessagesMap)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Step 2: update the state execution row
@@ -160,7 +182,8 @@ func (p sqlProcessStoreImpl) updateWaitUntilExecution(
 	stateRow.Status = data_models.StateExecutionStatusWaitUntilWaiting
 	stateRow.LastFailure = nil
 
-	stateRow.WaitUntilCommands, err = data_models.FromCommandRequestToBytes(request.CommandRequest)
+	stateRow.WaitUntilCommands, err = data_models.FromCommandRequestWithProcessCompletionCommandsToBytes(
+		request.CommandRequest, request.ProcessCompletionCommands)
 	if err != nil {
 		return nil, err
 	}
@@ -186,9 +209,19 @@ func (p sqlProcessStoreImpl) updateWaitUntilExecution(
 		return nil, err
 	}
 
+	// Step 2 - 2: wait for the process completion commands, the results of the closed processes are updated immediately
+	hasProcessCompletionCommands := len(request.ProcessCompletionCommands) > 0
+	if hasProcessCompletionCommands {
+		err = p.waitForProcessCompletionCommands(ctx, tx, request, &commandResults)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	hasNewImmediateTask := false
 
-	if hasLocalQueueCommands && p.hasCompletedWaitUntilWaiting(request.CommandRequest, commandResults) {
+	if (hasLocalQueueCommands || hasProcessCompletionCommands) &&
+		p.hasCompletedWaitUntilWaiting(request.CommandRequest, request.ProcessCompletionCommands, commandResults) {
 		hasNewImmediateTask = true
 
 		err = p.updateWhenCompletedWaitUntilWaiting(ctx, tx, request.TaskShardId, &localQueues, stateRow)
@@ -207,7 +240,7 @@ func (p sqlProcessStoreImpl) updateWaitUntilExecution(
 		return nil, err
 	}
 
-	// Step 2 - 2: create timer command tasks
+	// Step 2 - 3: create timer command tasks
 	var fireTimestamps []int64
 
 	for idx, timerCommand := range request.CommandRequest.TimerCommands {
@@ -240,17 +273,12 @@ func (p sqlProcessStoreImpl) updateWaitUntilExecution(
 		fireTimestamps = append(fireTimestamps, fireTimestamp)
 	}
 
-	// Step 3: update process execution row, and submit
+	// Step 3: update the local queues of process execution row, which will be submitted by the caller
 	if hasLocalQueueCommands {
 		prcRow.StateExecutionLocalQueues, err = localQueues.ToBytes()
 		if err != nil {
 			return nil, err
 		}
-
-		err = tx.UpdateProcessExecution(ctx, *prcRow)
-		if err != nil {
-			return nil, err
-		}
 	}
 
 	return &data_models.ProcessWaitUntilExecutionResponse{
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLListByCloseTimeTest(t *testing.T, ass *assert.Assertions, store persistence.VisibilityStore) {
+	ctx := context.Background()
+	namespace := newTestNamespace()
+	base := time.Now().Unix() - 1000
+
+	recordStart(ctx, t, store, namespace, "prc-running", testProcessType, base)
+	closeAfterSeconds := func(processId string, seconds int64) string {
+		prcExeId := recordStart(ctx, t, store, namespace, processId, testProcessType, base)
+		recordClose(ctx, t, store, namespace, prcExeId, data_models.ProcessExecutionStatusCompleted, base+seconds)
+		return prcExeId.String()
+	}
+	closed1 := closeAfterSeconds("prc-1", 10)
+	// closed at the same second, ordered by the process execution ids
+	tie1 := closeAfterSeconds("prc-2", 20)
+	tie2 := closeAfterSeconds("prc-3", 20)
+	if tie2 < tie1 {
+		tie1, tie2 = tie2, tie1
+	}
+	closed4 := closeAfterSeconds("prc-4", 30)
+	closed5 := closeAfterSeconds("prc-5", 500)
+
+	// the close time filter works without the start time filter
+	ids := listAllPages(ctx, t, ass, store, data_models.ListProcessExecutionsRequest{
+		ListProcessExecutionsRequest: xcapi.ListProcessExecutionsRequest{
+			Namespace: namespace,
+		},
+		CloseTimeFilter: &xcapi.TimeRangeFilter{
+			EarliestTime: ptr.Any(base + 10),
+			LatestTime:   ptr.Any(base + 30),
+		},
+	}, 10)
+	ass.ElementsMatch([]string{closed1, tie1, tie2, closed4}, ids)
+
+	// ordering by the close time skips the running process execution,
+	// and the pages are split between the process executions closed at the same second
+	ids = listAllPages(ctx, t, ass, store, data_models.ListProcessExecutionsRequest{
+		ListProcessExecutionsRequest: xcapi.ListProcessExecutionsRequest{
+			Namespace: namespace,
+		},
+		Query: &visibilityquery.Query{
+			OrderBy: &visibilityquery.OrderBy{
+				Field: visibilityquery.FieldCloseTime,
+			},
+		},
+	}, 2)
+	ass.Equal([]string{closed1, tie1, tie2, closed4, closed5}, ids)
+
+	ids = listAllPages(ctx, t, ass, store, data_models.ListProcessExecutionsRequest{
+		ListProcessExecutionsRequest: xcapi.ListProcessExecutionsRequest{
+			Namespace: namespace,
+		},
+		Query: &visibilityquery.Query{
+			OrderBy: &visibilityquery.OrderBy{
+				Field:      visibilityquery.FieldCloseTime,
+				Descending: true,
+			},
+		},
+	}, 3)
+	ass.Equal([]string{closed5, closed4, tie1, tie2, closed1}, ids)
+
+	// the filter and the order are combined
+	ids = listAllPages(ctx, t, ass, store, data_models.ListProcessExecutionsRequest{
+		ListProcessExecutionsRequest: xcapi.ListProcessExecutionsRequest{
+			Namespace: namespace,
+		},
+		CloseTimeFilter: &xcapi.TimeRangeFilter{
+			EarliestTime: ptr.Any(base + 20),
+			LatestTime:   ptr.Any(base + 500),
+		},
+		Query: &visibilityquery.Query{
+			OrderBy: &visibilityquery.OrderBy{
+				Field:      visibilityquery.FieldCloseTime,
+				Descending: true,
+			},
+		},
+	}, 1)
+	ass.Equal([]string{closed5, closed4, tie1, tie2}, ids)
+}
+
+// listAllPages returns the process execution ids of all the pages, in the order of the pages
+func listAllPages(
+	ctx context.Context, t *testing.T, ass *assert.Assertions, store persistence.VisibilityStore,
+	request data_models.ListProcessExecutionsRequest, pageSize int32,
+) []string {
+	request.PageSize = pageSize
+	var ids []string
+	for {
+		resp, err := store.ListProcessExecutions(ctx, request)
+		require.NoError(t, err)
+		ass.True(len(resp.ProcessExecutions) <= int(pageSize))
+		if len(resp.ProcessExecutions) == 0 {
+			return ids
+		}
+		for _, processExecution := range resp.ProcessExecutions {
+			ids = append(ids, processExecution.GetProcessExecutionId())
+		}
+		require.NotNil(t, resp.NextPageToken)
+		request.NextPageToken = resp.NextPageToken
+	}
+}
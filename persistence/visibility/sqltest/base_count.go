@@ -0,0 +1,93 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLCountProcessExecutionsTest(t *testing.T, ass *assert.Assertions, store persistence.VisibilityStore) {
+	ctx := context.Background()
+	namespace := newTestNamespace()
+	now := time.Now().Unix()
+
+	// type-a: one running, one completed; type-b: one failed
+	recordStart(ctx, t, store, namespace, "prc-1", "type-a", now)
+	prcExeId2 := recordStart(ctx, t, store, namespace, "prc-2", "type-a", now)
+	recordClose(ctx, t, store, namespace, prcExeId2, data_models.ProcessExecutionStatusCompleted, now)
+	prcExeId3 := recordStart(ctx, t, store, namespace, "prc-3", "type-b", now)
+	recordClose(ctx, t, store, namespace, prcExeId3, data_models.ProcessExecutionStatusFailed, now)
+	// the process executions in other namespaces are not counted
+	recordStart(ctx, t, store, newTestNamespace(), "prc-1", "type-a", now)
+
+	resp, err := store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: namespace,
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(3), resp.Count)
+	ass.Nil(resp.Groups)
+
+	resp, err = store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: namespace,
+		Where: visibilityquery.Compare(
+			visibilityquery.FieldStatus, visibilityquery.ComparisonOperatorNotEqual, xcapi.RUNNING),
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(2), resp.Count)
+
+	resp, err = store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: namespace,
+		GroupBy:   data_models.ProcessExecutionCountGroupByStatus,
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(3), resp.Count)
+	ass.ElementsMatch([]data_models.ProcessExecutionCountGroup{
+		{Status: data_models.ProcessExecutionStatusRunning, Count: 1},
+		{Status: data_models.ProcessExecutionStatusCompleted, Count: 1},
+		{Status: data_models.ProcessExecutionStatusFailed, Count: 1},
+	}, resp.Groups)
+
+	resp, err = store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: namespace,
+		GroupBy:   data_models.ProcessExecutionCountGroupByProcessType,
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(3), resp.Count)
+	ass.ElementsMatch([]data_models.ProcessExecutionCountGroup{
+		{ProcessType: "type-a", Count: 2},
+		{ProcessType: "type-b", Count: 1},
+	}, resp.Groups)
+
+	// grouping is applied after filtering
+	resp, err = store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: namespace,
+		Where: visibilityquery.Compare(
+			visibilityquery.FieldProcessType, visibilityquery.ComparisonOperatorEqual, "type-a"),
+		GroupBy: data_models.ProcessExecutionCountGroupByStatus,
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(2), resp.Count)
+	ass.ElementsMatch([]data_models.ProcessExecutionCountGroup{
+		{Status: data_models.ProcessExecutionStatusRunning, Count: 1},
+		{Status: data_models.ProcessExecutionStatusCompleted, Count: 1},
+	}, resp.Groups)
+
+	// no group is returned without any matching process execution
+	resp, err = store.CountProcessExecutions(ctx, data_models.CountProcessExecutionsRequest{
+		Namespace: newTestNamespace(),
+		GroupBy:   data_models.ProcessExecutionCountGroupByStatus,
+	})
+	require.NoError(t, err)
+	ass.Equal(int64(0), resp.Count)
+	ass.Equal(0, len(resp.Groups))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func SQLRecordStatusWithoutVisibilityRecordTest(
+	t *testing.T, ass *assert.Assertions, processStore persistence.ProcessStore, store persistence.VisibilityStore,
+) {
+	ctx := context.Background()
+	namespace := newTestNamespace()
+	processId := fmt.Sprintf("test-prcid-%v", time.Now().String())
+	now := time.Now().Unix()
+
+	// the updates of a deleted process execution are skipped instead of being retried forever
+	deletedPrcExeId := uuid.MustNewUUID()
+	err := store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: deletedPrcExeId,
+		Status:             data_models.ProcessExecutionStatusCompleted,
+		CloseTime:          ptr.Any(now),
+	})
+	ass.NoError(err)
+	err = store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: deletedPrcExeId,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		SearchAttributes:   map[string]interface{}{"key": "value"},
+	})
+	ass.NoError(err)
+
+	// the updates of a process execution whose start is not recorded yet return an error to be retried
+	startResp, err := processStore.StartProcess(ctx, data_models.StartProcessRequest{
+		Request: xcapi.ProcessExecutionStartRequest{
+			Namespace:    namespace,
+			ProcessId:    processId,
+			ProcessType:  testProcessType,
+			WorkerUrl:    "test-url",
+			StartStateId: ptr.Any("state1"),
+			ProcessStartConfig: &xcapi.ProcessStartConfig{
+				TimeoutSeconds: ptr.Any(int32(100)),
+			},
+		},
+	})
+	require.NoError(t, err)
+	prcExeId := startResp.ProcessExecutionId
+	err = store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		SearchAttributes:   map[string]interface{}{"key": "value"},
+	})
+	ass.Error(err)
+	err = store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+		Status:             data_models.ProcessExecutionStatusTerminated,
+		CloseTime:          ptr.Any(now),
+	})
+	ass.Error(err)
+
+	// the retried updates succeed after the start is recorded
+	err = store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessId:          processId,
+		ProcessExecutionId: prcExeId,
+		ProcessType:        testProcessType,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		StartTime:          ptr.Any(now),
+	})
+	require.NoError(t, err)
+	err = store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		SearchAttributes:   map[string]interface{}{"key": "value"},
+	})
+	ass.NoError(err)
+	recordClose(ctx, t, store, namespace, prcExeId, data_models.ProcessExecutionStatusTerminated, now)
+}
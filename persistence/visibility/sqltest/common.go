@@ -0,0 +1,55 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+const testProcessType = "test-type"
+
+// newTestNamespace returns a new namespace for each test, so that the visibility records of a test
+// are not mixed up with the ones of the other tests
+func newTestNamespace() string {
+	return fmt.Sprintf("test-ns-visibility-%v", time.Now().UnixNano())
+}
+
+func recordStart(
+	ctx context.Context, t *testing.T, store persistence.VisibilityStore,
+	namespace, processId, processType string, startTime int64,
+) uuid.UUID {
+	prcExeId := uuid.MustNewUUID()
+	err := store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessId:          processId,
+		ProcessExecutionId: prcExeId,
+		ProcessType:        processType,
+		Status:             data_models.ProcessExecutionStatusRunning,
+		StartTime:          ptr.Any(startTime),
+	})
+	require.NoError(t, err)
+	return prcExeId
+}
+
+func recordClose(
+	ctx context.Context, t *testing.T, store persistence.VisibilityStore,
+	namespace string, prcExeId uuid.UUID, status data_models.ProcessExecutionStatus, closeTime int64,
+) {
+	err := store.RecordProcessExecutionStatus(ctx, data_models.RecordProcessExecutionStatusRequest{
+		Namespace:          namespace,
+		ProcessExecutionId: prcExeId,
+		Status:             status,
+		CloseTime:          ptr.Any(closeTime),
+	})
+	require.NoError(t, err)
+}
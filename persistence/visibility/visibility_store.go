@@ -4,15 +4,21 @@
 package visibility
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/common/log"
+	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
 	"github.com/xcherryio/xcherry/config"
 	"github.com/xcherryio/xcherry/extensions"
 	"github.com/xcherryio/xcherry/persistence"
 	"github.com/xcherryio/xcherry/persistence/data_models"
+	"strconv"
 	"time"
 )
 
@@ -40,131 +46,165 @@ func (p sqlVisibilityStoreImpl) RecordProcessExecutionStatus(
 	}
 
 	if req.Status == data_models.ProcessExecutionStatusRunning {
+		searchAttributes := req.SearchAttributes
+		if searchAttributes == nil {
+			searchAttributes = map[string]interface{}{}
+		}
+		searchAttributesBytes, err := json.Marshal(searchAttributes)
+		if err != nil {
+			return err
+		}
+
 		if req.StartTime == nil {
-			return fmt.Errorf("start time is required for recording visibility for running process")
+			if req.SearchAttributes == nil {
+				return fmt.Errorf("start time or search attributes is required for recording visibility for running process")
+			}
+			// upsert the search attributes of the running process
+			err = p.session.UpsertProcessExecutionSearchAttributesForVisibility(ctx, extensions.ExecutionVisibilityRow{
+				Namespace:          req.Namespace,
+				ProcessExecutionId: req.ProcessExecutionId,
+				SearchAttributes:   searchAttributesBytes,
+			})
+			return p.skipIfProcessExecutionDeleted(ctx, req.ProcessExecutionId, err)
 		}
-		return p.session.InsertProcessExecutionStartForVisibility(ctx, extensions.ExecutionVisibilityRow{
+		row := extensions.ExecutionVisibilityRow{
 			Namespace:          req.Namespace,
 			ProcessId:          req.ProcessId,
 			ProcessExecutionId: req.ProcessExecutionId,
 			ProcessTypeName:    req.ProcessType,
 			Status:             req.Status,
 			StartTime:          time.Unix(*req.StartTime, 0),
-		})
+			SearchAttributes:   searchAttributesBytes,
+		}
+		if req.DelayedStartTime != nil {
+			row.DelayedStartTime = ptr.Any(time.Unix(*req.DelayedStartTime, 0))
+		}
+		return p.session.InsertProcessExecutionStartForVisibility(ctx, row)
 	}
-	return p.session.UpdateProcessExecutionStatusForVisibility(ctx, extensions.ExecutionVisibilityRow{
+	row := extensions.ExecutionVisibilityRow{
 		Namespace:          req.Namespace,
 		ProcessId:          req.ProcessId,
 		ProcessExecutionId: req.ProcessExecutionId,
 		ProcessTypeName:    req.ProcessType,
 		Status:             req.Status,
-		CloseTime:          time.Unix(*req.CloseTime, 0),
-	})
+		CloseTime:          ptr.Any(time.Unix(*req.CloseTime, 0)),
+	}
+	if req.CloseRecord != nil {
+		closeRecordBytes, err := req.CloseRecord.ToBytes()
+		if err != nil {
+			return err
+		}
+		row.CloseRecord = closeRecordBytes
+	}
+	err := p.session.UpdateProcessExecutionStatusForVisibility(ctx, row)
+	return p.skipIfProcessExecutionDeleted(ctx, req.ProcessExecutionId, err)
+}
+
+// skipIfProcessExecutionDeleted ignores the not found error of the visibility record if the process execution
+// is deleted, e.g. by the retention, since the visibility record is deleted or will be deleted along with it.
+// Otherwise, the start is not recorded yet, and the error is returned to retry the visibility task.
+func (p sqlVisibilityStoreImpl) skipIfProcessExecutionDeleted(
+	ctx context.Context, processExecutionId uuid.UUID, err error,
+) error {
+	if err == nil || !p.session.IsNotFoundError(err) {
+		return err
+	}
+	_, err2 := p.session.SelectProcessExecution(ctx, processExecutionId)
+	if err2 == nil {
+		return err
+	}
+	if !p.session.IsNotFoundError(err2) {
+		return err2
+	}
+	p.logger.Warn("skip updating the visibility record of the deleted process execution",
+		tag.ProcessExecutionId(processExecutionId.String()))
+	return nil
+}
+
+func (p sqlVisibilityStoreImpl) DeleteProcessExecution(
+	ctx context.Context, req data_models.DeleteProcessExecutionVisibilityRequest) error {
+	return p.session.DeleteProcessExecutionForVisibility(ctx, req.Namespace, req.ProcessExecutionId)
 }
 
 func (p sqlVisibilityStoreImpl) ListProcessExecutions(
-	ctx context.Context, request xcapi.ListProcessExecutionsRequest) (*xcapi.ListProcessExecutionsResponse, error) {
+	ctx context.Context, request data_models.ListProcessExecutionsRequest) (*data_models.ListProcessExecutionsResponse, error) {
 	if request.Namespace == "" {
 		return nil, fmt.Errorf("namespace is required for listing process executions")
 	}
-	if !request.HasStartTimeFilter() {
-		return nil, fmt.Errorf("start time filter is required for listing process executions")
+	if !request.HasStartTimeFilter() && request.Query == nil && request.CloseTimeFilter == nil {
+		return nil, fmt.Errorf("start time filter is required for listing process executions without a query or close time filter")
+	}
+
+	var filters []visibilityquery.Expression
+	if request.HasStartTimeFilter() {
+		if !request.StartTimeFilter.HasEarliestTime() || !request.StartTimeFilter.HasLatestTime() {
+			return nil, fmt.Errorf("both earliest and latest time are required for start time filter")
+		}
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldStartTime, visibilityquery.ComparisonOperatorGreaterOrEqual,
+				time.Unix(*request.StartTimeFilter.EarliestTime, 0)),
+			visibilityquery.Compare(visibilityquery.FieldStartTime, visibilityquery.ComparisonOperatorLessOrEqual,
+				time.Unix(*request.StartTimeFilter.LatestTime, 0)))
+	}
+	if request.CloseTimeFilter != nil {
+		if !request.CloseTimeFilter.HasEarliestTime() || !request.CloseTimeFilter.HasLatestTime() {
+			return nil, fmt.Errorf("both earliest and latest time are required for close time filter")
+		}
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldCloseTime, visibilityquery.ComparisonOperatorGreaterOrEqual,
+				time.Unix(*request.CloseTimeFilter.EarliestTime, 0)),
+			visibilityquery.Compare(visibilityquery.FieldCloseTime, visibilityquery.ComparisonOperatorLessOrEqual,
+				time.Unix(*request.CloseTimeFilter.LatestTime, 0)))
+	}
+	if request.HasStatusFilter() {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldStatus, visibilityquery.ComparisonOperatorEqual, request.GetStatusFilter()))
 	}
-	if !request.StartTimeFilter.HasEarliestTime() || !request.StartTimeFilter.HasLatestTime() {
-		return nil, fmt.Errorf("both earliest and latest time are required for start time filter")
+	if request.HasProcessIdFilter() {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldProcessId, visibilityquery.ComparisonOperatorEqual, *request.ProcessIdFilter.ProcessId))
 	}
-	if request.HasProcessIdFilter() && request.HasProcessTypeFilter() {
-		return nil, fmt.Errorf("process id and process type filter cannot be used together")
+	if request.HasProcessTypeFilter() {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldProcessType, visibilityquery.ComparisonOperatorEqual, request.ProcessTypeFilter.ProcessType))
 	}
 
-	var paginationToken *data_models.PaginationToken
-	var err error
+	query := extensions.ExecutionVisibilityQuery{
+		Namespace: request.Namespace,
+		OrderBy: visibilityquery.OrderBy{
+			Field:      visibilityquery.FieldStartTime,
+			Descending: true,
+		},
+		PageSize: request.PageSize,
+	}
+	if request.Query != nil {
+		filters = append(filters, request.Query.Where)
+		if request.Query.OrderBy != nil {
+			query.OrderBy = *request.Query.OrderBy
+		}
+	}
+	query.Where = visibilityquery.And(filters...)
+
 	if request.HasNextPageToken() {
-		paginationToken, err = data_models.ParsePaginationTokenFromString(*request.NextPageToken)
+		paginationToken, err := data_models.ParsePaginationTokenFromString(*request.NextPageToken)
 		if err != nil {
 			return nil, err
 		}
+		query.LastProcessExecutionIdString = paginationToken.LastProcessExecutionId
+		if paginationToken.LastSortValue != nil {
+			query.LastSortValue = *paginationToken.LastSortValue
+		} else {
+			query.LastSortValue = strconv.FormatInt(paginationToken.LastStartTime, 10)
+		}
 	}
 
-	var lastProcessExecutionId string
-	var lastStartTime int64
-	lastProcessExecutionId = "00000000-0000-0000-0000-000000000000"
-	if paginationToken != nil {
-		lastProcessExecutionId = paginationToken.LastProcessExecutionId
-		lastStartTime = paginationToken.LastStartTime
-	}
-
-	var processExecutionRows []extensions.ExecutionVisibilityRow
-	var processExecutionListInfo []xcapi.ProcessExecutionListInfo
-
-	if !request.HasStatusFilter() && !request.HasProcessIdFilter() && !request.HasProcessTypeFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutions(
-			ctx,
-			request.Namespace,
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	} else if request.HasStatusFilter() && !request.HasProcessIdFilter() && !request.HasProcessTypeFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutionsByStatus(
-			ctx,
-			request.Namespace,
-			data_models.ParseProcessExecutionStatus(string(request.GetStatusFilter())),
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	} else if !request.HasStatusFilter() && request.HasProcessIdFilter() && !request.HasProcessTypeFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutionsById(
-			ctx,
-			request.Namespace,
-			*request.ProcessIdFilter.ProcessId,
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	} else if !request.HasStatusFilter() && !request.HasProcessIdFilter() && request.HasProcessTypeFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutionsByTypeQuery(
-			ctx,
-			request.Namespace,
-			request.ProcessTypeFilter.ProcessType,
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	} else if request.HasStatusFilter() && request.HasProcessIdFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutionsByStatusAndId(
-			ctx,
-			request.Namespace,
-			data_models.ParseProcessExecutionStatus(string(request.GetStatusFilter())),
-			*request.ProcessIdFilter.ProcessId,
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	} else if request.HasStatusFilter() && request.HasProcessTypeFilter() {
-		processExecutionRows, err = p.session.SelectProcessExecutionsByStatusAndType(
-			ctx,
-			request.Namespace,
-			data_models.ParseProcessExecutionStatus(string(request.GetStatusFilter())),
-			request.ProcessTypeFilter.ProcessType,
-			*request.StartTimeFilter.EarliestTime,
-			*request.StartTimeFilter.LatestTime,
-			lastProcessExecutionId,
-			lastStartTime,
-			request.PageSize)
-	}
-
+	processExecutionRows, err := p.session.SelectProcessExecutions(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	processExecutionListInfo = make([]xcapi.ProcessExecutionListInfo, len(processExecutionRows))
+	processExecutionListInfo := make([]xcapi.ProcessExecutionListInfo, len(processExecutionRows))
+	closeRecords := make([]*data_models.ProcessCloseRecordJson, len(processExecutionRows))
 	for i, row := range processExecutionRows {
 		processExecutionListInfo[i] = xcapi.ProcessExecutionListInfo{
 			Namespace:          ptr.Any(row.Namespace),
@@ -173,21 +213,116 @@ func (p sqlVisibilityStoreImpl) ListProcessExecutions(
 			ProcessType:        ptr.Any(row.ProcessTypeName),
 			Status:             ptr.Any(xcapi.ProcessStatus(row.Status.String())),
 			StartTimestamp:     ptr.Any(row.StartTime.Unix()),
-			CloseTimestamp:     ptr.Any(row.CloseTime.Unix()),
+		}
+		if row.CloseTime != nil {
+			processExecutionListInfo[i].CloseTimestamp = ptr.Any(row.CloseTime.Unix())
+		}
+		closeRecords[i], err = data_models.BytesToProcessCloseRecord(row.CloseRecord)
+		if err != nil {
+			return nil, err
 		}
 	}
 
+	if len(processExecutionRows) == 0 {
+		return &data_models.ListProcessExecutionsResponse{
+			ListProcessExecutionsResponse: xcapi.ListProcessExecutionsResponse{
+				ProcessExecutions: processExecutionListInfo,
+			},
+		}, nil
+	}
+
+	lastRow := processExecutionRows[len(processExecutionRows)-1]
 	nextPaginationToken := data_models.NewPaginationToken(
-		processExecutionRows[len(processExecutionRows)-1].ProcessExecutionId.String(),
-		processExecutionRows[len(processExecutionRows)-1].StartTime.Unix(),
+		lastRow.ProcessExecutionId.String(),
+		lastRow.StartTime.Unix(),
 	)
+	nextPaginationToken.LastSortValue, err = getSortValueText(lastRow, query.OrderBy)
+	if err != nil {
+		return nil, err
+	}
 	nextPaginationTokenString, err := nextPaginationToken.String()
 	if err != nil {
 		return nil, err
 	}
 
-	return &xcapi.ListProcessExecutionsResponse{
-		ProcessExecutions: processExecutionListInfo,
-		NextPageToken:     ptr.Any(nextPaginationTokenString),
+	return &data_models.ListProcessExecutionsResponse{
+		ListProcessExecutionsResponse: xcapi.ListProcessExecutionsResponse{
+			ProcessExecutions: processExecutionListInfo,
+			NextPageToken:     ptr.Any(nextPaginationTokenString),
+		},
+		CloseRecords: closeRecords,
 	}, nil
 }
+
+// getSortValueText returns the text form of the value to sort by, to continue the pagination from the row
+func getSortValueText(row extensions.ExecutionVisibilityRow, orderBy visibilityquery.OrderBy) (*string, error) {
+	var text string
+	switch orderBy.Field {
+	case visibilityquery.FieldProcessId:
+		text = row.ProcessId
+	case visibilityquery.FieldProcessExecutionId:
+		text = row.ProcessExecutionId.String()
+	case visibilityquery.FieldProcessType:
+		text = row.ProcessTypeName
+	case visibilityquery.FieldStatus:
+		text = strconv.Itoa(int(row.Status))
+	case visibilityquery.FieldStartTime:
+		text = strconv.FormatInt(row.StartTime.Unix(), 10)
+	case visibilityquery.FieldCloseTime:
+		if row.CloseTime == nil {
+			return nil, fmt.Errorf("close time is not found in the last process execution")
+		}
+		text = strconv.FormatInt(row.CloseTime.Unix(), 10)
+	case visibilityquery.FieldDelayedStartTime:
+		if row.DelayedStartTime == nil {
+			return nil, fmt.Errorf("delayed start time is not found in the last process execution")
+		}
+		text = strconv.FormatInt(row.DelayedStartTime.Unix(), 10)
+	default:
+		return getSearchAttributeText(row.SearchAttributes, orderBy.Field)
+	}
+	return &text, nil
+}
+
+// getSearchAttributeText returns the same text as the ->> operator of the JSON object in database
+func getSearchAttributeText(searchAttributes []byte, name string) (*string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(searchAttributes))
+	decoder.UseNumber()
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	value, ok := values[name]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("search attribute %v is not found in the last process execution", name)
+	}
+	text := fmt.Sprintf("%v", value)
+	return &text, nil
+}
+
+func (p sqlVisibilityStoreImpl) CountProcessExecutions(
+	ctx context.Context, request data_models.CountProcessExecutionsRequest,
+) (*data_models.CountProcessExecutionsResponse, error) {
+	if request.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required for counting process executions")
+	}
+
+	rows, err := p.session.CountProcessExecutions(ctx, request.Namespace, request.Where, request.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &data_models.CountProcessExecutionsResponse{}
+	for _, row := range rows {
+		resp.Count += row.Count
+		if request.GroupBy == data_models.ProcessExecutionCountGroupByNone {
+			continue
+		}
+		resp.Groups = append(resp.Groups, data_models.ProcessExecutionCountGroup{
+			Status:      row.Status,
+			ProcessType: row.ProcessTypeName,
+			Count:       row.Count,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,567 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/engine"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// The models below are for the APIs that are not yet defined in the xcapi IDL.
+// The JSON schemas follow the same conventions as xcapi.
+
+const DefaultHistoryPageSize = 100
+const MaxHistoryPageSize = 1000
+
+const DefaultRunsPageSize = 100
+const MaxRunsPageSize = 1000
+
+// MaxScheduleBackfillFirings is the max number of fire times to start in one backfill request
+const MaxScheduleBackfillFirings = 100
+
+// MaxCompletionCallbackUrls is the max number of completion callback urls of a process execution
+const MaxCompletionCallbackUrls = 10
+
+// MaxCompletionCallbackUrlLength is the max length of a completion callback url
+const MaxCompletionCallbackUrlLength = 2047
+
+// MaxRequestIdLength is the max length of the request ids to deduplicate the repeated requests
+const MaxRequestIdLength = 255
+
+// MaxBatchOperationIdLength is the max length of the batch operation ids, so that the request ids
+// of the batch actions, the batch operation id joined with a process execution id by "-", are within MaxRequestIdLength
+const MaxBatchOperationIdLength = MaxRequestIdLength - 37
+
+// The built-in fields to sort the process executions by
+const (
+	SortByFieldStartTime = "START_TIME"
+	SortByFieldCloseTime = "CLOSE_TIME"
+)
+
+type (
+	// ProcessExecutionStartOptions are the extra fields in the body of the StartProcess API request,
+	// which are not yet defined in xcapi.ProcessExecutionStartRequest
+	ProcessExecutionStartOptions struct {
+		// SearchAttributes are the initial search attributes of the process execution.
+		// The names must be registered in the config, and the values must match the registered types.
+		SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
+		// StartDelaySeconds delays running the start state. The process execution is created immediately,
+		// and the timeout is counted from the delayed start.
+		StartDelaySeconds *int32 `json:"startDelaySeconds,omitempty"`
+		// RequestId deduplicates the retries of the StartProcess API. A repeated request id returns the
+		// process execution started by the original request, instead of starting again or returning 409.
+		// It's only for the StartProcess API, and is recorded until the process execution is deleted after the retention.
+		RequestId *string `json:"requestId,omitempty"`
+		// CompletionCallbackUrls are posted with a CompletionCallbackPayload after the process execution is closed
+		CompletionCallbackUrls []string `json:"completionCallbackUrls,omitempty"`
+		// CompletionCallbackRetryPolicy is the retry policy of posting the callbacks,
+		// default to the same as the worker APIs
+		CompletionCallbackRetryPolicy *xcapi.RetryPolicy `json:"completionCallbackRetryPolicy,omitempty"`
+	}
+
+	// ProcessExecutionStopOptions are the extra fields in the body of the StopProcess API request,
+	// which are not yet defined in xcapi.ProcessExecutionStopRequest
+	ProcessExecutionStopOptions struct {
+		// RequestId deduplicates the retries of the StopProcess API. A repeated request id is a noop,
+		// so that it cannot stop a newer process execution started after the original request.
+		RequestId *string `json:"requestId,omitempty"`
+		// Reason is recorded in the close record of the stopped process execution
+		Reason *string `json:"reason,omitempty"`
+	}
+
+	// ProcessExecutionRpcOptions are the extra fields in the body of the Rpc API request,
+	// which are not yet defined in xcapi.ProcessExecutionRpcRequest
+	ProcessExecutionRpcOptions struct {
+		// RequestId deduplicates the retries of the Rpc API. A repeated request id returns the output
+		// of the original request without calling the worker again.
+		// The concurrent requests with the same request id may all call the worker, because the request id is recorded
+		// with the decision after the worker returns, but only the decision of one of them is applied, and its output
+		// is returned to all of them. Rpc methods with side effects outside of the decision should be idempotent.
+		RequestId *string `json:"requestId,omitempty"`
+	}
+
+	// ProcessExecutionDescribeOptions are the extra fields in the body of the DescribeProcess API response,
+	// which are not yet defined in xcapi.ProcessExecutionDescribeResponse
+	ProcessExecutionDescribeOptions struct {
+		ProcessId string `json:"processId,omitempty"`
+		// CloseTimestamp is only returned if the process execution is closed
+		CloseTimestamp *int64 `json:"closeTimestamp,omitempty"`
+		// DelayedStartTimestamp is the time to run the start state, only returned if the start is delayed
+		DelayedStartTimestamp *int64 `json:"delayedStartTimestamp,omitempty"`
+		// PendingStart is true if the process execution is running, and the delayed start time is not reached yet
+		PendingStart bool `json:"pendingStart,omitempty"`
+		// ParentProcess is only returned if the process is started as a child process by a state decision
+		ParentProcess *data_models.ParentProcessJson `json:"parentProcess,omitempty"`
+		// ContinuedFromProcessExecutionId is only returned if the process execution is started by continuing as new
+		ContinuedFromProcessExecutionId string `json:"continuedFromProcessExecutionId,omitempty"`
+		// ContinuedAsNewProcessExecutionId is only returned if the process execution is closed by continuing as new
+		ContinuedAsNewProcessExecutionId string `json:"continuedAsNewProcessExecutionId,omitempty"`
+		// ChildProcesses are the child processes started by the state decisions of the process execution
+		ChildProcesses []ChildProcessDescription `json:"childProcesses,omitempty"`
+		// Paused is true if the process execution is paused by the PauseProcessExecution API
+		Paused bool `json:"paused,omitempty"`
+		// CompletionCallbacks are the delivery status of the completion callback urls
+		CompletionCallbacks []CompletionCallbackDescription `json:"completionCallbacks,omitempty"`
+		// CloseRecord is only returned if the process execution is closed
+		CloseRecord *data_models.ProcessCloseRecordJson `json:"closeRecord,omitempty"`
+	}
+
+	// ProcessExecutionWaitForCompletionOptions are the extra fields in the body of the WaitForProcessCompletion API
+	// response, which are not yet defined in xcapi.ProcessExecutionWaitForCompletionResponse.
+	// They are only returned if the process execution is closed.
+	ProcessExecutionWaitForCompletionOptions struct {
+		// ProcessExecutionId is the process execution waited for
+		ProcessExecutionId string `json:"processExecutionId,omitempty"`
+		// Output is the closeInput of the thread close decision that closed the process
+		Output *xcapi.EncodedObject `json:"output,omitempty"`
+		// Failure is only returned if the process is failed by a state execution failure
+		Failure *data_models.ProcessFailureJson `json:"failure,omitempty"`
+		// CloseRecord is the reason why the process execution is closed
+		CloseRecord *data_models.ProcessCloseRecordJson `json:"closeRecord,omitempty"`
+	}
+
+	// ProcessExecutionListInfoOptions are the extra fields of each process execution in the body of the
+	// ListProcessExecutions API response, which are not yet defined in xcapi.ProcessExecutionListInfo
+	ProcessExecutionListInfoOptions struct {
+		// CloseRecord is only returned if the process execution is closed
+		CloseRecord *data_models.ProcessCloseRecordJson `json:"closeRecord,omitempty"`
+	}
+
+	// CompletionCallbackPayload is the body posted to the completion callback urls
+	CompletionCallbackPayload = data_models.CompletionCallbackPayloadJson
+
+	CompletionCallbackDescription struct {
+		Url string `json:"url"`
+		// Status is PENDING, DELIVERED or FAILED. PENDING is either waiting for the process to close, or retrying.
+		Status            string `json:"status"`
+		CompletedAttempts int32  `json:"completedAttempts"`
+		// LastFailure is the failure of the last attempt, only returned if the last attempt is failed
+		LastFailure *data_models.StateExecutionFailureJson `json:"lastFailure,omitempty"`
+	}
+
+	ChildProcessDescription struct {
+		ProcessId          string `json:"processId"`
+		ProcessExecutionId string `json:"processExecutionId"`
+		// StateId and StateIdSequence identify the state execution that started the child process
+		StateId         string `json:"stateId"`
+		StateIdSequence int32  `json:"stateIdSequence"`
+		StartTimestamp  int64  `json:"startTimestamp"`
+	}
+
+	// PublishToLocalQueueWithStartRequest publishes the messages to the running process execution.
+	// If the process is not running, a new process execution is started with the StartRequest and its IdReusePolicy,
+	// then the messages are published to it, in the same transaction.
+	PublishToLocalQueueWithStartRequest struct {
+		StartRequest xcapi.ProcessExecutionStartRequest `json:"startRequest"`
+		StartOptions ProcessExecutionStartOptions       `json:"startOptions"`
+		Messages     []xcapi.LocalQueueMessage          `json:"messages"`
+	}
+
+	PublishToLocalQueueWithStartResponse struct {
+		ProcessExecutionId string `json:"processExecutionId"`
+		// Started is true if a new process execution is started,
+		// false if the messages are published to the running process execution
+		Started bool `json:"started"`
+	}
+
+	// ListProcessExecutionsOptions are the extra fields in the body of the ListProcessExecutions API request,
+	// which are not yet defined in xcapi.ListProcessExecutionsRequest
+	ListProcessExecutionsOptions struct {
+		// Query is an optional filter expression, combined with the other filters using AND, e.g.
+		// "ProcessType = 'order' AND (Status = 'FAILED' OR Status = 'TIMEOUT') ORDER BY StartTime DESC".
+		// The start time filter is optional when Query or CloseTimeFilter is provided.
+		Query *string `json:"query,omitempty"`
+		// CloseTimeFilter will only return the closed process executions within the time range
+		CloseTimeFilter *xcapi.TimeRangeFilter `json:"closeTimeFilter,omitempty"`
+		// SearchAttributesFilter will only return the process executions with all the search attributes equal to the values
+		SearchAttributesFilter map[string]interface{} `json:"searchAttributesFilter,omitempty"`
+		// CloseReasonFilter will only return the closed process executions with the close reason type, e.g. CONTINUED_AS_NEW,
+		// to tell apart the process executions continued as new from the completed ones with the same COMPLETED status
+		CloseReasonFilter *string `json:"closeReasonFilter,omitempty"`
+		// SortBy is optional, and cannot be used together with ORDER BY in Query.
+		// The process executions are sorted by start time in descending order if not provided
+		SortBy *ProcessExecutionsSortBy `json:"sortBy,omitempty"`
+	}
+
+	ProcessExecutionsSortBy struct {
+		// Field is either START_TIME or CLOSE_TIME, and cannot be used together with SearchAttribute.
+		// The running process executions are not returned when sorting by CLOSE_TIME.
+		Field *string `json:"field,omitempty"`
+		// SearchAttribute is the registered search attribute to sort by.
+		// The process executions without the search attribute are not returned.
+		SearchAttribute string `json:"searchAttribute,omitempty"`
+		// Descending is optional, default to false
+		Descending *bool `json:"descending,omitempty"`
+	}
+
+	// ProcessEventsSubscribeRequest is bound from the query parameters, so that browsers can subscribe with EventSource.
+	// Namespace is required, the other fields are optional filters.
+	ProcessEventsSubscribeRequest struct {
+		Namespace          string `json:"namespace" form:"namespace"`
+		ProcessId          string `json:"processId,omitempty" form:"processId"`
+		ProcessExecutionId string `json:"processExecutionId,omitempty" form:"processExecutionId"`
+		ProcessType        string `json:"processType,omitempty" form:"processType"`
+	}
+
+	// WebUIProcessExecutionsListRequest is bound from the query parameters of the web UI to list the process executions.
+	// Query is the optional visibility query, the process executions of all time are listed if it's empty.
+	WebUIProcessExecutionsListRequest struct {
+		Namespace     string `json:"namespace" form:"namespace"`
+		Query         string `json:"query,omitempty" form:"query"`
+		PageSize      int32  `json:"pageSize,omitempty" form:"pageSize"`
+		NextPageToken string `json:"nextPageToken,omitempty" form:"nextPageToken"`
+	}
+
+	// WebUIProcessExecutionDescribeRequest is bound from the query parameters of the web UI to describe a process execution
+	WebUIProcessExecutionDescribeRequest struct {
+		Namespace          string `json:"namespace" form:"namespace"`
+		ProcessExecutionId string `json:"processExecutionId" form:"processExecutionId"`
+	}
+
+	// WebUIProcessExecutionDescribeResponse combines the process execution, its state executions
+	// and the first page of the history events, which are shown on the same page of the web UI
+	WebUIProcessExecutionDescribeResponse struct {
+		// Process is the same as the response body of the DescribeProcessExecutionById API
+		Process         map[string]interface{}         `json:"process"`
+		StateExecutions []StateExecutionDescription    `json:"stateExecutions"`
+		HistoryEvents   []ProcessExecutionHistoryEvent `json:"historyEvents"`
+		// HistoryNextPageToken is returned if there are more history events than the first page
+		HistoryNextPageToken *string `json:"historyNextPageToken,omitempty"`
+	}
+
+	// ProcessEvent is the data of the server-sent events streamed by the SubscribeProcessEvents API
+	ProcessEvent = engine.ProcessEvent
+
+	ProcessExecutionHistoryRequest struct {
+		Namespace string `json:"namespace"`
+		ProcessId string `json:"processId"`
+		// ProcessExecutionId is optional. The latest execution of the processId is used if not provided
+		ProcessExecutionId *string `json:"processExecutionId,omitempty"`
+		PageSize           *int32  `json:"pageSize,omitempty"`
+		NextPageToken      *string `json:"nextPageToken,omitempty"`
+	}
+
+	ProcessExecutionHistoryResponse struct {
+		ProcessExecutionId string                         `json:"processExecutionId"`
+		Events             []ProcessExecutionHistoryEvent `json:"events"`
+		NextPageToken      *string                        `json:"nextPageToken,omitempty"`
+	}
+
+	ProcessExecutionHistoryEvent struct {
+		EventId         int32                            `json:"eventId"`
+		EventType       string                           `json:"eventType"`
+		StateId         *string                          `json:"stateId,omitempty"`
+		StateIdSequence *int32                           `json:"stateIdSequence,omitempty"`
+		Timestamp       int64                            `json:"timestamp"`
+		Info            data_models.HistoryEventInfoJson `json:"info"`
+	}
+
+	DescribeStateExecutionsRequest struct {
+		Namespace string `json:"namespace"`
+		ProcessId string `json:"processId"`
+		// ProcessExecutionId is optional. The latest execution of the processId is used if not provided
+		ProcessExecutionId *string `json:"processExecutionId,omitempty"`
+		// PendingOnly will only return the state executions that are not yet completed
+		PendingOnly *bool `json:"pendingOnly,omitempty"`
+	}
+
+	DescribeStateExecutionsResponse struct {
+		ProcessExecutionId string                      `json:"processExecutionId"`
+		StateExecutions    []StateExecutionDescription `json:"stateExecutions"`
+	}
+
+	StateExecutionDescription struct {
+		StateId         string `json:"stateId"`
+		StateIdSequence int32  `json:"stateIdSequence"`
+		Status          string `json:"status"`
+		Pending         bool   `json:"pending"`
+
+		WaitUntilCommands       *xcapi.CommandRequest           `json:"waitUntilCommands,omitempty"`
+		WaitUntilCommandResults *data_models.CommandResultsJson `json:"waitUntilCommandResults,omitempty"`
+
+		LastFailure *StateExecutionFailure `json:"lastFailure,omitempty"`
+	}
+
+	// ScheduleCreateRequest creates a schedule to start the process executions periodically.
+	// The processId of each firing is the processId of StartRequest suffixed with the fire time in UTC,
+	// e.g. "daily-report-20231201T000000Z", so that each fire time starts at most one process execution.
+	ScheduleCreateRequest struct {
+		Namespace  string                       `json:"namespace"`
+		ScheduleId string                       `json:"scheduleId"`
+		Spec       data_models.ScheduleSpecJson `json:"spec"`
+		// StartRequest is the template to start the process executions. The namespace is the namespace of the schedule,
+		// the IdReusePolicy is ignored, and the AppDatabaseConfig is not supported.
+		StartRequest xcapi.ProcessExecutionStartRequest `json:"startRequest"`
+		StartOptions ProcessExecutionStartOptions       `json:"startOptions"`
+		// OverlapPolicy is optional, default to SKIP
+		OverlapPolicy *data_models.ScheduleOverlapPolicy `json:"overlapPolicy,omitempty"`
+		// Paused is optional, default to false
+		Paused *bool `json:"paused,omitempty"`
+	}
+
+	ScheduleCreateResponse struct {
+		// NextFireTimestamp is not returned if the schedule is paused, or will never fire
+		NextFireTimestamp *int64 `json:"nextFireTimestamp,omitempty"`
+	}
+
+	// ScheduleUpdateRequest replaces the definition of the schedule. The next fire time is recalculated.
+	ScheduleUpdateRequest struct {
+		Namespace     string                             `json:"namespace"`
+		ScheduleId    string                             `json:"scheduleId"`
+		Spec          data_models.ScheduleSpecJson       `json:"spec"`
+		StartRequest  xcapi.ProcessExecutionStartRequest `json:"startRequest"`
+		StartOptions  ProcessExecutionStartOptions       `json:"startOptions"`
+		OverlapPolicy *data_models.ScheduleOverlapPolicy `json:"overlapPolicy,omitempty"`
+	}
+
+	ScheduleUpdateResponse struct {
+		NextFireTimestamp *int64 `json:"nextFireTimestamp,omitempty"`
+	}
+
+	ScheduleDescribeRequest struct {
+		Namespace  string `json:"namespace"`
+		ScheduleId string `json:"scheduleId"`
+	}
+
+	ScheduleDescribeResponse struct {
+		ScheduleId          string                             `json:"scheduleId"`
+		Spec                data_models.ScheduleSpecJson       `json:"spec"`
+		StartRequest        xcapi.ProcessExecutionStartRequest `json:"startRequest"`
+		StartOptions        ProcessExecutionStartOptions       `json:"startOptions"`
+		OverlapPolicy       data_models.ScheduleOverlapPolicy  `json:"overlapPolicy"`
+		Paused              bool                               `json:"paused"`
+		NextFireTimestamp   *int64                             `json:"nextFireTimestamp,omitempty"`
+		LastFireTimestamp   *int64                             `json:"lastFireTimestamp,omitempty"`
+		LastProcessId       *string                            `json:"lastProcessId,omitempty"`
+		FiredCount          int32                              `json:"firedCount"`
+		SkippedCount        int32                              `json:"skippedCount"`
+		CreateTimestamp     int64                              `json:"createTimestamp"`
+		LastUpdateTimestamp int64                              `json:"lastUpdateTimestamp"`
+	}
+
+	SchedulePauseRequest struct {
+		Namespace  string `json:"namespace"`
+		ScheduleId string `json:"scheduleId"`
+	}
+
+	ScheduleResumeRequest struct {
+		Namespace  string `json:"namespace"`
+		ScheduleId string `json:"scheduleId"`
+	}
+
+	ScheduleDeleteRequest struct {
+		Namespace  string `json:"namespace"`
+		ScheduleId string `json:"scheduleId"`
+	}
+
+	// ScheduleBackfillRequest starts the process executions for the fire times in the range (StartTimestamp, EndTimestamp],
+	// regardless of the overlap policy and whether the schedule is paused. The fire times already started are skipped.
+	ScheduleBackfillRequest struct {
+		Namespace      string `json:"namespace"`
+		ScheduleId     string `json:"scheduleId"`
+		StartTimestamp int64  `json:"startTimestamp"`
+		EndTimestamp   int64  `json:"endTimestamp"`
+	}
+
+	ScheduleBackfillResponse struct {
+		StartedProcessExecutionIds []string `json:"startedProcessExecutionIds"`
+		SkippedCount               int32    `json:"skippedCount"`
+		// NextStartTimestamp is returned if there are more than MaxScheduleBackfillFirings fire times in the range,
+		// to continue the backfill with it as the StartTimestamp
+		NextStartTimestamp *int64 `json:"nextStartTimestamp,omitempty"`
+	}
+
+	ArchivedProcessExecutionDescribeRequest struct {
+		Namespace          string `json:"namespace"`
+		ProcessExecutionId string `json:"processExecutionId"`
+	}
+
+	StateExecutionFailure struct {
+		StatusCode           *int32  `json:"statusCode,omitempty"`
+		Details              *string `json:"details,omitempty"`
+		CompletedAttempts    *int32  `json:"completedAttempts,omitempty"`
+		LastAttemptTimestamp *int64  `json:"lastAttemptTimestamp,omitempty"`
+		// NextAttemptTimestamp is only returned when the state execution is backing off for the next attempt
+		NextAttemptTimestamp *int64 `json:"nextAttemptTimestamp,omitempty"`
+	}
+
+	// ProcessExecutionDescribeByIdRequest describes any process execution, including the earlier runs of the processId.
+	// The response is the same as the DescribeProcess API.
+	ProcessExecutionDescribeByIdRequest struct {
+		Namespace          string `json:"namespace"`
+		ProcessExecutionId string `json:"processExecutionId"`
+	}
+
+	ProcessExecutionRunsListRequest struct {
+		Namespace     string  `json:"namespace"`
+		ProcessId     string  `json:"processId"`
+		PageSize      *int32  `json:"pageSize,omitempty"`
+		NextPageToken *string `json:"nextPageToken,omitempty"`
+	}
+
+	ProcessExecutionRunsListResponse struct {
+		// Runs are ordered by the start time. The closed runs are deleted after the retention
+		Runs          []ProcessExecutionRun `json:"runs"`
+		NextPageToken *string               `json:"nextPageToken,omitempty"`
+	}
+
+	ProcessExecutionRun struct {
+		ProcessExecutionId string              `json:"processExecutionId"`
+		ProcessType        string              `json:"processType"`
+		Status             xcapi.ProcessStatus `json:"status"`
+		StartTimestamp     int64               `json:"startTimestamp"`
+		// CloseTimestamp is only returned if the run is closed
+		CloseTimestamp *int64 `json:"closeTimestamp,omitempty"`
+		// Latest is true for the latest process execution of the processId, which the other APIs operate on
+		Latest bool `json:"latest"`
+	}
+
+	GetLocalAttributesRequest struct {
+		Namespace string `json:"namespace"`
+		ProcessId string `json:"processId"`
+		// ProcessExecutionId is optional. The latest execution of the processId is used if not provided
+		ProcessExecutionId *string `json:"processExecutionId,omitempty"`
+		// Keys is optional. All the local attributes are returned if not provided
+		Keys []string `json:"keys,omitempty"`
+	}
+
+	GetLocalAttributesResponse struct {
+		ProcessExecutionId string                `json:"processExecutionId"`
+		Attributes         []LocalAttributeValue `json:"attributes"`
+	}
+
+	LocalAttributeValue struct {
+		Key   string              `json:"key"`
+		Value xcapi.EncodedObject `json:"value"`
+		// Version is increased on every write of the key, starting from 1
+		Version int64 `json:"version"`
+	}
+
+	// SetLocalAttributesRequest writes the local attributes of a running or closed process execution.
+	// If any expected version doesn't match, nothing is written and 409 is returned.
+	SetLocalAttributesRequest struct {
+		Namespace string `json:"namespace"`
+		ProcessId string `json:"processId"`
+		// ProcessExecutionId is optional. The latest execution of the processId is used if not provided
+		ProcessExecutionId *string               `json:"processExecutionId,omitempty"`
+		Attributes         []LocalAttributeWrite `json:"attributes"`
+		Reason             *string               `json:"reason,omitempty"`
+	}
+
+	LocalAttributeWrite struct {
+		Key   string              `json:"key"`
+		Value xcapi.EncodedObject `json:"value"`
+		// ExpectedVersion is optional for the compare-and-set of the key. 0 means the key must not exist
+		ExpectedVersion *int64 `json:"expectedVersion,omitempty"`
+	}
+
+	SetLocalAttributesResponse struct {
+		ProcessExecutionId string                `json:"processExecutionId"`
+		Attributes         []LocalAttributeValue `json:"attributes"`
+	}
+
+	BatchOperationStartRequest struct {
+		Namespace string `json:"namespace"`
+		// BatchOperationId is optional. A random id will be generated if not provided
+		BatchOperationId *string `json:"batchOperationId,omitempty"`
+		// the visibility filter to select the process executions
+		ProcessTypeFilter *string               `json:"processTypeFilter,omitempty"`
+		StatusFilter      *xcapi.ProcessStatus  `json:"statusFilter,omitempty"`
+		StartTimeFilter   xcapi.TimeRangeFilter `json:"startTimeFilter"`
+		// the action to apply on each selected process execution
+		Action data_models.BatchOperationActionJson `json:"action"`
+		// Rps is the max number of actions to apply per second
+		Rps *int32 `json:"rps,omitempty"`
+	}
+
+	BatchOperationStartResponse struct {
+		BatchOperationId string `json:"batchOperationId"`
+	}
+
+	BatchOperationDescribeRequest struct {
+		Namespace        string `json:"namespace"`
+		BatchOperationId string `json:"batchOperationId"`
+	}
+
+	BatchOperationDescribeResponse struct {
+		BatchOperationId    string                               `json:"batchOperationId"`
+		Status              string                               `json:"status"`
+		Filter              data_models.BatchOperationFilterJson `json:"filter"`
+		Action              data_models.BatchOperationActionJson `json:"action"`
+		Rps                 int32                                `json:"rps"`
+		SucceededCount      int32                                `json:"succeededCount"`
+		FailedCount         int32                                `json:"failedCount"`
+		SkippedCount        int32                                `json:"skippedCount"`
+		LastErrorMessage    *string                              `json:"lastErrorMessage,omitempty"`
+		CreateTimestamp     int64                                `json:"createTimestamp"`
+		LastUpdateTimestamp int64                                `json:"lastUpdateTimestamp"`
+	}
+
+	BatchOperationCancelRequest struct {
+		Namespace        string `json:"namespace"`
+		BatchOperationId string `json:"batchOperationId"`
+	}
+
+	// CountProcessExecutionsRequest accepts the same filters as xcapi.ListProcessExecutionsRequest
+	CountProcessExecutionsRequest struct {
+		Namespace         string                   `json:"namespace"`
+		StartTimeFilter   *xcapi.TimeRangeFilter   `json:"startTimeFilter,omitempty"`
+		StatusFilter      *xcapi.ProcessStatus     `json:"statusFilter,omitempty"`
+		ProcessIdFilter   *xcapi.ProcessIdFilter   `json:"processIdFilter,omitempty"`
+		ProcessTypeFilter *xcapi.ProcessTypeFilter `json:"processTypeFilter,omitempty"`
+		CloseTimeFilter   *xcapi.TimeRangeFilter   `json:"closeTimeFilter,omitempty"`
+		// Query is an optional filter expression without ORDER BY, same as ListProcessExecutionsOptions.Query
+		Query *string `json:"query,omitempty"`
+		// CloseReasonFilter is optional, same as ListProcessExecutionsOptions.CloseReasonFilter
+		CloseReasonFilter *string `json:"closeReasonFilter,omitempty"`
+		// GroupBy is optional, either STATUS or PROCESS_TYPE
+		GroupBy *string `json:"groupBy,omitempty"`
+	}
+
+	CountProcessExecutionsResponse struct {
+		Count int64 `json:"count"`
+		// Groups is only returned when GroupBy is provided in the request
+		Groups []ProcessExecutionCountGroup `json:"groups,omitempty"`
+	}
+
+	ProcessExecutionCountGroup struct {
+		Status      *xcapi.ProcessStatus `json:"status,omitempty"`
+		ProcessType *string              `json:"processType,omitempty"`
+		Count       int64                `json:"count"`
+	}
+
+	ProcessExecutionResetRequest struct {
+		Namespace string `json:"namespace"`
+		ProcessId string `json:"processId"`
+		// StateId and StateIdSequence identify the prior state execution to reset to.
+		// A new sequence of the state will be started with the original input of it.
+		StateId         string  `json:"stateId"`
+		StateIdSequence int32   `json:"stateIdSequence"`
+		Reason          *string `json:"reason,omitempty"`
+	}
+
+	ProcessExecutionResetResponse struct {
+		ProcessExecutionId string `json:"processExecutionId"`
+		StateId            string `json:"stateId"`
+		StateIdSequence    int32  `json:"stateIdSequence"`
+	}
+
+	// ProcessExecutionPauseRequest stops calling the worker for the latest process execution of the processId.
+	// The timers and local queue messages keep being applied, and the state executions are continued on resume.
+	// Pausing a paused process execution is a noop.
+	ProcessExecutionPauseRequest struct {
+		Namespace string  `json:"namespace"`
+		ProcessId string  `json:"processId"`
+		Reason    *string `json:"reason,omitempty"`
+	}
+
+	// ProcessExecutionResumeRequest resumes the paused process execution. Resuming a running process execution
+	// that is not paused is a noop.
+	ProcessExecutionResumeRequest struct {
+		Namespace string  `json:"namespace"`
+		ProcessId string  `json:"processId"`
+		Reason    *string `json:"reason,omitempty"`
+	}
+)
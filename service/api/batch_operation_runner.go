@@ -0,0 +1,394 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// batchOperationRunner runs the batch operations in the background.
+// The progress is checkpointed into the database after each page of the visibility query,
+// so that a batch operation can be resumed by any API service instance after the lease of the owner is expired.
+type batchOperationRunner struct {
+	rootCtx context.Context
+	cfg     config.BatchOperationConfig
+	logger  log.Logger
+
+	svc             Service
+	processStore    persistence.ProcessStore
+	visibilityStore persistence.VisibilityStore
+
+	// the identity of this instance to own the batch operations
+	owner string
+
+	// namespace/batchOperationId: true, for the batch operations running in this instance
+	running map[string]bool
+	lock    sync.Mutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newBatchOperationRunner(
+	rootCtx context.Context,
+	cfg config.Config,
+	svc Service,
+	processStore persistence.ProcessStore,
+	visibilityStore persistence.VisibilityStore,
+	logger log.Logger,
+) *batchOperationRunner {
+	return &batchOperationRunner{
+		rootCtx:         rootCtx,
+		cfg:             cfg.ApiService.BatchOperation,
+		logger:          logger,
+		svc:             svc,
+		processStore:    processStore,
+		visibilityStore: visibilityStore,
+		owner:           fmt.Sprintf("%v_%v", cfg.ApiService.HttpServer.Address, uuid.MustNewUUID().String()),
+		running:         map[string]bool{},
+		stopCh:          make(chan struct{}),
+	}
+}
+
+func (r *batchOperationRunner) Start() {
+	r.wg.Add(1)
+	go r.pollAndRun()
+}
+
+func (r *batchOperationRunner) Stop(ctx context.Context) error {
+	close(r.stopCh)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *batchOperationRunner) pollAndRun() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.claimBatchOperations()
+
+		select {
+		case <-r.rootCtx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *batchOperationRunner) claimBatchOperations() {
+	resp, err := r.processStore.GetRunningBatchOperations(r.rootCtx, data_models.GetRunningBatchOperationsRequest{
+		PageSize: 100,
+	})
+	if err != nil {
+		r.logger.Error("failed to get running batch operations", tag.Error(err))
+		return
+	}
+
+	for _, batchOperation := range resp.BatchOperations {
+		key := getBatchOperationKey(batchOperation)
+
+		r.lock.Lock()
+		alreadyRunning := r.running[key]
+		full := len(r.running) >= r.cfg.MaxConcurrentBatchOperations
+		r.lock.Unlock()
+
+		if full {
+			return
+		}
+		if alreadyRunning {
+			continue
+		}
+		if batchOperation.Owner != r.owner && batchOperation.LeaseExpireTimestamp > time.Now().Unix() {
+			// owned by another instance
+			continue
+		}
+
+		batchOperation.Owner = r.owner
+		batchOperation.LeaseExpireTimestamp = time.Now().Add(r.getLeaseDuration(batchOperation)).Unix()
+		updateResp, err := r.processStore.UpdateBatchOperation(r.rootCtx, data_models.UpdateBatchOperationRequest{
+			BatchOperation: batchOperation,
+		})
+		if err != nil {
+			r.logger.Error("failed to claim batch operation", tag.ID(key), tag.Error(err))
+			continue
+		}
+		if updateResp.VersionConflict {
+			// claimed by another instance, or canceled
+			continue
+		}
+		batchOperation.Version++
+
+		r.lock.Lock()
+		r.running[key] = true
+		r.lock.Unlock()
+
+		r.logger.Info("start running batch operation", tag.ID(key))
+
+		r.wg.Add(1)
+		go r.run(batchOperation)
+	}
+}
+
+func (r *batchOperationRunner) run(batchOperation data_models.BatchOperation) {
+	key := getBatchOperationKey(batchOperation)
+	defer func() {
+		r.lock.Lock()
+		delete(r.running, key)
+		r.lock.Unlock()
+		r.wg.Done()
+	}()
+
+	if err := validateBatchOperationAction(batchOperation.Info.Action); err != nil {
+		batchOperation.Status = data_models.BatchOperationStatusFailed
+		batchOperation.ErrorMessage = err.Error()
+		_, err = r.processStore.UpdateBatchOperation(r.rootCtx, data_models.UpdateBatchOperationRequest{
+			BatchOperation: batchOperation,
+		})
+		if err != nil {
+			r.logger.Error("failed to fail batch operation", tag.ID(key), tag.Error(err))
+		}
+		return
+	}
+
+	rateLimiter := time.NewTicker(time.Second / time.Duration(batchOperation.Rps))
+	defer rateLimiter.Stop()
+
+	filter := batchOperation.Info.Filter
+	for {
+		listReq := xcapi.ListProcessExecutionsRequest{
+			Namespace: batchOperation.Namespace,
+			PageSize:  r.cfg.PageSize,
+			StartTimeFilter: &xcapi.TimeRangeFilter{
+				EarliestTime: &filter.EarliestStartTime,
+				LatestTime:   &filter.LatestStartTime,
+			},
+			StatusFilter: filter.Status,
+		}
+		if filter.ProcessType != nil {
+			listReq.ProcessTypeFilter = &xcapi.ProcessTypeFilter{
+				ProcessType: *filter.ProcessType,
+			}
+		}
+		if batchOperation.NextPageToken != "" {
+			listReq.NextPageToken = &batchOperation.NextPageToken
+		}
+
+		listResp, err := r.visibilityStore.ListProcessExecutions(r.rootCtx, data_models.ListProcessExecutionsRequest{
+			ListProcessExecutionsRequest: listReq,
+		})
+		if err != nil {
+			// it will be picked up again by the next poll
+			r.logger.Error("failed to list process executions for batch operation", tag.ID(key), tag.Error(err))
+			return
+		}
+
+		for _, processExecution := range listResp.ProcessExecutions {
+			select {
+			case <-r.rootCtx.Done():
+				return
+			case <-r.stopCh:
+				return
+			case <-rateLimiter.C:
+			}
+
+			r.applyAction(&batchOperation, processExecution)
+		}
+
+		// reload to check if the batch operation has been canceled or taken over
+		descResp, err := r.processStore.DescribeBatchOperation(r.rootCtx, data_models.DescribeBatchOperationRequest{
+			Namespace:        batchOperation.Namespace,
+			BatchOperationId: batchOperation.BatchOperationId,
+		})
+		if err != nil {
+			r.logger.Error("failed to describe batch operation", tag.ID(key), tag.Error(err))
+			return
+		}
+		current := descResp.BatchOperation
+		if descResp.NotExists || current.Status != data_models.BatchOperationStatusRunning || current.Owner != r.owner {
+			r.logger.Info("stop running batch operation as it's no longer owned or running", tag.ID(key))
+			return
+		}
+
+		// checkpoint
+		isLastPage := listResp.NextPageToken == nil || len(listResp.ProcessExecutions) < int(r.cfg.PageSize)
+		if isLastPage {
+			batchOperation.Status = data_models.BatchOperationStatusCompleted
+		} else {
+			batchOperation.NextPageToken = *listResp.NextPageToken
+		}
+		batchOperation.Version = current.Version
+		batchOperation.LeaseExpireTimestamp = time.Now().Add(r.getLeaseDuration(batchOperation)).Unix()
+
+		updateResp, err := r.processStore.UpdateBatchOperation(r.rootCtx, data_models.UpdateBatchOperationRequest{
+			BatchOperation: batchOperation,
+		})
+		if err != nil {
+			r.logger.Error("failed to checkpoint batch operation", tag.ID(key), tag.Error(err))
+			return
+		}
+		if updateResp.VersionConflict {
+			r.logger.Info("stop running batch operation because of conflict on checkpoint", tag.ID(key))
+			return
+		}
+		batchOperation.Version++
+
+		if isLastPage {
+			r.logger.Info("completed batch operation", tag.ID(key))
+			return
+		}
+	}
+}
+
+// applyAction applies the action on the process execution, and counts the result into the batch operation
+func (r *batchOperationRunner) applyAction(
+	batchOperation *data_models.BatchOperation, processExecution xcapi.ProcessExecutionListInfo,
+) {
+	ctx := r.rootCtx
+	namespace := batchOperation.Namespace
+	processId := processExecution.GetProcessId()
+
+	// the APIs can only operate on the latest execution of a processId,
+	// skip if the listed execution is not the latest one, or has been closed
+	latestResp, err := r.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+	})
+	if err != nil {
+		r.logger.Warn("failed to get latest process execution for batch operation",
+			tag.ProcessId(processId), tag.Error(err))
+		batchOperation.FailedCount++
+		return
+	}
+	if latestResp.NotExists ||
+		latestResp.ProcessExecutionId.String() != processExecution.GetProcessExecutionId() ||
+		latestResp.Status != data_models.ProcessExecutionStatusRunning {
+		batchOperation.SkippedCount++
+		return
+	}
+
+	// the action may be applied again to the same process execution after a crash or a lost lease,
+	// since the progress is only checkpointed per page, so the deterministic request ids and dedupIds
+	// derived from the batch operation and the process execution are sent to deduplicate the repeated ones
+	requestId := getBatchActionRequestId(batchOperation.BatchOperationId, processExecution.GetProcessExecutionId())
+
+	action := batchOperation.Info.Action
+	var errResp *ErrorWithStatus
+	switch action.Type {
+	case data_models.BatchOperationActionTypeStop:
+		errResp = r.svc.StopProcess(ctx, xcapi.ProcessExecutionStopRequest{
+			Namespace: namespace,
+			ProcessId: processId,
+			StopType:  action.StopType,
+		}, ProcessExecutionStopOptions{
+			Reason:    ptr.Any("stopped by batch operation " + batchOperation.BatchOperationId),
+			RequestId: &requestId,
+		})
+	case data_models.BatchOperationActionTypePublishToLocalQueue:
+		errResp = r.svc.PublishToLocalQueue(ctx, xcapi.PublishToLocalQueueRequest{
+			Namespace: namespace,
+			ProcessId: processId,
+			Messages:  withDeterministicDedupIds(action.Messages, requestId),
+		})
+	case data_models.BatchOperationActionTypeRpc:
+		_, errResp = r.svc.Rpc(ctx, xcapi.ProcessExecutionRpcRequest{
+			Namespace:      namespace,
+			ProcessId:      processId,
+			RpcName:        *action.RpcName,
+			Input:          action.RpcInput,
+			TimeoutSeconds: action.RpcTimeoutSeconds,
+		}, ProcessExecutionRpcOptions{
+			RequestId: &requestId,
+		})
+	}
+
+	if errResp == nil {
+		batchOperation.SucceededCount++
+		return
+	}
+	if errResp.StatusCode == http.StatusNotFound {
+		batchOperation.SkippedCount++
+		return
+	}
+
+	r.logger.Warn("failed to apply batch operation action",
+		tag.ProcessId(processId), tag.StatusCode(errResp.StatusCode), tag.Value(errResp.Error.GetDetails()))
+	batchOperation.FailedCount++
+	batchOperation.ErrorMessage = fmt.Sprintf("processId %v: %v", processId, errResp.Error.GetDetails())
+}
+
+// getLeaseDuration makes sure the lease covers the time to process a page,
+// so that the batch operation won't be taken over in the middle of a page
+func (r *batchOperationRunner) getLeaseDuration(batchOperation data_models.BatchOperation) time.Duration {
+	pageDuration := time.Duration(r.cfg.PageSize) * time.Second / time.Duration(batchOperation.Rps)
+	return r.cfg.LeaseDuration + pageDuration
+}
+
+func validateBatchOperationAction(action data_models.BatchOperationActionJson) error {
+	switch action.Type {
+	case data_models.BatchOperationActionTypeStop:
+		return nil
+	case data_models.BatchOperationActionTypePublishToLocalQueue:
+		if len(action.Messages) == 0 {
+			return fmt.Errorf("messages are required for %v action", action.Type)
+		}
+		return nil
+	case data_models.BatchOperationActionTypeRpc:
+		if action.RpcName == nil || *action.RpcName == "" {
+			return fmt.Errorf("rpcName is required for %v action", action.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported action type %v", action.Type)
+	}
+}
+
+func getBatchActionRequestId(batchOperationId string, processExecutionId string) string {
+	return batchOperationId + "-" + processExecutionId
+}
+
+func getBatchOperationKey(batchOperation data_models.BatchOperation) string {
+	return batchOperation.Namespace + "/" + batchOperation.BatchOperationId
+}
+
+// withDeterministicDedupIds returns a copy of the messages, with the dedupIds derived from the given name
+// for the messages without the user-specified dedupIds
+func withDeterministicDedupIds(messages []xcapi.LocalQueueMessage, name string) []xcapi.LocalQueueMessage {
+	result := make([]xcapi.LocalQueueMessage, 0, len(messages))
+	for idx, message := range messages {
+		if message.GetDedupId() == "" {
+			message.DedupId = ptr.Any(uuid.NewNameBasedUUID(fmt.Sprintf("%v-%v", name, idx)).String())
+		}
+		result = append(result, message)
+	}
+	return result
+}
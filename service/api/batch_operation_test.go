@@ -0,0 +1,50 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+func TestBatchActionRequestIdLength(t *testing.T) {
+	batchOperationId := strings.Repeat("a", MaxBatchOperationIdLength)
+	requestId := getBatchActionRequestId(batchOperationId, uuid.MustNewUUID().String())
+	assert.Equal(t, MaxRequestIdLength, len(requestId))
+}
+
+func TestStartBatchOperationWithTooLongId(t *testing.T) {
+	svc := serviceImpl{cfg: config.Config{
+		ApiService: &config.ApiServiceConfig{
+			BatchOperation: config.BatchOperationConfig{
+				DefaultRps: 10,
+				MaxRps:     100,
+			},
+		},
+	}}
+
+	_, errResp := svc.StartBatchOperation(context.Background(), BatchOperationStartRequest{
+		Namespace:        "test-ns",
+		BatchOperationId: ptr.Any(strings.Repeat("a", MaxBatchOperationIdLength+1)),
+		StartTimeFilter: xcapi.TimeRangeFilter{
+			EarliestTime: ptr.Any(int64(1700000000)),
+			LatestTime:   ptr.Any(int64(1700000060)),
+		},
+		Action: data_models.BatchOperationActionJson{
+			Type: data_models.BatchOperationActionTypeStop,
+		},
+	})
+	require.NotNil(t, errResp)
+	assert.Equal(t, http.StatusBadRequest, errResp.StatusCode)
+}
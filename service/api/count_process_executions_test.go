@@ -0,0 +1,116 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/ptr"
+	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+)
+
+// countOnlyVisibilityStore records the count request, and returns the given response
+type countOnlyVisibilityStore struct {
+	persistence.VisibilityStore
+	request  data_models.CountProcessExecutionsRequest
+	response data_models.CountProcessExecutionsResponse
+}
+
+func (s *countOnlyVisibilityStore) CountProcessExecutions(
+	_ context.Context, request data_models.CountProcessExecutionsRequest,
+) (*data_models.CountProcessExecutionsResponse, error) {
+	s.request = request
+	return &s.response, nil
+}
+
+func newCountTestService(store persistence.VisibilityStore) serviceImpl {
+	return serviceImpl{
+		cfg:             config.Config{Database: &config.DatabaseConfig{}},
+		visibilityStore: store,
+	}
+}
+
+func TestCountProcessExecutionsGroupByStatus(t *testing.T) {
+	store := &countOnlyVisibilityStore{
+		response: data_models.CountProcessExecutionsResponse{
+			Count: 3,
+			Groups: []data_models.ProcessExecutionCountGroup{
+				{Status: data_models.ProcessExecutionStatusRunning, Count: 1},
+				{Status: data_models.ProcessExecutionStatusFailed, Count: 2},
+			},
+		},
+	}
+	resp, errResp := newCountTestService(store).CountProcessExecutions(context.Background(), CountProcessExecutionsRequest{
+		Namespace: "test-ns",
+		Query:     ptr.Any("ProcessType = 'test-type'"),
+		GroupBy:   ptr.Any(string(data_models.ProcessExecutionCountGroupByStatus)),
+	})
+	require.Nil(t, errResp)
+	assert.Equal(t, data_models.ProcessExecutionCountGroupByStatus, store.request.GroupBy)
+	assert.NotNil(t, store.request.Where)
+	assert.Equal(t, &CountProcessExecutionsResponse{
+		Count: 3,
+		Groups: []ProcessExecutionCountGroup{
+			{Status: xcapi.RUNNING.Ptr(), Count: 1},
+			{Status: xcapi.FAILED.Ptr(), Count: 2},
+		},
+	}, resp)
+}
+
+func TestCountProcessExecutionsGroupByProcessType(t *testing.T) {
+	store := &countOnlyVisibilityStore{
+		response: data_models.CountProcessExecutionsResponse{
+			Count: 3,
+			Groups: []data_models.ProcessExecutionCountGroup{
+				{ProcessType: "type-a", Count: 2},
+				{ProcessType: "type-b", Count: 1},
+			},
+		},
+	}
+	resp, errResp := newCountTestService(store).CountProcessExecutions(context.Background(), CountProcessExecutionsRequest{
+		Namespace: "test-ns",
+		StartTimeFilter: &xcapi.TimeRangeFilter{
+			EarliestTime: ptr.Any(int64(1700000000)),
+			LatestTime:   ptr.Any(int64(1700000060)),
+		},
+		GroupBy: ptr.Any(string(data_models.ProcessExecutionCountGroupByProcessType)),
+	})
+	require.Nil(t, errResp)
+	assert.Equal(t, &CountProcessExecutionsResponse{
+		Count: 3,
+		Groups: []ProcessExecutionCountGroup{
+			{ProcessType: ptr.Any("type-a"), Count: 2},
+			{ProcessType: ptr.Any("type-b"), Count: 1},
+		},
+	}, resp)
+}
+
+func TestCountProcessExecutionsInvalidRequests(t *testing.T) {
+	svc := newCountTestService(&countOnlyVisibilityStore{})
+	for name, request := range map[string]CountProcessExecutionsRequest{
+		"no time filter or query": {
+			Namespace: "test-ns",
+		},
+		"order by": {
+			Namespace: "test-ns",
+			Query:     ptr.Any("ProcessType = 'test-type' ORDER BY StartTime"),
+		},
+		"unknown group by": {
+			Namespace: "test-ns",
+			Query:     ptr.Any("ProcessType = 'test-type'"),
+			GroupBy:   ptr.Any("PROCESS_ID"),
+		},
+	} {
+		_, errResp := svc.CountProcessExecutions(context.Background(), request)
+		require.NotNil(t, errResp, name)
+		assert.Equal(t, http.StatusBadRequest, errResp.StatusCode, name)
+	}
+}
@@ -10,17 +10,44 @@ import (
 	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/config"
 	"github.com/xcherryio/xcherry/persistence"
+	"go.uber.org/multierr"
 	"net"
 	"net/http"
 )
 
 const PathStartProcessExecution = "/api/v1/xcherry/service/process-execution/start"
 const PathDescribeProcessExecution = "/api/v1/xcherry/service/process-execution/describe"
+const PathDescribeProcessExecutionById = "/api/v1/xcherry/service/process-execution/describe-by-id"
+const PathListProcessExecutionRuns = "/api/v1/xcherry/service/process-execution/list-runs"
 const PathStopProcessExecution = "/api/v1/xcherry/service/process-execution/stop"
 const PathPublishToLocalQueue = "/api/v1/xcherry/service/process-execution/publish-to-local-queue"
+const PathPublishToLocalQueueWithStart = "/api/v1/xcherry/service/process-execution/publish-to-local-queue-with-start"
 const PathProcessExecutionRpc = "/api/v1/xcherry/service/process-execution/rpc"
 const PathListProcessExecutions = "/api/v1/xcherry/service/process-execution/list"
+const PathCountProcessExecutions = "/api/v1/xcherry/service/process-execution/count"
 const PathWaitForProcessCompletion = "/api/v1/xcherry/service/process-execution/wait-for-process-completion"
+const PathGetProcessExecutionHistory = "/api/v1/xcherry/service/process-execution/history"
+const PathSubscribeProcessEvents = "/api/v1/xcherry/service/process-execution/events"
+const PathResetProcessExecution = "/api/v1/xcherry/service/process-execution/reset"
+const PathPauseProcessExecution = "/api/v1/xcherry/service/process-execution/pause"
+const PathResumeProcessExecution = "/api/v1/xcherry/service/process-execution/resume"
+const PathDescribeStateExecutions = "/api/v1/xcherry/service/process-execution/describe-state-executions"
+const PathDescribeArchivedProcessExecution = "/api/v1/xcherry/service/process-execution/describe-archived"
+const PathGetLocalAttributes = "/api/v1/xcherry/service/process-execution/get-local-attributes"
+const PathSetLocalAttributes = "/api/v1/xcherry/service/process-execution/set-local-attributes"
+const PathStartBatchOperation = "/api/v1/xcherry/service/batch-operation/start"
+const PathDescribeBatchOperation = "/api/v1/xcherry/service/batch-operation/describe"
+const PathCancelBatchOperation = "/api/v1/xcherry/service/batch-operation/cancel"
+const PathCreateSchedule = "/api/v1/xcherry/service/schedule/create"
+const PathDescribeSchedule = "/api/v1/xcherry/service/schedule/describe"
+const PathUpdateSchedule = "/api/v1/xcherry/service/schedule/update"
+const PathPauseSchedule = "/api/v1/xcherry/service/schedule/pause"
+const PathResumeSchedule = "/api/v1/xcherry/service/schedule/resume"
+const PathDeleteSchedule = "/api/v1/xcherry/service/schedule/delete"
+const PathBackfillSchedule = "/api/v1/xcherry/service/schedule/backfill"
+const PathWebUI = "/ui"
+const PathWebUIListProcessExecutions = "/api/v1/xcherry/ui/process-executions"
+const PathWebUIDescribeProcessExecution = "/api/v1/xcherry/ui/process-execution"
 
 type defaultSever struct {
 	rootCtx context.Context
@@ -29,6 +56,8 @@ type defaultSever struct {
 
 	engine     *gin.Engine
 	httpServer *http.Server
+
+	batchOperationRunner *batchOperationRunner
 }
 
 func NewDefaultAPIServerWithGin(
@@ -36,22 +65,51 @@ func NewDefaultAPIServerWithGin(
 	cfg config.Config,
 	processStore persistence.ProcessStore,
 	visibilityStore persistence.VisibilityStore,
+	archiver persistence.Archiver,
 	logger log.Logger,
 ) Server {
 	engine := gin.Default()
 
-	handler := newGinHandler(cfg, processStore, visibilityStore, logger)
+	svc := NewServiceImpl(cfg, processStore, visibilityStore, archiver, logger)
+	handler := newGinHandler(cfg, svc, logger)
+	batchRunner := newBatchOperationRunner(rootCtx, cfg, svc, processStore, visibilityStore, logger)
 
 	engine.GET("/", func(c *gin.Context) {
 		c.String(http.StatusOK, "Hello from xCherry server!")
 	})
 	engine.POST(PathStartProcessExecution, handler.StartProcess)
 	engine.POST(PathDescribeProcessExecution, handler.DescribeProcess)
+	engine.POST(PathDescribeProcessExecutionById, handler.DescribeProcessExecutionById)
+	engine.POST(PathListProcessExecutionRuns, handler.ListProcessExecutionRuns)
 	engine.POST(PathStopProcessExecution, handler.StopProcess)
 	engine.POST(PathPublishToLocalQueue, handler.PublishToLocalQueue)
+	engine.POST(PathPublishToLocalQueueWithStart, handler.PublishToLocalQueueWithStart)
 	engine.POST(PathProcessExecutionRpc, handler.Rpc)
 	engine.POST(PathListProcessExecutions, handler.ListProcessExecutions)
+	engine.POST(PathCountProcessExecutions, handler.CountProcessExecutions)
 	engine.POST(PathWaitForProcessCompletion, handler.WaitForProcessCompletion)
+	engine.POST(PathGetProcessExecutionHistory, handler.GetProcessExecutionHistory)
+	engine.GET(PathSubscribeProcessEvents, handler.SubscribeProcessEvents)
+	engine.POST(PathResetProcessExecution, handler.ResetProcessExecution)
+	engine.POST(PathPauseProcessExecution, handler.PauseProcessExecution)
+	engine.POST(PathResumeProcessExecution, handler.ResumeProcessExecution)
+	engine.POST(PathDescribeStateExecutions, handler.DescribeStateExecutions)
+	engine.POST(PathDescribeArchivedProcessExecution, handler.DescribeArchivedProcessExecution)
+	engine.POST(PathGetLocalAttributes, handler.GetLocalAttributes)
+	engine.POST(PathSetLocalAttributes, handler.SetLocalAttributes)
+	engine.POST(PathStartBatchOperation, handler.StartBatchOperation)
+	engine.POST(PathDescribeBatchOperation, handler.DescribeBatchOperation)
+	engine.POST(PathCancelBatchOperation, handler.CancelBatchOperation)
+	engine.POST(PathCreateSchedule, handler.CreateSchedule)
+	engine.POST(PathDescribeSchedule, handler.DescribeSchedule)
+	engine.POST(PathUpdateSchedule, handler.UpdateSchedule)
+	engine.POST(PathPauseSchedule, handler.PauseSchedule)
+	engine.POST(PathResumeSchedule, handler.ResumeSchedule)
+	engine.POST(PathDeleteSchedule, handler.DeleteSchedule)
+	engine.POST(PathBackfillSchedule, handler.BackfillSchedule)
+	if cfg.ApiService.WebUI.Enabled {
+		registerWebUI(engine, handler)
+	}
 
 	svrCfg := cfg.ApiService.HttpServer
 	httpServer := &http.Server{
@@ -75,6 +133,8 @@ func NewDefaultAPIServerWithGin(
 		logger:     logger,
 		engine:     engine,
 		httpServer: httpServer,
+
+		batchOperationRunner: batchRunner,
 	}
 }
 
@@ -84,9 +144,12 @@ func (s defaultSever) Start() error {
 		s.logger.Info("Http Server for API service is closed", tag.Error(err))
 	}()
 
+	s.batchOperationRunner.Start()
 	return nil
 }
 
 func (s defaultSever) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	err1 := s.httpServer.Shutdown(ctx)
+	err2 := s.batchOperationRunner.Stop(ctx)
+	return multierr.Combine(err1, err2)
 }
@@ -4,12 +4,15 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/common/log"
 	"github.com/xcherryio/xcherry/common/log/tag"
 	"github.com/xcherryio/xcherry/config"
-	"github.com/xcherryio/xcherry/persistence"
+	"github.com/xcherryio/xcherry/persistence/data_models"
+	"github.com/xcherryio/xcherry/service/async"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -23,11 +26,9 @@ type ginHandler struct {
 
 func newGinHandler(
 	cfg config.Config,
-	processStore persistence.ProcessStore,
-	visibilityStore persistence.VisibilityStore,
+	svc Service,
 	logger log.Logger,
 ) *ginHandler {
-	svc := NewServiceImpl(cfg, processStore, visibilityStore, logger)
 	return &ginHandler{
 		config: cfg,
 		logger: logger,
@@ -37,18 +38,19 @@ func newGinHandler(
 
 func (h *ginHandler) StartProcess(c *gin.Context) {
 	var req xcapi.ProcessExecutionStartRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var options ProcessExecutionStartOptions
+	if err := bindJSONWithOptions(c, &req, &options); err != nil {
 		invalidRequestSchema(c)
 		return
 	}
 	var errResp *ErrorWithStatus
 	var resp *xcapi.ProcessExecutionStartResponse
-	h.logger.Debug("received StartProcess API request", tag.Value(h.toJson(req)))
+	h.logger.Debug("received StartProcess API request", tag.Value(h.toJson(req)), tag.Value(h.toJson(options)))
 	defer func() {
 		h.logger.Debug("responded StartProcess API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
 	}()
 
-	resp, errResp = h.svc.StartProcess(c.Request.Context(), req)
+	resp, errResp = h.svc.StartProcess(c.Request.Context(), req, options)
 
 	if errResp != nil {
 		c.JSON(errResp.StatusCode, errResp.Error)
@@ -59,17 +61,18 @@ func (h *ginHandler) StartProcess(c *gin.Context) {
 
 func (h *ginHandler) StopProcess(c *gin.Context) {
 	var req xcapi.ProcessExecutionStopRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var options ProcessExecutionStopOptions
+	if err := bindJSONWithOptions(c, &req, &options); err != nil {
 		invalidRequestSchema(c)
 		return
 	}
 	var err *ErrorWithStatus
-	h.logger.Debug("received StopProcess API request", tag.Value(h.toJson(req)))
+	h.logger.Debug("received StopProcess API request", tag.Value(h.toJson(req)), tag.Value(h.toJson(options)))
 	defer func() {
 		h.logger.Debug("responded StopProcess API request", tag.Value(h.toJson(err)))
 	}()
 
-	err = h.svc.StopProcess(c.Request.Context(), req)
+	err = h.svc.StopProcess(c.Request.Context(), req, options)
 
 	if err != nil {
 		c.JSON(err.StatusCode, err.Error)
@@ -86,24 +89,92 @@ func (h *ginHandler) DescribeProcess(c *gin.Context) {
 		return
 	}
 	var resp *xcapi.ProcessExecutionDescribeResponse
+	var options *ProcessExecutionDescribeOptions
 	var errResp *ErrorWithStatus
 
 	h.logger.Debug("received DescribeProcess API request", tag.Value(h.toJson(req)))
 	defer func() {
-		h.logger.Debug("responded DescribeProcess API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+		h.logger.Debug("responded DescribeProcess API request",
+			tag.Value(h.toJson(resp)), tag.Value(h.toJson(options)), tag.Value(h.toJson(errResp)))
 	}()
 
-	resp, errResp = h.svc.DescribeLatestProcess(c.Request.Context(), req)
+	resp, options, errResp = h.svc.DescribeLatestProcess(c.Request.Context(), req)
 
 	if errResp != nil {
 		c.JSON(errResp.StatusCode, errResp.Error)
 		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	body, err := mergeJSONWithOptions(resp, options)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
 	return
 }
 
+func (h *ginHandler) DescribeProcessExecutionById(c *gin.Context) {
+	var req ProcessExecutionDescribeByIdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var resp *xcapi.ProcessExecutionDescribeResponse
+	var options *ProcessExecutionDescribeOptions
+	var errResp *ErrorWithStatus
+
+	h.logger.Debug("received DescribeProcessExecutionById API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeProcessExecutionById API request",
+			tag.Value(h.toJson(resp)), tag.Value(h.toJson(options)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, options, errResp = h.svc.DescribeProcessExecutionById(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	body, err := mergeJSONWithOptions(resp, options)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+func (h *ginHandler) ListProcessExecutionRuns(c *gin.Context) {
+	var req ProcessExecutionRunsListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ProcessExecutionRunsListResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received ListProcessExecutionRuns API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded ListProcessExecutionRuns API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.ListProcessExecutionRuns(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *ginHandler) PublishToLocalQueue(c *gin.Context) {
 	var req xcapi.PublishToLocalQueueRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -131,9 +202,34 @@ func (h *ginHandler) PublishToLocalQueue(c *gin.Context) {
 	c.JSON(http.StatusOK, struct{}{})
 }
 
+func (h *ginHandler) PublishToLocalQueueWithStart(c *gin.Context) {
+	var req PublishToLocalQueueWithStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *PublishToLocalQueueWithStartResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received PublishToLocalQueueWithStart API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded PublishToLocalQueueWithStart API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.PublishToLocalQueueWithStart(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *ginHandler) Rpc(c *gin.Context) {
 	var req xcapi.ProcessExecutionRpcRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var options ProcessExecutionRpcOptions
+	if err := bindJSONWithOptions(c, &req, &options); err != nil {
 		invalidRequestSchema(c)
 		return
 	}
@@ -144,12 +240,12 @@ func (h *ginHandler) Rpc(c *gin.Context) {
 	}
 
 	var err *ErrorWithStatus
-	h.logger.Debug("received Rpc API request", tag.Value(h.toJson(req)))
+	h.logger.Debug("received Rpc API request", tag.Value(h.toJson(req)), tag.Value(h.toJson(options)))
 	defer func() {
 		h.logger.Debug("responded Rpc API request", tag.Value(h.toJson(err)))
 	}()
 
-	resp, err := h.svc.Rpc(c.Request.Context(), req)
+	resp, err := h.svc.Rpc(c.Request.Context(), req, options)
 
 	if err != nil {
 		c.JSON(err.StatusCode, err.Error)
@@ -161,19 +257,54 @@ func (h *ginHandler) Rpc(c *gin.Context) {
 
 func (h *ginHandler) ListProcessExecutions(c *gin.Context) {
 	var req xcapi.ListProcessExecutionsRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var options ListProcessExecutionsOptions
+	if err := bindJSONWithOptions(c, &req, &options); err != nil {
 		invalidRequestSchema(c)
 		return
 	}
 
 	var resp *xcapi.ListProcessExecutionsResponse
+	var infoOptions []ProcessExecutionListInfoOptions
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received ListProcessExecutions API request", tag.Value(h.toJson(req)), tag.Value(h.toJson(options)))
+	defer func() {
+		h.logger.Debug("responded ListProcessExecutions API request",
+			tag.Value(h.toJson(resp)), tag.Value(h.toJson(infoOptions)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, infoOptions, errResp = h.svc.ListProcessExecutions(c.Request.Context(), req, options)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	body, err := mergeProcessExecutionsWithOptions(resp, infoOptions)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+func (h *ginHandler) CountProcessExecutions(c *gin.Context) {
+	var req CountProcessExecutionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *CountProcessExecutionsResponse
 	var errResp *ErrorWithStatus
-	h.logger.Debug("received ListProcessExecutions API request", tag.Value(h.toJson(req)))
+	h.logger.Debug("received CountProcessExecutions API request", tag.Value(h.toJson(req)))
 	defer func() {
-		h.logger.Debug("responded ListProcessExecutions API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+		h.logger.Debug("responded CountProcessExecutions API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
 	}()
 
-	resp, errResp = h.svc.ListProcessExecutions(c.Request.Context(), req)
+	resp, errResp = h.svc.CountProcessExecutions(c.Request.Context(), req)
 
 	if errResp != nil {
 		c.JSON(errResp.StatusCode, errResp.Error)
@@ -190,13 +321,479 @@ func (h *ginHandler) WaitForProcessCompletion(c *gin.Context) {
 		return
 	}
 
+	var resp *xcapi.ProcessExecutionWaitForCompletionResponse
+	var options *ProcessExecutionWaitForCompletionOptions
 	var errResp *ErrorWithStatus
 	h.logger.Debug("received WaitForProcessCompletion API request", tag.Value(h.toJson(req)))
 	defer func() {
-		h.logger.Debug("responded WaitForProcessCompletion API request", tag.Value(h.toJson(errResp)))
+		h.logger.Debug("responded WaitForProcessCompletion API request",
+			tag.Value(h.toJson(resp)), tag.Value(h.toJson(options)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, options, errResp = h.svc.WaitForProcessCompletion(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	body, err := mergeJSONWithOptions(resp, options)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+func (h *ginHandler) GetProcessExecutionHistory(c *gin.Context) {
+	var req ProcessExecutionHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ProcessExecutionHistoryResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received GetProcessExecutionHistory API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded GetProcessExecutionHistory API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.GetProcessExecutionHistory(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) SubscribeProcessEvents(c *gin.Context) {
+	var req ProcessEventsSubscribeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	h.logger.Debug("received SubscribeProcessEvents API request", tag.Value(h.toJson(req)))
+
+	ctx, cancel := async.WithProcessEventStreamDeadline(c.Request.Context(), h.config.ApiService.HttpServer.WriteTimeout)
+	defer cancel()
+
+	events, errResp := h.svc.SubscribeProcessEvents(ctx, req)
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	// respond the headers right away, so that the client doesn't wait for the first event
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent("processEvent", event)
+		return true
+	})
+}
+
+func (h *ginHandler) StartBatchOperation(c *gin.Context) {
+	var req BatchOperationStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *BatchOperationStartResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received StartBatchOperation API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded StartBatchOperation API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.StartBatchOperation(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) DescribeBatchOperation(c *gin.Context) {
+	var req BatchOperationDescribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *BatchOperationDescribeResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received DescribeBatchOperation API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeBatchOperation API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.DescribeBatchOperation(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) CancelBatchOperation(c *gin.Context) {
+	var req BatchOperationCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received CancelBatchOperation API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded CancelBatchOperation API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.CancelBatchOperation(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ScheduleCreateResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received CreateSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded CreateSchedule API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
 	}()
 
-	resp, errResp := h.svc.WaitForProcessCompletion(c.Request.Context(), req)
+	resp, errResp = h.svc.CreateSchedule(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) DescribeSchedule(c *gin.Context) {
+	var req ScheduleDescribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ScheduleDescribeResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received DescribeSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeSchedule API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.DescribeSchedule(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) UpdateSchedule(c *gin.Context) {
+	var req ScheduleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ScheduleUpdateResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received UpdateSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded UpdateSchedule API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.UpdateSchedule(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) PauseSchedule(c *gin.Context) {
+	var req SchedulePauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received PauseSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded PauseSchedule API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.PauseSchedule(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) ResumeSchedule(c *gin.Context) {
+	var req ScheduleResumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received ResumeSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded ResumeSchedule API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.ResumeSchedule(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) DeleteSchedule(c *gin.Context) {
+	var req ScheduleDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received DeleteSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DeleteSchedule API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.DeleteSchedule(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) BackfillSchedule(c *gin.Context) {
+	var req ScheduleBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ScheduleBackfillResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received BackfillSchedule API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded BackfillSchedule API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.BackfillSchedule(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) ResetProcessExecution(c *gin.Context) {
+	var req ProcessExecutionResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *ProcessExecutionResetResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received ResetProcessExecution API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded ResetProcessExecution API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.ResetProcessExecution(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) PauseProcessExecution(c *gin.Context) {
+	var req ProcessExecutionPauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received PauseProcessExecution API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded PauseProcessExecution API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.PauseProcessExecution(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) ResumeProcessExecution(c *gin.Context) {
+	var req ProcessExecutionResumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	var err *ErrorWithStatus
+	h.logger.Debug("received ResumeProcessExecution API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded ResumeProcessExecution API request", tag.Value(h.toJson(err)))
+	}()
+
+	err = h.svc.ResumeProcessExecution(c.Request.Context(), req)
+
+	if err != nil {
+		c.JSON(err.StatusCode, err.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct{}{})
+}
+
+func (h *ginHandler) DescribeStateExecutions(c *gin.Context) {
+	var req DescribeStateExecutionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *DescribeStateExecutionsResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received DescribeStateExecutions API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeStateExecutions API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.DescribeStateExecutions(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) GetLocalAttributes(c *gin.Context) {
+	var req GetLocalAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *GetLocalAttributesResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received GetLocalAttributes API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded GetLocalAttributes API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.GetLocalAttributes(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) SetLocalAttributes(c *gin.Context) {
+	var req SetLocalAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *SetLocalAttributesResponse
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received SetLocalAttributes API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded SetLocalAttributes API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.SetLocalAttributes(c.Request.Context(), req)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ginHandler) DescribeArchivedProcessExecution(c *gin.Context) {
+	var req ArchivedProcessExecutionDescribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var resp *data_models.ArchivedProcessExecutionJson
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received DescribeArchivedProcessExecution API request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeArchivedProcessExecution API request", tag.Value(h.toJson(resp)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, errResp = h.svc.DescribeArchivedProcessExecution(c.Request.Context(), req)
 
 	if errResp != nil {
 		c.JSON(errResp.StatusCode, errResp.Error)
@@ -215,6 +812,56 @@ func (h *ginHandler) toJson(req any) string {
 	return string(str)
 }
 
+// bindJSONWithOptions binds the same request body into both the xcapi request,
+// and the options which contain the extra fields that are not yet defined in the xcapi IDL
+func bindJSONWithOptions(c *gin.Context, req interface{}, options interface{}) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, req); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, options)
+}
+
+// mergeJSONWithOptions merges the xcapi response and the options, which contain the extra fields
+// that are not yet defined in the xcapi IDL, into the same response body
+func mergeJSONWithOptions(resp interface{}, options interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	for _, obj := range []interface{}{resp, options} {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		// keep the numbers as they are
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// mergeProcessExecutionsWithOptions merges the options into each process execution of the ListProcessExecutions
+// API response, which then replace the ones in the response
+func mergeProcessExecutionsWithOptions(
+	resp *xcapi.ListProcessExecutionsResponse, infoOptions []ProcessExecutionListInfoOptions,
+) (map[string]interface{}, error) {
+	processExecutions := make([]map[string]interface{}, len(resp.ProcessExecutions))
+	for i := range resp.ProcessExecutions {
+		var err error
+		processExecutions[i], err = mergeJSONWithOptions(resp.ProcessExecutions[i], infoOptions[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mergeJSONWithOptions(resp, map[string]interface{}{
+		"processExecutions": processExecutions,
+	})
+}
+
 func invalidRequestSchema(c *gin.Context) {
 	c.JSON(http.StatusBadRequest, xcapi.ApiErrorResponse{
 		Details: xcapi.PtrString("invalid request schema"),
@@ -6,6 +6,7 @@ package api
 import (
 	"context"
 	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/persistence/data_models"
 )
 
 type Server interface {
@@ -17,17 +18,65 @@ type Server interface {
 // Service is the interface of API service, which decoupled from REST server framework like Gin
 // So that users can choose to use other REST frameworks to serve requests
 type Service interface {
-	StartProcess(ctx context.Context, request xcapi.ProcessExecutionStartRequest) (
-		resp *xcapi.ProcessExecutionStartResponse, err *ErrorWithStatus)
-	StopProcess(ctx context.Context, request xcapi.ProcessExecutionStopRequest) *ErrorWithStatus
+	StartProcess(
+		ctx context.Context, request xcapi.ProcessExecutionStartRequest, options ProcessExecutionStartOptions,
+	) (resp *xcapi.ProcessExecutionStartResponse, err *ErrorWithStatus)
+	StopProcess(
+		ctx context.Context, request xcapi.ProcessExecutionStopRequest, options ProcessExecutionStopOptions,
+	) *ErrorWithStatus
 	DescribeLatestProcess(ctx context.Context, request xcapi.ProcessExecutionDescribeRequest) (
-		resp *xcapi.ProcessExecutionDescribeResponse, err *ErrorWithStatus)
+		resp *xcapi.ProcessExecutionDescribeResponse, options *ProcessExecutionDescribeOptions, err *ErrorWithStatus)
+	DescribeProcessExecutionById(ctx context.Context, request ProcessExecutionDescribeByIdRequest) (
+		resp *xcapi.ProcessExecutionDescribeResponse, options *ProcessExecutionDescribeOptions, err *ErrorWithStatus)
+	ListProcessExecutionRuns(ctx context.Context, request ProcessExecutionRunsListRequest) (
+		resp *ProcessExecutionRunsListResponse, err *ErrorWithStatus)
 	PublishToLocalQueue(ctx context.Context, request xcapi.PublishToLocalQueueRequest) *ErrorWithStatus
+	PublishToLocalQueueWithStart(ctx context.Context, request PublishToLocalQueueWithStartRequest) (
+		resp *PublishToLocalQueueWithStartResponse, err *ErrorWithStatus)
 	Rpc(
-		ctx context.Context, request xcapi.ProcessExecutionRpcRequest,
+		ctx context.Context, request xcapi.ProcessExecutionRpcRequest, options ProcessExecutionRpcOptions,
 	) (resp *xcapi.ProcessExecutionRpcResponse, err *ErrorWithStatus)
-	ListProcessExecutions(ctx context.Context, request xcapi.ListProcessExecutionsRequest,
-	) (response *xcapi.ListProcessExecutionsResponse, retErr *ErrorWithStatus)
+	ListProcessExecutions(
+		ctx context.Context, request xcapi.ListProcessExecutionsRequest, options ListProcessExecutionsOptions,
+	) (response *xcapi.ListProcessExecutionsResponse, infoOptions []ProcessExecutionListInfoOptions,
+		retErr *ErrorWithStatus)
+	CountProcessExecutions(ctx context.Context, request CountProcessExecutionsRequest) (
+		resp *CountProcessExecutionsResponse, err *ErrorWithStatus)
 	WaitForProcessCompletion(ctx context.Context, request xcapi.ProcessExecutionWaitForCompletionRequest) (
-		resp *xcapi.ProcessExecutionWaitForCompletionResponse, err *ErrorWithStatus)
+		resp *xcapi.ProcessExecutionWaitForCompletionResponse, options *ProcessExecutionWaitForCompletionOptions,
+		err *ErrorWithStatus)
+	GetProcessExecutionHistory(ctx context.Context, request ProcessExecutionHistoryRequest) (
+		resp *ProcessExecutionHistoryResponse, err *ErrorWithStatus)
+	// SubscribeProcessEvents returns the channel of the process events, which is closed when the ctx is done,
+	// or any async server ends the stream
+	SubscribeProcessEvents(ctx context.Context, request ProcessEventsSubscribeRequest) (
+		events <-chan ProcessEvent, err *ErrorWithStatus)
+	StartBatchOperation(ctx context.Context, request BatchOperationStartRequest) (
+		resp *BatchOperationStartResponse, err *ErrorWithStatus)
+	DescribeBatchOperation(ctx context.Context, request BatchOperationDescribeRequest) (
+		resp *BatchOperationDescribeResponse, err *ErrorWithStatus)
+	CancelBatchOperation(ctx context.Context, request BatchOperationCancelRequest) *ErrorWithStatus
+	ResetProcessExecution(ctx context.Context, request ProcessExecutionResetRequest) (
+		resp *ProcessExecutionResetResponse, err *ErrorWithStatus)
+	PauseProcessExecution(ctx context.Context, request ProcessExecutionPauseRequest) *ErrorWithStatus
+	ResumeProcessExecution(ctx context.Context, request ProcessExecutionResumeRequest) *ErrorWithStatus
+	DescribeStateExecutions(ctx context.Context, request DescribeStateExecutionsRequest) (
+		resp *DescribeStateExecutionsResponse, err *ErrorWithStatus)
+	DescribeArchivedProcessExecution(ctx context.Context, request ArchivedProcessExecutionDescribeRequest) (
+		resp *data_models.ArchivedProcessExecutionJson, err *ErrorWithStatus)
+	GetLocalAttributes(ctx context.Context, request GetLocalAttributesRequest) (
+		resp *GetLocalAttributesResponse, err *ErrorWithStatus)
+	SetLocalAttributes(ctx context.Context, request SetLocalAttributesRequest) (
+		resp *SetLocalAttributesResponse, err *ErrorWithStatus)
+	CreateSchedule(ctx context.Context, request ScheduleCreateRequest) (
+		resp *ScheduleCreateResponse, err *ErrorWithStatus)
+	DescribeSchedule(ctx context.Context, request ScheduleDescribeRequest) (
+		resp *ScheduleDescribeResponse, err *ErrorWithStatus)
+	UpdateSchedule(ctx context.Context, request ScheduleUpdateRequest) (
+		resp *ScheduleUpdateResponse, err *ErrorWithStatus)
+	PauseSchedule(ctx context.Context, request SchedulePauseRequest) *ErrorWithStatus
+	ResumeSchedule(ctx context.Context, request ScheduleResumeRequest) *ErrorWithStatus
+	DeleteSchedule(ctx context.Context, request ScheduleDeleteRequest) *ErrorWithStatus
+	BackfillSchedule(ctx context.Context, request ScheduleBackfillRequest) (
+		resp *ScheduleBackfillResponse, err *ErrorWithStatus)
 }
@@ -5,16 +5,23 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"github.com/xcherryio/xcherry/engine"
 	"github.com/xcherryio/xcherry/service/async"
 	"github.com/xcherryio/xcherry/utils"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/common/decision"
 	"github.com/xcherryio/xcherry/common/httperror"
 	"github.com/xcherryio/xcherry/common/urlautofix"
+	"github.com/xcherryio/xcherry/common/uuid"
+	"github.com/xcherryio/xcherry/common/visibilityquery"
 	"github.com/xcherryio/xcherry/persistence/data_models"
 
 	"github.com/xcherryio/xcherry/common/log"
@@ -28,14 +35,17 @@ type serviceImpl struct {
 	cfg             config.Config
 	processStore    persistence.ProcessStore
 	visibilityStore persistence.VisibilityStore
-	logger          log.Logger
-	membership      async.Membership
+	// archiver is nil if the archival is disabled
+	archiver   persistence.Archiver
+	logger     log.Logger
+	membership async.Membership
 }
 
 func NewServiceImpl(
 	cfg config.Config,
 	processStore persistence.ProcessStore,
 	visibilityStore persistence.VisibilityStore,
+	archiver persistence.Archiver,
 	logger log.Logger,
 ) Service {
 	membershipImpl := async.NewMembershipImpl(cfg, logger, nil, async.ServerTypeApi)
@@ -44,33 +54,49 @@ func NewServiceImpl(
 		cfg:             cfg,
 		processStore:    processStore,
 		visibilityStore: visibilityStore,
+		archiver:        archiver,
 		logger:          logger,
 		membership:      membershipImpl,
 	}
 }
 
 func (s serviceImpl) StartProcess(
-	ctx context.Context, request xcapi.ProcessExecutionStartRequest,
+	ctx context.Context, request xcapi.ProcessExecutionStartRequest, options ProcessExecutionStartOptions,
 ) (response *xcapi.ProcessExecutionStartResponse, retErr *ErrorWithStatus) {
-	timeoutUnixSeconds := 0
-	if request.ProcessStartConfig != nil && request.ProcessStartConfig.TimeoutSeconds != nil {
-		timeoutUnixSeconds = int(request.ProcessStartConfig.GetTimeoutSeconds())
+	err := s.validateStartOptions(request, options)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
 	}
 
-	shardId := int32(utils.GetRandomShardId(s.cfg.Database.Shards))
-
-	storeReq := data_models.StartProcessRequest{
-		Request:        request,
-		NewTaskShardId: shardId,
+	prevRequest, errResp := s.getProcessRequest(
+		ctx, request.Namespace, request.ProcessId, options.RequestId, data_models.ProcessRequestTypeStart)
+	if errResp != nil {
+		return nil, errResp
 	}
-	if timeoutUnixSeconds > 0 {
-		storeReq.TimeoutTimeUnixSeconds = time.Now().Unix() + int64(timeoutUnixSeconds)
+	if prevRequest != nil {
+		return &xcapi.ProcessExecutionStartResponse{
+			ProcessExecutionId: prevRequest.ProcessExecutionId.String(),
+		}, nil
+	}
+
+	storeReq := s.newStartProcessStoreRequest(request, options)
+	if options.RequestId != nil {
+		storeReq.RequestId = *options.RequestId
 	}
+	shardId := storeReq.NewTaskShardId
 
 	resp, perr := s.processStore.StartProcess(ctx, storeReq)
 	if perr != nil {
 		return nil, s.handleUnknownError(perr)
 	}
+	if resp.DuplicateRequest != nil {
+		if errResp := validateProcessRequestType(resp.DuplicateRequest, data_models.ProcessRequestTypeStart); errResp != nil {
+			return nil, errResp
+		}
+		return &xcapi.ProcessExecutionStartResponse{
+			ProcessExecutionId: resp.DuplicateRequest.ProcessExecutionId.String(),
+		}, nil
+	}
 
 	if resp.AlreadyStarted {
 		return nil, NewErrorWithStatus(
@@ -92,13 +118,13 @@ func (s serviceImpl) StartProcess(
 		})
 	}
 
-	if storeReq.TimeoutTimeUnixSeconds != 0 {
+	if fireTimestamps := storeReq.GetTimerTaskFireTimestamps(); len(fireTimestamps) > 0 {
 		s.notifyRemoteTimerTaskAsync(ctx, xcapi.NotifyTimerTasksRequest{
 			ShardId:            shardId,
 			Namespace:          &request.Namespace,
 			ProcessId:          &request.ProcessId,
 			ProcessExecutionId: ptr.Any(resp.ProcessExecutionId.String()),
-			FireTimestamps:     []int64{storeReq.TimeoutTimeUnixSeconds},
+			FireTimestamps:     fireTimestamps,
 		})
 	}
 
@@ -107,17 +133,134 @@ func (s serviceImpl) StartProcess(
 	}, nil
 }
 
+func (s serviceImpl) newStartProcessStoreRequest(
+	request xcapi.ProcessExecutionStartRequest, options ProcessExecutionStartOptions,
+) data_models.StartProcessRequest {
+	timeoutUnixSeconds := 0
+	if request.ProcessStartConfig != nil && request.ProcessStartConfig.TimeoutSeconds != nil {
+		timeoutUnixSeconds = int(request.ProcessStartConfig.GetTimeoutSeconds())
+	}
+
+	storeReq := data_models.StartProcessRequest{
+		Request:          request,
+		NewTaskShardId:   int32(utils.GetRandomShardId(s.cfg.Database.Shards)),
+		SearchAttributes: options.SearchAttributes,
+
+		CompletionCallbackUrls:        options.CompletionCallbackUrls,
+		CompletionCallbackRetryPolicy: options.CompletionCallbackRetryPolicy,
+	}
+	startTime := time.Now().Unix()
+	if options.StartDelaySeconds != nil && *options.StartDelaySeconds > 0 {
+		startTime += int64(*options.StartDelaySeconds)
+		storeReq.DelayedStartTimeUnixSeconds = startTime
+	}
+	if timeoutUnixSeconds > 0 {
+		storeReq.TimeoutTimeUnixSeconds = startTime + int64(timeoutUnixSeconds)
+	}
+	return storeReq
+}
+
+func (s serviceImpl) validateStartOptions(
+	request xcapi.ProcessExecutionStartRequest, options ProcessExecutionStartOptions,
+) error {
+	err := data_models.ValidateSearchAttributes(s.cfg.Database.SearchAttributes, options.SearchAttributes, false)
+	if err != nil {
+		return err
+	}
+	if options.StartDelaySeconds != nil {
+		if *options.StartDelaySeconds < 0 {
+			return fmt.Errorf("startDelaySeconds cannot be negative")
+		}
+		if request.StartStateId == nil {
+			return fmt.Errorf("startDelaySeconds requires a startStateId")
+		}
+	}
+	if len(options.CompletionCallbackUrls) > MaxCompletionCallbackUrls {
+		return fmt.Errorf("at most %v completionCallbackUrls are allowed", MaxCompletionCallbackUrls)
+	}
+	for _, callbackUrl := range options.CompletionCallbackUrls {
+		if len(callbackUrl) > MaxCompletionCallbackUrlLength {
+			return fmt.Errorf("completionCallbackUrl cannot be longer than %v characters", MaxCompletionCallbackUrlLength)
+		}
+		parsed, err := url.Parse(callbackUrl)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid completionCallbackUrl %v", callbackUrl)
+		}
+	}
+	return nil
+}
+
+// getProcessRequest returns the request recorded with the request id,
+// nil if the request id is not provided or there is no such request
+func (s serviceImpl) getProcessRequest(
+	ctx context.Context, namespace, processId string, requestId *string, requestType data_models.ProcessRequestType,
+) (*data_models.GetProcessRequestResponse, *ErrorWithStatus) {
+	if requestId == nil {
+		return nil, nil
+	}
+	if *requestId == "" || len(*requestId) > MaxRequestIdLength {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("requestId must be non-empty and at most %v characters", MaxRequestIdLength))
+	}
+
+	resp, err := s.processStore.GetProcessRequest(ctx, data_models.GetProcessRequestRequest{
+		Namespace: namespace,
+		ProcessId: processId,
+		RequestId: *requestId,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, nil
+	}
+	if errResp := validateProcessRequestType(resp, requestType); errResp != nil {
+		return nil, errResp
+	}
+	return resp, nil
+}
+
+// validateProcessRequestType returns an error if the request id is recorded by a different type of request
+func validateProcessRequestType(
+	recorded *data_models.GetProcessRequestResponse, requestType data_models.ProcessRequestType,
+) *ErrorWithStatus {
+	if recorded.RequestType != requestType {
+		return NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("requestId is already used by a %v request", recorded.RequestType))
+	}
+	return nil
+}
+
 func (s serviceImpl) StopProcess(
-	ctx context.Context, request xcapi.ProcessExecutionStopRequest,
+	ctx context.Context, request xcapi.ProcessExecutionStopRequest, options ProcessExecutionStopOptions,
 ) *ErrorWithStatus {
-	resp, err := s.processStore.StopProcess(ctx, data_models.StopProcessRequest{
+	prevRequest, errResp := s.getProcessRequest(
+		ctx, request.GetNamespace(), request.GetProcessId(), options.RequestId, data_models.ProcessRequestTypeStop)
+	if errResp != nil {
+		return errResp
+	}
+	if prevRequest != nil {
+		return nil
+	}
+
+	storeReq := data_models.StopProcessRequest{
 		Namespace:       request.GetNamespace(),
 		ProcessId:       request.GetProcessId(),
 		ProcessStopType: request.GetStopType(),
-	})
+	}
+	if options.RequestId != nil {
+		storeReq.RequestId = *options.RequestId
+	}
+	if options.Reason != nil {
+		storeReq.Reason = *options.Reason
+	}
+	resp, err := s.processStore.StopProcess(ctx, storeReq)
 	if err != nil {
 		return s.handleUnknownError(err)
 	}
+	if resp.DuplicateRequest != nil {
+		return validateProcessRequestType(resp.DuplicateRequest, data_models.ProcessRequestTypeStop)
+	}
 
 	if resp.NotExists {
 		return NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
@@ -128,18 +271,130 @@ func (s serviceImpl) StopProcess(
 
 func (s serviceImpl) DescribeLatestProcess(
 	ctx context.Context, request xcapi.ProcessExecutionDescribeRequest,
-) (response *xcapi.ProcessExecutionDescribeResponse, retErr *ErrorWithStatus) {
+) (response *xcapi.ProcessExecutionDescribeResponse, options *ProcessExecutionDescribeOptions, retErr *ErrorWithStatus) {
 	resp, perr := s.processStore.DescribeLatestProcess(ctx, data_models.DescribeLatestProcessRequest{
 		Namespace: request.Namespace,
 		ProcessId: request.ProcessId,
 	})
 	if perr != nil {
-		return nil, s.handleUnknownError(perr)
+		return nil, nil, s.handleUnknownError(perr)
+	}
+	if resp.NotExists {
+		return nil, nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	return resp.Response, toProcessExecutionDescribeOptions(resp), nil
+}
+
+func (s serviceImpl) DescribeProcessExecutionById(
+	ctx context.Context, request ProcessExecutionDescribeByIdRequest,
+) (response *xcapi.ProcessExecutionDescribeResponse, options *ProcessExecutionDescribeOptions, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	prcExeId, err := uuid.ParseUUID(request.ProcessExecutionId)
+	if err != nil {
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+	}
+
+	resp, err := s.processStore.DescribeProcessExecution(ctx, data_models.DescribeProcessExecutionRequest{
+		Namespace:          request.Namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	if err != nil {
+		return nil, nil, s.handleUnknownError(err)
 	}
 	if resp.NotExists {
+		return nil, nil, NewErrorWithStatus(http.StatusNotFound, "Process execution does not exist")
+	}
+
+	return resp.Response, toProcessExecutionDescribeOptions(resp), nil
+}
+
+func toProcessExecutionDescribeOptions(
+	resp *data_models.DescribeLatestProcessResponse,
+) *ProcessExecutionDescribeOptions {
+	options := &ProcessExecutionDescribeOptions{
+		ProcessId: resp.ProcessId,
+	}
+	if resp.CloseTimestamp != 0 {
+		options.CloseTimestamp = ptr.Any(resp.CloseTimestamp)
+	}
+	if resp.DelayedStartTimestamp != 0 {
+		options.DelayedStartTimestamp = ptr.Any(resp.DelayedStartTimestamp)
+		options.PendingStart = resp.Response.GetStatus() == xcapi.RUNNING && resp.DelayedStartTimestamp > time.Now().Unix()
+	}
+	options.ParentProcess = resp.ParentProcess
+	options.ContinuedFromProcessExecutionId = resp.ContinuedFromProcessExecutionId
+	options.ContinuedAsNewProcessExecutionId = resp.ContinuedAsNewProcessExecutionId
+	options.Paused = resp.Paused
+	options.CloseRecord = resp.CloseRecord
+	for _, callback := range resp.CompletionCallbacks {
+		options.CompletionCallbacks = append(options.CompletionCallbacks, CompletionCallbackDescription{
+			Url:               callback.Url,
+			Status:            callback.Status.String(),
+			CompletedAttempts: callback.CompletedAttempts,
+			LastFailure:       callback.LastFailure,
+		})
+	}
+	for _, child := range resp.ChildProcesses {
+		options.ChildProcesses = append(options.ChildProcesses, ChildProcessDescription{
+			ProcessId:          child.ProcessId,
+			ProcessExecutionId: child.ProcessExecutionId,
+			StateId:            child.StateId,
+			StateIdSequence:    child.StateIdSequence,
+			StartTimestamp:     child.StartTimestamp,
+		})
+	}
+	return options
+}
+
+func (s serviceImpl) ListProcessExecutionRuns(
+	ctx context.Context, request ProcessExecutionRunsListRequest,
+) (response *ProcessExecutionRunsListResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" || request.ProcessId == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace and processId are required")
+	}
+	pageSize := int32(DefaultRunsPageSize)
+	if request.PageSize != nil {
+		pageSize = *request.PageSize
+	}
+	if pageSize <= 0 || pageSize > MaxRunsPageSize {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("page size should be between 1 and %v", MaxRunsPageSize))
+	}
+
+	resp, err := s.processStore.ListProcessExecutionRuns(ctx, data_models.ListProcessExecutionRunsRequest{
+		Namespace:     request.Namespace,
+		ProcessId:     request.ProcessId,
+		PageSize:      pageSize,
+		NextPageToken: request.NextPageToken,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if len(resp.Runs) == 0 && request.NextPageToken == nil {
 		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
 	}
-	return resp.Response, nil
+
+	runs := []ProcessExecutionRun{}
+	for _, run := range resp.Runs {
+		apiRun := ProcessExecutionRun{
+			ProcessExecutionId: run.ProcessExecutionId.String(),
+			ProcessType:        run.ProcessType,
+			Status:             xcapi.ProcessStatus(run.Status.String()),
+			StartTimestamp:     run.StartTimestamp,
+			Latest:             run.Latest,
+		}
+		if run.CloseTimestamp != 0 {
+			apiRun.CloseTimestamp = ptr.Any(run.CloseTimestamp)
+		}
+		runs = append(runs, apiRun)
+	}
+	return &ProcessExecutionRunsListResponse{
+		Runs:          runs,
+		NextPageToken: resp.NextPageToken,
+	}, nil
 }
 
 func (s serviceImpl) PublishToLocalQueue(
@@ -174,9 +429,83 @@ func (s serviceImpl) PublishToLocalQueue(
 	return nil
 }
 
+func (s serviceImpl) PublishToLocalQueueWithStart(
+	ctx context.Context, request PublishToLocalQueueWithStartRequest,
+) (response *PublishToLocalQueueWithStartResponse, retErr *ErrorWithStatus) {
+	if request.StartRequest.Namespace == "" || request.StartRequest.ProcessId == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "startRequest.namespace and startRequest.processId are required")
+	}
+	if len(request.Messages) == 0 {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "messages are required")
+	}
+	err := s.validateStartOptions(request.StartRequest, request.StartOptions)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
+	startReq := s.newStartProcessStoreRequest(request.StartRequest, request.StartOptions)
+	resp, err := s.processStore.PublishToLocalQueueWithStart(ctx, data_models.PublishToLocalQueueWithStartRequest{
+		StartRequest: startReq,
+		Messages:     request.Messages,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+
+	if resp.AlreadyStarted {
+		return nil, NewErrorWithStatus(
+			http.StatusConflict,
+			"Process is not running and the processIdReusePolicy disallows starting a new one")
+	}
+	if resp.FailedAtWritingAppDatabase {
+		return nil, NewErrorWithStatus(
+			http.StatusFailedDependency,
+			"Failed to write database, please check the error message for details: "+resp.AppDatabaseWritingError.Error())
+	}
+
+	if resp.HasNewImmediateTask {
+		s.notifyRemoteImmediateTaskAsync(ctx, xcapi.NotifyImmediateTasksRequest{
+			ShardId:            resp.ShardId,
+			Namespace:          &request.StartRequest.Namespace,
+			ProcessId:          &request.StartRequest.ProcessId,
+			ProcessExecutionId: ptr.Any(resp.ProcessExecutionId.String()),
+		})
+	}
+
+	if fireTimestamps := startReq.GetTimerTaskFireTimestamps(); resp.Started && len(fireTimestamps) > 0 {
+		s.notifyRemoteTimerTaskAsync(ctx, xcapi.NotifyTimerTasksRequest{
+			ShardId:            resp.ShardId,
+			Namespace:          &request.StartRequest.Namespace,
+			ProcessId:          &request.StartRequest.ProcessId,
+			ProcessExecutionId: ptr.Any(resp.ProcessExecutionId.String()),
+			FireTimestamps:     fireTimestamps,
+		})
+	}
+
+	return &PublishToLocalQueueWithStartResponse{
+		ProcessExecutionId: resp.ProcessExecutionId.String(),
+		Started:            resp.Started,
+	}, nil
+}
+
 func (s serviceImpl) Rpc(
-	ctx context.Context, request xcapi.ProcessExecutionRpcRequest,
+	ctx context.Context, request xcapi.ProcessExecutionRpcRequest, options ProcessExecutionRpcOptions,
 ) (response *xcapi.ProcessExecutionRpcResponse, retErr *ErrorWithStatus) {
+	prevRequest, errResp := s.getProcessRequest(
+		ctx, request.GetNamespace(), request.GetProcessId(), options.RequestId, data_models.ProcessRequestTypeRpc)
+	if errResp != nil {
+		return nil, errResp
+	}
+	if prevRequest != nil {
+		return &xcapi.ProcessExecutionRpcResponse{
+			Output: prevRequest.Response.RpcOutput,
+		}, nil
+	}
+	requestId := ""
+	if options.RequestId != nil {
+		requestId = *options.RequestId
+	}
+
 	latestPrcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
 		Namespace: request.GetNamespace(),
 		ProcessId: request.GetProcessId(),
@@ -244,11 +573,22 @@ func (s serviceImpl) Rpc(
 			http.StatusBadRequest, err.Error())
 	}
 
+	var respExt data_models.ProcessRpcWorkerResponseExtJson
+	err = data_models.ReadWorkerResponseExt(httpResp, &respExt)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	err = data_models.ValidateSearchAttributes(s.cfg.Database.SearchAttributes, respExt.UpsertSearchAttributes, true)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
 	updateResp, err := s.processStore.UpdateProcessExecutionForRpc(ctx, data_models.UpdateProcessExecutionForRpcRequest{
 		Namespace:          request.Namespace,
 		ProcessId:          request.ProcessId,
 		ProcessType:        latestPrcExe.ProcessType,
 		ProcessExecutionId: latestPrcExe.ProcessExecutionId,
+		RpcName:            request.GetRpcName(),
 
 		StateDecision:       resp.GetStateDecision(),
 		PublishToLocalQueue: resp.GetPublishToLocalQueue(),
@@ -256,12 +596,27 @@ func (s serviceImpl) Rpc(
 		AppDatabaseConfig: latestPrcExe.AppDatabaseConfig,
 		AppDatabaseWrite:  resp.WriteToAppDatabase,
 
+		UpsertSearchAttributes: respExt.UpsertSearchAttributes,
+
 		WorkerUrl:   latestPrcExe.WorkerUrl,
 		TaskShardId: latestPrcExe.ShardId,
+
+		RequestId: requestId,
+		RpcOutput: resp.Output,
 	})
 	if err != nil {
 		return nil, s.handleUnknownError(err)
 	}
+	if updateResp.DuplicateRequest != nil {
+		// the worker has been called by both the concurrent requests, because it's called before the request id is
+		// recorded, but only the decision of the recorded one is applied, and its output is returned to both
+		if errResp := validateProcessRequestType(updateResp.DuplicateRequest, data_models.ProcessRequestTypeRpc); errResp != nil {
+			return nil, errResp
+		}
+		return &xcapi.ProcessExecutionRpcResponse{
+			Output: updateResp.DuplicateRequest.Response.RpcOutput,
+		}, nil
+	}
 	if updateResp.FailAtWritingAppDatabase {
 		s.logger.Warn("failed to write app database")
 		return nil, NewErrorWithStatus(
@@ -288,81 +643,1133 @@ func (s serviceImpl) Rpc(
 	}, nil
 }
 
-func (s serviceImpl) ListProcessExecutions(ctx context.Context, request xcapi.ListProcessExecutionsRequest,
-) (response *xcapi.ListProcessExecutionsResponse, retErr *ErrorWithStatus) {
+func (s serviceImpl) ListProcessExecutions(
+	ctx context.Context, request xcapi.ListProcessExecutionsRequest, options ListProcessExecutionsOptions,
+) (response *xcapi.ListProcessExecutionsResponse, infoOptions []ProcessExecutionListInfoOptions, retErr *ErrorWithStatus) {
 	if request.Namespace == "" {
-		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
 	}
 	if request.PageSize <= 0 {
-		return nil, NewErrorWithStatus(http.StatusBadRequest, "page size should be positive")
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "page size should be positive")
 	}
-	if !request.HasStartTimeFilter() {
-		return nil, NewErrorWithStatus(http.StatusBadRequest, "start time filter is required")
+	if !request.HasStartTimeFilter() && options.Query == nil && options.CloseTimeFilter == nil {
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "start time filter is required without a query or close time filter")
 	}
-	if !request.StartTimeFilter.HasEarliestTime() || !request.StartTimeFilter.HasLatestTime() {
-		return nil, NewErrorWithStatus(http.StatusBadRequest, "both earliest and latest time are required for start time filter")
+	if errResp := validateTimeRangeFilter("start time", request.StartTimeFilter); errResp != nil {
+		return nil, nil, errResp
+	}
+	if errResp := validateTimeRangeFilter("close time", options.CloseTimeFilter); errResp != nil {
+		return nil, nil, errResp
+	}
+
+	query, errResp := parseVisibilityQuery(options.Query)
+	if errResp != nil {
+		return nil, nil, errResp
+	}
+	if len(options.SearchAttributesFilter) > 0 {
+		err := data_models.ValidateSearchAttributes(s.cfg.Database.SearchAttributes, options.SearchAttributesFilter, false)
+		if err != nil {
+			return nil, nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+		}
+		names := make([]string, 0, len(options.SearchAttributesFilter))
+		for name := range options.SearchAttributesFilter {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		filters := []visibilityquery.Expression{query.Where}
+		for _, name := range names {
+			filters = append(filters, visibilityquery.Compare(
+				name, visibilityquery.ComparisonOperatorEqual, options.SearchAttributesFilter[name]))
+		}
+		query.Where = visibilityquery.And(filters...)
+	}
+	if options.CloseReasonFilter != nil {
+		if errResp := validateCloseReasonFilter(*options.CloseReasonFilter); errResp != nil {
+			return nil, nil, errResp
+		}
+		query.Where = visibilityquery.And(query.Where, visibilityquery.Compare(
+			visibilityquery.FieldCloseReason, visibilityquery.ComparisonOperatorEqual, *options.CloseReasonFilter))
+	}
+	if options.SortBy != nil {
+		if query.OrderBy != nil {
+			return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "sortBy cannot be used together with ORDER BY in query")
+		}
+		var field string
+		switch {
+		case options.SortBy.Field != nil && options.SortBy.SearchAttribute != "":
+			return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "field and searchAttribute of sortBy cannot be used together")
+		case options.SortBy.Field != nil && *options.SortBy.Field == SortByFieldStartTime:
+			field = visibilityquery.FieldStartTime
+		case options.SortBy.Field != nil && *options.SortBy.Field == SortByFieldCloseTime:
+			field = visibilityquery.FieldCloseTime
+		case options.SortBy.Field != nil:
+			return nil, nil, NewErrorWithStatus(http.StatusBadRequest, "field of sortBy should be either START_TIME or CLOSE_TIME")
+		case visibilityquery.IsBuiltInField(options.SortBy.SearchAttribute):
+			return nil, nil, NewErrorWithStatus(http.StatusBadRequest,
+				options.SortBy.SearchAttribute+" is not a search attribute, use ORDER BY in query instead")
+		default:
+			field = options.SortBy.SearchAttribute
+		}
+		query.OrderBy = &visibilityquery.OrderBy{
+			Field:      field,
+			Descending: options.SortBy.Descending != nil && *options.SortBy.Descending,
+		}
+	}
+	if err := visibilityquery.Validate(query, s.cfg.Database.SearchAttributes); err != nil {
+		return nil, nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
 	}
 
-	resp, err := s.visibilityStore.ListProcessExecutions(ctx, request)
+	storeReq := data_models.ListProcessExecutionsRequest{
+		ListProcessExecutionsRequest: request,
+		CloseTimeFilter:              options.CloseTimeFilter,
+		Query:                        query,
+	}
+
+	resp, err := s.visibilityStore.ListProcessExecutions(ctx, storeReq)
 	if err != nil {
-		return nil, NewErrorWithStatus(http.StatusInternalServerError, err.Error())
+		return nil, nil, NewErrorWithStatus(http.StatusInternalServerError, err.Error())
 	}
-	return resp, nil
+	infoOptions = make([]ProcessExecutionListInfoOptions, len(resp.ProcessExecutions))
+	for i, closeRecord := range resp.CloseRecords {
+		infoOptions[i].CloseRecord = closeRecord
+	}
+	return &resp.ListProcessExecutionsResponse, infoOptions, nil
+}
+
+func (s serviceImpl) CountProcessExecutions(
+	ctx context.Context, request CountProcessExecutionsRequest,
+) (response *CountProcessExecutionsResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if request.StartTimeFilter == nil && request.Query == nil && request.CloseTimeFilter == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "start time filter is required without a query or close time filter")
+	}
+	if errResp := validateTimeRangeFilter("start time", request.StartTimeFilter); errResp != nil {
+		return nil, errResp
+	}
+	if errResp := validateTimeRangeFilter("close time", request.CloseTimeFilter); errResp != nil {
+		return nil, errResp
+	}
+
+	query, errResp := parseVisibilityQuery(request.Query)
+	if errResp != nil {
+		return nil, errResp
+	}
+	if query.OrderBy != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "ORDER BY is not supported for counting process executions")
+	}
+	filters := []visibilityquery.Expression{query.Where}
+	if request.StartTimeFilter != nil {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldStartTime, visibilityquery.ComparisonOperatorGreaterOrEqual,
+				request.StartTimeFilter.GetEarliestTime()),
+			visibilityquery.Compare(visibilityquery.FieldStartTime, visibilityquery.ComparisonOperatorLessOrEqual,
+				request.StartTimeFilter.GetLatestTime()))
+	}
+	if request.CloseTimeFilter != nil {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldCloseTime, visibilityquery.ComparisonOperatorGreaterOrEqual,
+				request.CloseTimeFilter.GetEarliestTime()),
+			visibilityquery.Compare(visibilityquery.FieldCloseTime, visibilityquery.ComparisonOperatorLessOrEqual,
+				request.CloseTimeFilter.GetLatestTime()))
+	}
+	if request.StatusFilter != nil {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldStatus, visibilityquery.ComparisonOperatorEqual, *request.StatusFilter))
+	}
+	if request.ProcessIdFilter != nil {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldProcessId, visibilityquery.ComparisonOperatorEqual, request.ProcessIdFilter.GetProcessId()))
+	}
+	if request.ProcessTypeFilter != nil {
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldProcessType, visibilityquery.ComparisonOperatorEqual, request.ProcessTypeFilter.ProcessType))
+	}
+	if request.CloseReasonFilter != nil {
+		if errResp := validateCloseReasonFilter(*request.CloseReasonFilter); errResp != nil {
+			return nil, errResp
+		}
+		filters = append(filters,
+			visibilityquery.Compare(visibilityquery.FieldCloseReason, visibilityquery.ComparisonOperatorEqual, *request.CloseReasonFilter))
+	}
+	query.Where = visibilityquery.And(filters...)
+	if err := visibilityquery.Validate(query, s.cfg.Database.SearchAttributes); err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
+	storeReq := data_models.CountProcessExecutionsRequest{
+		Namespace: request.Namespace,
+		Where:     query.Where,
+	}
+	if request.GroupBy != nil {
+		storeReq.GroupBy = data_models.ProcessExecutionCountGroupBy(*request.GroupBy)
+		if storeReq.GroupBy != data_models.ProcessExecutionCountGroupByStatus &&
+			storeReq.GroupBy != data_models.ProcessExecutionCountGroupByProcessType {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "groupBy should be either STATUS or PROCESS_TYPE")
+		}
+	}
+
+	resp, err := s.visibilityStore.CountProcessExecutions(ctx, storeReq)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+
+	response = &CountProcessExecutionsResponse{
+		Count: resp.Count,
+	}
+	for _, group := range resp.Groups {
+		countGroup := ProcessExecutionCountGroup{
+			Count: group.Count,
+		}
+		switch storeReq.GroupBy {
+		case data_models.ProcessExecutionCountGroupByStatus:
+			countGroup.Status = ptr.Any(xcapi.ProcessStatus(group.Status.String()))
+		case data_models.ProcessExecutionCountGroupByProcessType:
+			countGroup.ProcessType = ptr.Any(group.ProcessType)
+		}
+		response.Groups = append(response.Groups, countGroup)
+	}
+	return response, nil
+}
+
+func validateTimeRangeFilter(name string, filter *xcapi.TimeRangeFilter) *ErrorWithStatus {
+	if filter != nil && (!filter.HasEarliestTime() || !filter.HasLatestTime()) {
+		return NewErrorWithStatus(http.StatusBadRequest, "both earliest and latest time are required for "+name+" filter")
+	}
+	return nil
+}
+
+func validateCloseReasonFilter(closeReason string) *ErrorWithStatus {
+	if !data_models.ProcessCloseReasonType(closeReason).IsValid() {
+		return NewErrorWithStatus(http.StatusBadRequest, "unknown close reason "+closeReason)
+	}
+	return nil
+}
+
+// parseVisibilityQuery returns an empty query if not provided
+func parseVisibilityQuery(query *string) (*visibilityquery.Query, *ErrorWithStatus) {
+	if query == nil {
+		return &visibilityquery.Query{}, nil
+	}
+	parsed, err := visibilityquery.Parse(*query)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+	return parsed, nil
 }
 
 func (s serviceImpl) WaitForProcessCompletion(
 	ctx context.Context, request xcapi.ProcessExecutionWaitForCompletionRequest,
-) (response *xcapi.ProcessExecutionWaitForCompletionResponse, retErr *ErrorWithStatus) {
-	var timeout = engine.DEFAULT_WAIT_FOR_TIMEOUT_MAX
+) (response *xcapi.ProcessExecutionWaitForCompletionResponse, options *ProcessExecutionWaitForCompletionOptions,
+	retErr *ErrorWithStatus) {
+	timeout := time.Second * time.Duration(engine.DEFAULT_WAIT_FOR_TIMEOUT_MAX)
 	if request.TimeoutSeconds != nil {
-		timeout = *request.TimeoutSeconds
+		timeout = time.Second * time.Duration(*request.TimeoutSeconds)
 	}
-	if timeout > engine.DEFAULT_WAIT_FOR_TIMEOUT_MAX {
-		timeout = engine.DEFAULT_WAIT_FOR_TIMEOUT_MAX
+	if timeout > s.maxWaitForProcessCompletionTimeout() {
+		timeout = s.maxWaitForProcessCompletionTimeout()
 	}
 
-	ctx, canf := context.WithTimeout(ctx, time.Second*time.Duration(timeout))
+	ctx, canf := context.WithTimeout(ctx, timeout)
 	defer canf()
 
-	latestPrcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+	timeoutResponse := &xcapi.ProcessExecutionWaitForCompletionResponse{
+		Timeout: xcapi.PtrBool(true),
+	}
+
+	prcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
 		Namespace: request.GetNamespace(),
 		ProcessId: request.GetProcessId(),
 	})
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return &xcapi.ProcessExecutionWaitForCompletionResponse{
-				Timeout: xcapi.PtrBool(true),
-			}, nil
+			return timeoutResponse, &ProcessExecutionWaitForCompletionOptions{}, nil
 		}
-		return nil, s.handleUnknownError(err)
+		return nil, nil, s.handleUnknownError(err)
 	}
 
-	if latestPrcExe.NotExists {
-		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	if prcExe.NotExists {
+		return nil, nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
 	}
 
-	if latestPrcExe.Status != data_models.ProcessExecutionStatusUndefined &&
-		latestPrcExe.Status != data_models.ProcessExecutionStatusRunning {
-		return &xcapi.ProcessExecutionWaitForCompletionResponse{
-			Timeout:      xcapi.PtrBool(false),
-			StopBySystem: xcapi.PtrBool(false),
-			Status:       xcapi.ProcessStatus(latestPrcExe.Status.String()).Ptr(),
-		}, nil
-	}
+	// The waiting is done in rounds, each round waits in the async server owning the shard for a limited time.
+	// When a round ends without the completion, e.g. the shard is moved to another async server, the process execution
+	// is checked again in the database, so that the waiting can re-attach to the new owner of the shard.
+	for {
+		if prcExe.CloseResult != nil && prcExe.CloseResult.ContinuedAsNewProcessExecutionId != "" {
+			// the process is continued as new, so wait for the new process execution instead
+			prcExe, err = s.processStore.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+				Namespace:          request.GetNamespace(),
+				ProcessExecutionId: uuid.MustParseUUID(prcExe.CloseResult.ContinuedAsNewProcessExecutionId),
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return timeoutResponse, &ProcessExecutionWaitForCompletionOptions{}, nil
+				}
+				return nil, nil, s.handleUnknownError(err)
+			}
+			if prcExe.NotExists {
+				return nil, nil, NewErrorWithStatus(http.StatusNotFound, "Process execution does not exist")
+			}
+			continue
+		}
 
-	resp, err := s.askRemoteWaitForProcessCompletion(ctx, xcapi.WaitForProcessCompletionRequest{
-		ShardId:            latestPrcExe.ShardId,
-		ProcessExecutionId: latestPrcExe.ProcessExecutionId.String(),
-	})
-	if err != nil {
-		return nil, NewErrorWithStatus(http.StatusInternalServerError, err.Error())
+		if prcExe.Status != data_models.ProcessExecutionStatusUndefined &&
+			prcExe.Status != data_models.ProcessExecutionStatusRunning {
+			options = &ProcessExecutionWaitForCompletionOptions{
+				ProcessExecutionId: prcExe.ProcessExecutionId.String(),
+			}
+			if prcExe.CloseResult != nil {
+				options.Output = prcExe.CloseResult.Output
+				options.Failure = prcExe.CloseResult.Failure
+				options.CloseRecord = &prcExe.CloseResult.ProcessCloseRecordJson
+			}
+			return &xcapi.ProcessExecutionWaitForCompletionResponse{
+				Timeout:      xcapi.PtrBool(false),
+				StopBySystem: xcapi.PtrBool(false),
+				Status:       xcapi.ProcessStatus(prcExe.Status.String()).Ptr(),
+			}, options, nil
+		}
+
+		roundCtx, roundCanf := context.WithTimeout(ctx, time.Second*time.Duration(engine.DEFAULT_WAIT_FOR_TIMEOUT_MAX))
+		_, err = s.askRemoteWaitForProcessCompletion(roundCtx, xcapi.WaitForProcessCompletionRequest{
+			ShardId:            prcExe.ShardId,
+			ProcessExecutionId: prcExe.ProcessExecutionId.String(),
+		})
+		roundCanf()
+		if err != nil {
+			// the async server may be restarting or rebalancing, back off before checking again
+			s.logger.Warn("failed to wait for process completion in the async server, will check again",
+				tag.ProcessExecutionId(prcExe.ProcessExecutionId.String()), tag.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+		if ctx.Err() != nil {
+			return timeoutResponse, &ProcessExecutionWaitForCompletionOptions{}, nil
+		}
+
+		// the response of the round doesn't have the output, so always read the closed process execution from database
+		prcExe, err = s.processStore.GetProcessExecution(ctx, data_models.GetProcessExecutionRequest{
+			Namespace:          request.GetNamespace(),
+			ProcessExecutionId: prcExe.ProcessExecutionId,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return timeoutResponse, &ProcessExecutionWaitForCompletionOptions{}, nil
+			}
+			return nil, nil, s.handleUnknownError(err)
+		}
+		if prcExe.NotExists {
+			return nil, nil, NewErrorWithStatus(http.StatusNotFound, "Process execution does not exist")
+		}
 	}
+}
 
-	return &xcapi.ProcessExecutionWaitForCompletionResponse{
-		Timeout:      resp.Timeout,
-		StopBySystem: resp.StopBySystem,
-		Status:       resp.Status,
-	}, nil
+// maxWaitForProcessCompletionTimeout caps the timeout by the WriteTimeout of the http server,
+// so that the response can still be written
+func (s serviceImpl) maxWaitForProcessCompletionTimeout() time.Duration {
+	maxTimeout := s.cfg.ApiService.WaitForProcessCompletion.MaxTimeout
+	writeTimeout := s.cfg.ApiService.HttpServer.WriteTimeout
+	if writeTimeout > time.Second && writeTimeout-time.Second < maxTimeout {
+		maxTimeout = writeTimeout - time.Second
+	}
+	return maxTimeout
+}
+
+func (s serviceImpl) GetProcessExecutionHistory(
+	ctx context.Context, request ProcessExecutionHistoryRequest,
+) (response *ProcessExecutionHistoryResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if request.ProcessId == "" && request.ProcessExecutionId == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "either processId or processExecutionId is required")
+	}
+
+	pageSize := int32(DefaultHistoryPageSize)
+	if request.PageSize != nil {
+		pageSize = *request.PageSize
+	}
+	if pageSize <= 0 || pageSize > MaxHistoryPageSize {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("page size should be between 1 and %v", MaxHistoryPageSize))
+	}
+
+	storeReq := data_models.GetProcessExecutionHistoryRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+		PageSize:  pageSize,
+	}
+	if request.ProcessExecutionId != nil {
+		prcExeId, err := uuid.ParseUUID(*request.ProcessExecutionId)
+		if err != nil {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+		}
+		storeReq.ProcessExecutionId = &prcExeId
+	}
+	if request.NextPageToken != nil {
+		lastEventId, err := strconv.ParseInt(*request.NextPageToken, 10, 32)
+		if err != nil {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid nextPageToken")
+		}
+		storeReq.MinEventIdExclusive = int32(lastEventId)
+	}
+
+	resp, err := s.processStore.GetProcessExecutionHistory(ctx, storeReq)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	events := []ProcessExecutionHistoryEvent{}
+	for _, event := range resp.Events {
+		apiEvent := ProcessExecutionHistoryEvent{
+			EventId:   event.EventId,
+			EventType: event.EventType.String(),
+			Timestamp: event.CreateTimestamp,
+			Info:      event.Info,
+		}
+		if event.StateExecutionId.StateId != "" {
+			apiEvent.StateId = ptr.Any(event.StateExecutionId.StateId)
+			apiEvent.StateIdSequence = ptr.Any(event.StateExecutionId.StateIdSequence)
+		}
+		events = append(events, apiEvent)
+	}
+
+	response = &ProcessExecutionHistoryResponse{
+		ProcessExecutionId: resp.ProcessExecutionId.String(),
+		Events:             events,
+	}
+	if resp.HasMore && len(resp.Events) > 0 {
+		response.NextPageToken = ptr.Any(strconv.Itoa(int(resp.Events[len(resp.Events)-1].EventId)))
+	}
+	return response, nil
+}
+
+func (s serviceImpl) StartBatchOperation(
+	ctx context.Context, request BatchOperationStartRequest,
+) (response *BatchOperationStartResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if !request.StartTimeFilter.HasEarliestTime() || !request.StartTimeFilter.HasLatestTime() {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			"both earliest and latest time are required for start time filter")
+	}
+	err := validateBatchOperationAction(request.Action)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
+	batchCfg := s.cfg.ApiService.BatchOperation
+	rps := batchCfg.DefaultRps
+	if request.Rps != nil {
+		rps = *request.Rps
+	}
+	if rps <= 0 || rps > batchCfg.MaxRps {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("rps should be between 1 and %v", batchCfg.MaxRps))
+	}
+
+	batchOperationId := uuid.MustNewUUID().String()
+	if request.BatchOperationId != nil && *request.BatchOperationId != "" {
+		batchOperationId = *request.BatchOperationId
+	}
+	if len(batchOperationId) > MaxBatchOperationIdLength {
+		return nil, NewErrorWithStatus(http.StatusBadRequest,
+			fmt.Sprintf("batchOperationId must be at most %v characters", MaxBatchOperationIdLength))
+	}
+
+	resp, err := s.processStore.CreateBatchOperation(ctx, data_models.CreateBatchOperationRequest{
+		Namespace:        request.Namespace,
+		BatchOperationId: batchOperationId,
+		Info: data_models.BatchOperationInfoJson{
+			Filter: data_models.BatchOperationFilterJson{
+				ProcessType:       request.ProcessTypeFilter,
+				Status:            request.StatusFilter,
+				EarliestStartTime: request.StartTimeFilter.GetEarliestTime(),
+				LatestStartTime:   request.StartTimeFilter.GetLatestTime(),
+			},
+			Action: request.Action,
+		},
+		Rps: rps,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.AlreadyExists {
+		return nil, NewErrorWithStatus(http.StatusConflict, "Batch operation already exists")
+	}
+
+	return &BatchOperationStartResponse{
+		BatchOperationId: batchOperationId,
+	}, nil
+}
+
+func (s serviceImpl) DescribeBatchOperation(
+	ctx context.Context, request BatchOperationDescribeRequest,
+) (response *BatchOperationDescribeResponse, retErr *ErrorWithStatus) {
+	resp, err := s.processStore.DescribeBatchOperation(ctx, data_models.DescribeBatchOperationRequest{
+		Namespace:        request.Namespace,
+		BatchOperationId: request.BatchOperationId,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Batch operation does not exist")
+	}
+
+	batchOperation := resp.BatchOperation
+	response = &BatchOperationDescribeResponse{
+		BatchOperationId:    batchOperation.BatchOperationId,
+		Status:              batchOperation.Status.String(),
+		Filter:              batchOperation.Info.Filter,
+		Action:              batchOperation.Info.Action,
+		Rps:                 batchOperation.Rps,
+		SucceededCount:      batchOperation.SucceededCount,
+		FailedCount:         batchOperation.FailedCount,
+		SkippedCount:        batchOperation.SkippedCount,
+		CreateTimestamp:     batchOperation.CreateTimestamp,
+		LastUpdateTimestamp: batchOperation.LastUpdateTimestamp,
+	}
+	if batchOperation.ErrorMessage != "" {
+		response.LastErrorMessage = ptr.Any(batchOperation.ErrorMessage)
+	}
+	return response, nil
+}
+
+func (s serviceImpl) CancelBatchOperation(
+	ctx context.Context, request BatchOperationCancelRequest,
+) *ErrorWithStatus {
+	resp, err := s.processStore.CancelBatchOperation(ctx, data_models.CancelBatchOperationRequest{
+		Namespace:        request.Namespace,
+		BatchOperationId: request.BatchOperationId,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Batch operation does not exist")
+	}
+	if resp.AlreadyClosed {
+		return NewErrorWithStatus(http.StatusBadRequest, "Batch operation is not running")
+	}
+	return nil
+}
+
+func (s serviceImpl) CreateSchedule(
+	ctx context.Context, request ScheduleCreateRequest,
+) (response *ScheduleCreateResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" || request.ScheduleId == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace and scheduleId are required")
+	}
+	info, err := s.newScheduleInfo(
+		request.Namespace, request.Spec, request.StartRequest, request.StartOptions, request.OverlapPolicy)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
+	shardId := int32(utils.GetRandomShardId(s.cfg.Database.Shards))
+	resp, err := s.processStore.CreateSchedule(ctx, data_models.CreateScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+		ShardId:    shardId,
+		Info:       *info,
+		Paused:     request.Paused != nil && *request.Paused,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.AlreadyExists {
+		return nil, NewErrorWithStatus(http.StatusConflict, "Schedule already exists")
+	}
+
+	s.notifyRemoteScheduleTimerTaskAsync(ctx, shardId, resp.NextFireTimestamp)
+	return &ScheduleCreateResponse{
+		NextFireTimestamp: nonZeroTimestamp(resp.NextFireTimestamp),
+	}, nil
+}
+
+func (s serviceImpl) DescribeSchedule(
+	ctx context.Context, request ScheduleDescribeRequest,
+) (response *ScheduleDescribeResponse, retErr *ErrorWithStatus) {
+	resp, err := s.processStore.DescribeSchedule(ctx, data_models.DescribeScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Schedule does not exist")
+	}
+
+	schedule := resp.Schedule
+	response = &ScheduleDescribeResponse{
+		ScheduleId:   schedule.ScheduleId,
+		Spec:         schedule.Info.Spec,
+		StartRequest: schedule.Info.StartRequest,
+		StartOptions: ProcessExecutionStartOptions{
+			SearchAttributes: schedule.Info.SearchAttributes,
+		},
+		OverlapPolicy:       schedule.Info.OverlapPolicy,
+		Paused:              schedule.Paused,
+		NextFireTimestamp:   nonZeroTimestamp(schedule.NextFireTimestamp),
+		LastFireTimestamp:   nonZeroTimestamp(schedule.LastFireTimestamp),
+		FiredCount:          schedule.FiredCount,
+		SkippedCount:        schedule.SkippedCount,
+		CreateTimestamp:     schedule.CreateTimestamp,
+		LastUpdateTimestamp: schedule.LastUpdateTimestamp,
+	}
+	if schedule.LastProcessId != "" {
+		response.LastProcessId = ptr.Any(schedule.LastProcessId)
+	}
+	return response, nil
+}
+
+func (s serviceImpl) UpdateSchedule(
+	ctx context.Context, request ScheduleUpdateRequest,
+) (response *ScheduleUpdateResponse, retErr *ErrorWithStatus) {
+	info, err := s.newScheduleInfo(
+		request.Namespace, request.Spec, request.StartRequest, request.StartOptions, request.OverlapPolicy)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, err.Error())
+	}
+
+	nextFireTimestamp, errResp := s.updateSchedule(ctx, data_models.UpdateScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+		Info:       info,
+	})
+	if errResp != nil {
+		return nil, errResp
+	}
+	return &ScheduleUpdateResponse{
+		NextFireTimestamp: nonZeroTimestamp(nextFireTimestamp),
+	}, nil
+}
+
+func (s serviceImpl) PauseSchedule(ctx context.Context, request SchedulePauseRequest) *ErrorWithStatus {
+	_, errResp := s.updateSchedule(ctx, data_models.UpdateScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+		Paused:     ptr.Any(true),
+	})
+	return errResp
+}
+
+func (s serviceImpl) ResumeSchedule(ctx context.Context, request ScheduleResumeRequest) *ErrorWithStatus {
+	_, errResp := s.updateSchedule(ctx, data_models.UpdateScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+		Paused:     ptr.Any(false),
+	})
+	return errResp
+}
+
+func (s serviceImpl) updateSchedule(
+	ctx context.Context, request data_models.UpdateScheduleRequest,
+) (int64, *ErrorWithStatus) {
+	resp, err := s.processStore.UpdateSchedule(ctx, request)
+	if err != nil {
+		return 0, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return 0, NewErrorWithStatus(http.StatusNotFound, "Schedule does not exist")
+	}
+
+	s.notifyRemoteScheduleTimerTaskAsync(ctx, resp.ShardId, resp.NextFireTimestamp)
+	return resp.NextFireTimestamp, nil
+}
+
+func (s serviceImpl) DeleteSchedule(ctx context.Context, request ScheduleDeleteRequest) *ErrorWithStatus {
+	resp, err := s.processStore.DeleteSchedule(ctx, data_models.DeleteScheduleRequest{
+		Namespace:  request.Namespace,
+		ScheduleId: request.ScheduleId,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Schedule does not exist")
+	}
+	return nil
+}
+
+func (s serviceImpl) BackfillSchedule(
+	ctx context.Context, request ScheduleBackfillRequest,
+) (response *ScheduleBackfillResponse, retErr *ErrorWithStatus) {
+	if request.StartTimestamp >= request.EndTimestamp {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "startTimestamp must be before endTimestamp")
+	}
+
+	resp, err := s.processStore.BackfillSchedule(ctx, data_models.BackfillScheduleRequest{
+		Namespace:      request.Namespace,
+		ScheduleId:     request.ScheduleId,
+		StartTimestamp: request.StartTimestamp,
+		EndTimestamp:   request.EndTimestamp,
+		MaxFirings:     MaxScheduleBackfillFirings,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Schedule does not exist")
+	}
+
+	response = &ScheduleBackfillResponse{
+		StartedProcessExecutionIds: []string{},
+		SkippedCount:               resp.SkippedCount,
+		NextStartTimestamp:         nonZeroTimestamp(resp.NextStartTimestamp),
+	}
+	for _, prcExeId := range resp.StartedProcessExecutionIds {
+		response.StartedProcessExecutionIds = append(response.StartedProcessExecutionIds, prcExeId.String())
+	}
+
+	if len(resp.StartedProcessExecutionIds) > 0 {
+		s.notifyRemoteImmediateTaskAsync(ctx, xcapi.NotifyImmediateTasksRequest{
+			ShardId: resp.ShardId,
+		})
+	}
+	if len(resp.TimeoutTimestamps) > 0 {
+		s.notifyRemoteTimerTaskAsync(ctx, xcapi.NotifyTimerTasksRequest{
+			ShardId:        resp.ShardId,
+			FireTimestamps: resp.TimeoutTimestamps,
+		})
+	}
+	return response, nil
+}
+
+// newScheduleInfo validates the definition of the schedule
+func (s serviceImpl) newScheduleInfo(
+	namespace string,
+	spec data_models.ScheduleSpecJson,
+	startRequest xcapi.ProcessExecutionStartRequest,
+	startOptions ProcessExecutionStartOptions,
+	overlapPolicy *data_models.ScheduleOverlapPolicy,
+) (*data_models.ScheduleInfoJson, error) {
+	err := spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if startRequest.ProcessId == "" || startRequest.ProcessType == "" || startRequest.WorkerUrl == "" {
+		return nil, fmt.Errorf("processId, processType and workerUrl are required in the startRequest")
+	}
+	if startRequest.ProcessStartConfig != nil && startRequest.ProcessStartConfig.AppDatabaseConfig != nil {
+		return nil, fmt.Errorf("appDatabaseConfig is not supported for schedules")
+	}
+	err = data_models.ValidateSearchAttributes(s.cfg.Database.SearchAttributes, startOptions.SearchAttributes, false)
+	if err != nil {
+		return nil, err
+	}
+	if startOptions.StartDelaySeconds != nil {
+		return nil, fmt.Errorf("startDelaySeconds is not supported for schedules")
+	}
+
+	policy := data_models.ScheduleOverlapPolicySkip
+	if overlapPolicy != nil {
+		policy = *overlapPolicy
+	}
+	switch policy {
+	case data_models.ScheduleOverlapPolicySkip, data_models.ScheduleOverlapPolicyAllowAll,
+		data_models.ScheduleOverlapPolicyTerminatePrevious:
+	default:
+		return nil, fmt.Errorf("unknown overlap policy %v", policy)
+	}
+
+	startRequest.Namespace = namespace
+	return &data_models.ScheduleInfoJson{
+		Spec:             spec,
+		StartRequest:     startRequest,
+		SearchAttributes: startOptions.SearchAttributes,
+		OverlapPolicy:    policy,
+	}, nil
+}
+
+// notifyRemoteScheduleTimerTaskAsync notifies the timer task to fire the schedule, it's a noop if it won't fire
+func (s serviceImpl) notifyRemoteScheduleTimerTaskAsync(ctx context.Context, shardId int32, nextFireTimestamp int64) {
+	if nextFireTimestamp == 0 {
+		return
+	}
+	s.notifyRemoteTimerTaskAsync(ctx, xcapi.NotifyTimerTasksRequest{
+		ShardId:        shardId,
+		FireTimestamps: []int64{nextFireTimestamp},
+	})
+}
+
+func nonZeroTimestamp(timestamp int64) *int64 {
+	if timestamp == 0 {
+		return nil
+	}
+	return ptr.Any(timestamp)
+}
+
+func (s serviceImpl) ResetProcessExecution(
+	ctx context.Context, request ProcessExecutionResetRequest,
+) (response *ProcessExecutionResetResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" || request.ProcessId == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace and processId are required")
+	}
+	if request.StateId == "" || request.StateIdSequence <= 0 {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "stateId and a positive stateIdSequence are required")
+	}
+
+	latestPrcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if latestPrcExe.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	reason := ""
+	if request.Reason != nil {
+		reason = *request.Reason
+	}
+
+	resp, err := s.processStore.ResetProcessExecution(ctx, data_models.ResetProcessExecutionRequest{
+		ProcessExecutionId: latestPrcExe.ProcessExecutionId,
+		ResetToStateExecutionId: data_models.StateExecutionId{
+			StateId:         request.StateId,
+			StateIdSequence: request.StateIdSequence,
+		},
+		Reason: reason,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.ProcessNotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+	if resp.ProcessNotRunning {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "Process is not running")
+	}
+	if resp.StateExecutionNotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "State execution does not exist")
+	}
+
+	if resp.HasNewImmediateTask {
+		processExecutionIdString := latestPrcExe.ProcessExecutionId.String()
+
+		s.notifyRemoteImmediateTaskAsync(ctx, xcapi.NotifyImmediateTasksRequest{
+			ShardId:            latestPrcExe.ShardId,
+			Namespace:          &request.Namespace,
+			ProcessId:          &request.ProcessId,
+			ProcessExecutionId: &processExecutionIdString,
+		})
+	}
+
+	return &ProcessExecutionResetResponse{
+		ProcessExecutionId: latestPrcExe.ProcessExecutionId.String(),
+		StateId:            resp.NewStateExecutionId.StateId,
+		StateIdSequence:    resp.NewStateExecutionId.StateIdSequence,
+	}, nil
+}
+
+func (s serviceImpl) PauseProcessExecution(
+	ctx context.Context, request ProcessExecutionPauseRequest,
+) *ErrorWithStatus {
+	if request.Namespace == "" || request.ProcessId == "" {
+		return NewErrorWithStatus(http.StatusBadRequest, "namespace and processId are required")
+	}
+
+	latestPrcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if latestPrcExe.NotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	reason := ""
+	if request.Reason != nil {
+		reason = *request.Reason
+	}
+
+	resp, err := s.processStore.PauseProcessExecution(ctx, data_models.PauseProcessExecutionRequest{
+		ProcessExecutionId: latestPrcExe.ProcessExecutionId,
+		Reason:             reason,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if resp.ProcessNotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+	if resp.ProcessNotRunning {
+		return NewErrorWithStatus(http.StatusMethodNotAllowed, "Process is not running")
+	}
+	return nil
+}
+
+func (s serviceImpl) ResumeProcessExecution(
+	ctx context.Context, request ProcessExecutionResumeRequest,
+) *ErrorWithStatus {
+	if request.Namespace == "" || request.ProcessId == "" {
+		return NewErrorWithStatus(http.StatusBadRequest, "namespace and processId are required")
+	}
+
+	latestPrcExe, err := s.processStore.GetLatestProcessExecution(ctx, data_models.GetLatestProcessExecutionRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if latestPrcExe.NotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	reason := ""
+	if request.Reason != nil {
+		reason = *request.Reason
+	}
+
+	resp, err := s.processStore.ResumeProcessExecution(ctx, data_models.ResumeProcessExecutionRequest{
+		ProcessExecutionId: latestPrcExe.ProcessExecutionId,
+		Reason:             reason,
+	})
+	if err != nil {
+		return s.handleUnknownError(err)
+	}
+	if resp.ProcessNotExists {
+		return NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+	if resp.ProcessNotRunning {
+		return NewErrorWithStatus(http.StatusMethodNotAllowed, "Process is not running")
+	}
+
+	if resp.HasNewImmediateTask {
+		processExecutionIdString := latestPrcExe.ProcessExecutionId.String()
+
+		s.notifyRemoteImmediateTaskAsync(ctx, xcapi.NotifyImmediateTasksRequest{
+			ShardId:            latestPrcExe.ShardId,
+			Namespace:          &request.Namespace,
+			ProcessId:          &request.ProcessId,
+			ProcessExecutionId: &processExecutionIdString,
+		})
+	}
+	return nil
+}
+
+func (s serviceImpl) DescribeStateExecutions(
+	ctx context.Context, request DescribeStateExecutionsRequest,
+) (response *DescribeStateExecutionsResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if request.ProcessId == "" && request.ProcessExecutionId == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "either processId or processExecutionId is required")
+	}
+
+	storeReq := data_models.DescribeStateExecutionsRequest{
+		Namespace:   request.Namespace,
+		ProcessId:   request.ProcessId,
+		PendingOnly: request.PendingOnly != nil && *request.PendingOnly,
+	}
+	if request.ProcessExecutionId != nil {
+		prcExeId, err := uuid.ParseUUID(*request.ProcessExecutionId)
+		if err != nil {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+		}
+		storeReq.ProcessExecutionId = &prcExeId
+	}
+
+	resp, err := s.processStore.DescribeStateExecutions(ctx, storeReq)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	stateExecutions := []StateExecutionDescription{}
+	for _, stateExe := range resp.StateExecutions {
+		desc := StateExecutionDescription{
+			StateId:         stateExe.StateId,
+			StateIdSequence: stateExe.StateIdSequence,
+			Status:          stateExe.Status.String(),
+			Pending:         stateExe.Pending,
+
+			WaitUntilCommands:       ptr.Any(stateExe.WaitUntilCommands),
+			WaitUntilCommandResults: ptr.Any(stateExe.WaitUntilCommandResults),
+		}
+		if stateExe.LastFailure != nil {
+			failure := StateExecutionFailure{
+				StatusCode:           stateExe.LastFailure.StatusCode,
+				Details:              stateExe.LastFailure.Details,
+				CompletedAttempts:    stateExe.LastFailure.CompletedAttempts,
+				LastAttemptTimestamp: stateExe.LastFailure.LastAttemptTimestamp,
+			}
+			if stateExe.Status == data_models.StateExecutionStatusWaitUntilRunning ||
+				stateExe.Status == data_models.StateExecutionStatusExecuteRunning {
+				// still backing off for the next attempt
+				failure.NextAttemptTimestamp = stateExe.LastFailure.NextAttemptTimestamp
+			}
+			desc.LastFailure = &failure
+		}
+		stateExecutions = append(stateExecutions, desc)
+	}
+
+	return &DescribeStateExecutionsResponse{
+		ProcessExecutionId: resp.ProcessExecutionId.String(),
+		StateExecutions:    stateExecutions,
+	}, nil
+}
+
+func (s serviceImpl) DescribeArchivedProcessExecution(
+	ctx context.Context, request ArchivedProcessExecutionDescribeRequest,
+) (response *data_models.ArchivedProcessExecutionJson, retErr *ErrorWithStatus) {
+	if s.archiver == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "archival is not enabled")
+	}
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	prcExeId, err := uuid.ParseUUID(request.ProcessExecutionId)
+	if err != nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+	}
+
+	resp, err := s.archiver.GetArchivedProcessExecution(ctx, data_models.GetArchivedProcessExecutionRequest{
+		Namespace:          request.Namespace,
+		ProcessExecutionId: prcExeId,
+	})
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Archived process execution does not exist")
+	}
+	return &resp.Record, nil
+}
+
+func (s serviceImpl) GetLocalAttributes(
+	ctx context.Context, request GetLocalAttributesRequest,
+) (response *GetLocalAttributesResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if request.ProcessId == "" && request.ProcessExecutionId == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "either processId or processExecutionId is required")
+	}
+
+	storeReq := data_models.GetLocalAttributesRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+		Keys:      request.Keys,
+	}
+	if request.ProcessExecutionId != nil {
+		prcExeId, err := uuid.ParseUUID(*request.ProcessExecutionId)
+		if err != nil {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+		}
+		storeReq.ProcessExecutionId = &prcExeId
+	}
+
+	resp, err := s.processStore.GetLocalAttributes(ctx, storeReq)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+
+	return &GetLocalAttributesResponse{
+		ProcessExecutionId: resp.ProcessExecutionId.String(),
+		Attributes:         toLocalAttributeValues(resp.Attributes),
+	}, nil
+}
+
+func (s serviceImpl) SetLocalAttributes(
+	ctx context.Context, request SetLocalAttributesRequest,
+) (response *SetLocalAttributesResponse, retErr *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	if request.ProcessId == "" && request.ProcessExecutionId == nil {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "either processId or processExecutionId is required")
+	}
+	if len(request.Attributes) == 0 {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "attributes are required")
+	}
+
+	storeReq := data_models.SetLocalAttributesRequest{
+		Namespace: request.Namespace,
+		ProcessId: request.ProcessId,
+	}
+	if request.Reason != nil {
+		storeReq.Reason = *request.Reason
+	}
+	if request.ProcessExecutionId != nil {
+		prcExeId, err := uuid.ParseUUID(*request.ProcessExecutionId)
+		if err != nil {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "invalid processExecutionId: "+err.Error())
+		}
+		storeReq.ProcessExecutionId = &prcExeId
+	}
+
+	keys := map[string]bool{}
+	for _, attribute := range request.Attributes {
+		if attribute.Key == "" {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "key of the attributes is required")
+		}
+		if keys[attribute.Key] {
+			return nil, NewErrorWithStatus(http.StatusBadRequest, "duplicate key of the attributes: "+attribute.Key)
+		}
+		keys[attribute.Key] = true
+
+		storeReq.Attributes = append(storeReq.Attributes, data_models.LocalAttributeWrite{
+			Key:             attribute.Key,
+			Value:           attribute.Value,
+			ExpectedVersion: attribute.ExpectedVersion,
+		})
+	}
+
+	resp, err := s.processStore.SetLocalAttributes(ctx, storeReq)
+	if err != nil {
+		return nil, s.handleUnknownError(err)
+	}
+	if resp.NotExists {
+		return nil, NewErrorWithStatus(http.StatusNotFound, "Process does not exist")
+	}
+	if len(resp.VersionConflicts) > 0 {
+		details := "version conflicts of the local attributes:"
+		for _, conflict := range resp.VersionConflicts {
+			details += fmt.Sprintf(" %v(expected: %v, current: %v)",
+				conflict.Key, conflict.ExpectedVersion, conflict.CurrentVersion)
+		}
+		return nil, NewErrorWithStatus(http.StatusConflict, details)
+	}
+
+	return &SetLocalAttributesResponse{
+		ProcessExecutionId: resp.ProcessExecutionId.String(),
+		Attributes:         toLocalAttributeValues(resp.Attributes),
+	}, nil
+}
+
+func toLocalAttributeValues(attributes []data_models.LocalAttribute) []LocalAttributeValue {
+	values := []LocalAttributeValue{}
+	for _, attribute := range attributes {
+		values = append(values, LocalAttributeValue{
+			Key:     attribute.Key,
+			Value:   attribute.Value,
+			Version: attribute.Version,
+		})
+	}
+	return values
 }
 
 func (s serviceImpl) notifyRemoteImmediateTaskAsync(_ context.Context, req xcapi.NotifyImmediateTasksRequest) {
@@ -467,6 +1874,72 @@ func (s serviceImpl) askRemoteWaitForProcessCompletion(ctx context.Context, req
 	return resp, nil
 }
 
+func (s serviceImpl) SubscribeProcessEvents(
+	ctx context.Context, request ProcessEventsSubscribeRequest,
+) (<-chan ProcessEvent, *ErrorWithStatus) {
+	if request.Namespace == "" {
+		return nil, NewErrorWithStatus(http.StatusBadRequest, "namespace is required")
+	}
+	filter := engine.ProcessEventFilter{
+		Namespace:          request.Namespace,
+		ProcessId:          request.ProcessId,
+		ProcessExecutionId: request.ProcessExecutionId,
+		ProcessType:        request.ProcessType,
+	}
+
+	// the events are published by the async servers owning the shards of the process executions,
+	// so every async server is subscribed to
+	asyncAddresses := []string{s.cfg.ApiService.AsyncServiceAddress}
+	if s.membership != nil {
+		asyncAddresses = nil
+		seen := map[string]bool{}
+		for shardId := 0; shardId < s.cfg.Database.Shards; shardId++ {
+			address := s.membership.GetAsyncServerAddressForShard(int32(shardId))
+			if !seen[address] {
+				seen[address] = true
+				asyncAddresses = append(asyncAddresses, address)
+			}
+		}
+	}
+
+	// end all the streams once any of them ends, so that the client subscribes again to the current async servers
+	ctx, cancel := context.WithCancel(ctx)
+	var remoteEventsList []<-chan engine.ProcessEvent
+	for _, address := range asyncAddresses {
+		remoteEvents, err := async.SubscribeRemoteProcessEvents(ctx, address, filter)
+		if err != nil {
+			cancel()
+			s.logger.Error("failed to subscribe to remote process events", tag.Error(err))
+			return nil, NewErrorWithStatus(http.StatusInternalServerError, err.Error())
+		}
+		remoteEventsList = append(remoteEventsList, remoteEvents)
+	}
+
+	events := make(chan ProcessEvent)
+	var wg sync.WaitGroup
+	for _, remoteEvents := range remoteEventsList {
+		wg.Add(1)
+		go func(remoteEvents <-chan engine.ProcessEvent) {
+			defer wg.Done()
+			defer cancel()
+			for event := range remoteEvents {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(remoteEvents)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(events)
+	}()
+
+	return events, nil
+}
+
 func (s serviceImpl) handleUnknownError(err error) *ErrorWithStatus {
 	s.logger.Error("unknown error on operation", tag.Error(err))
 	return NewErrorWithStatus(500, err.Error())
@@ -0,0 +1,151 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/common/log/tag"
+	"github.com/xcherryio/xcherry/common/ptr"
+)
+
+const webUIDefaultPageSize = 20
+const webUIHistoryPageSize = 100
+
+// webUIAssets are the static files of the web UI. The actions of the web UI, e.g. stopping a process,
+// call the existing APIs directly, while reading is done by the read endpoints below.
+//
+//go:embed webui
+var webUIAssets embed.FS
+
+// registerWebUI serves the web UI and its read endpoints on the gin engine
+func registerWebUI(engine *gin.Engine, handler *ginHandler) {
+	assets, err := fs.Sub(webUIAssets, "webui")
+	if err != nil {
+		// the embedded directory always exists
+		panic(err)
+	}
+	engine.StaticFS(PathWebUI, http.FS(assets))
+	engine.GET(PathWebUIListProcessExecutions, handler.ListProcessExecutionsForWebUI)
+	engine.GET(PathWebUIDescribeProcessExecution, handler.DescribeProcessExecutionForWebUI)
+}
+
+func (h *ginHandler) ListProcessExecutionsForWebUI(c *gin.Context) {
+	var req WebUIProcessExecutionsListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	listReq := xcapi.ListProcessExecutionsRequest{
+		Namespace: req.Namespace,
+		PageSize:  req.PageSize,
+	}
+	if listReq.PageSize == 0 {
+		listReq.PageSize = webUIDefaultPageSize
+	}
+	if req.NextPageToken != "" {
+		listReq.NextPageToken = ptr.Any(req.NextPageToken)
+	}
+	var options ListProcessExecutionsOptions
+	if req.Query != "" {
+		options.Query = ptr.Any(req.Query)
+	} else {
+		listReq.StartTimeFilter = &xcapi.TimeRangeFilter{
+			EarliestTime: ptr.Any(int64(0)),
+			LatestTime:   ptr.Any(time.Now().Unix()),
+		}
+	}
+
+	var resp *xcapi.ListProcessExecutionsResponse
+	var infoOptions []ProcessExecutionListInfoOptions
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received ListProcessExecutionsForWebUI request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded ListProcessExecutionsForWebUI request",
+			tag.Value(h.toJson(resp)), tag.Value(h.toJson(infoOptions)), tag.Value(h.toJson(errResp)))
+	}()
+
+	resp, infoOptions, errResp = h.svc.ListProcessExecutions(c.Request.Context(), listReq, options)
+
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	body, err := mergeProcessExecutionsWithOptions(resp, infoOptions)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+func (h *ginHandler) DescribeProcessExecutionForWebUI(c *gin.Context) {
+	var req WebUIProcessExecutionDescribeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+
+	var errResp *ErrorWithStatus
+	h.logger.Debug("received DescribeProcessExecutionForWebUI request", tag.Value(h.toJson(req)))
+	defer func() {
+		h.logger.Debug("responded DescribeProcessExecutionForWebUI request", tag.Value(h.toJson(errResp)))
+	}()
+
+	ctx := c.Request.Context()
+	describeResp, describeOptions, errResp := h.svc.DescribeProcessExecutionById(ctx, ProcessExecutionDescribeByIdRequest{
+		Namespace:          req.Namespace,
+		ProcessExecutionId: req.ProcessExecutionId,
+	})
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	stateResp, errResp := h.svc.DescribeStateExecutions(ctx, DescribeStateExecutionsRequest{
+		Namespace:          req.Namespace,
+		ProcessId:          describeOptions.ProcessId,
+		ProcessExecutionId: ptr.Any(req.ProcessExecutionId),
+	})
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	historyResp, errResp := h.svc.GetProcessExecutionHistory(ctx, ProcessExecutionHistoryRequest{
+		Namespace:          req.Namespace,
+		ProcessId:          describeOptions.ProcessId,
+		ProcessExecutionId: ptr.Any(req.ProcessExecutionId),
+		PageSize:           ptr.Any(int32(webUIHistoryPageSize)),
+	})
+	if errResp != nil {
+		c.JSON(errResp.StatusCode, errResp.Error)
+		return
+	}
+
+	process, err := mergeJSONWithOptions(describeResp, describeOptions)
+	if err != nil {
+		h.logger.Error("error when serializing response", tag.Error(err))
+		c.JSON(http.StatusInternalServerError, xcapi.ApiErrorResponse{
+			Details: xcapi.PtrString(err.Error()),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, WebUIProcessExecutionDescribeResponse{
+		Process:              process,
+		StateExecutions:      stateResp.StateExecutions,
+		HistoryEvents:        historyResp.Events,
+		HistoryNextPageToken: historyResp.NextPageToken,
+	})
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+// The web UI reads through the /api/v1/xcherry/ui endpoints, and calls the existing APIs for the actions.
+// The current view is kept in the location hash, so that the pages can be shared and reloaded:
+//   #/executions?namespace=...&query=...
+//   #/execution?namespace=...&processExecutionId=...
+
+const listPath = "/api/v1/xcherry/ui/process-executions";
+const describePath = "/api/v1/xcherry/ui/process-execution";
+const stopPath = "/api/v1/xcherry/service/process-execution/stop";
+const publishPath = "/api/v1/xcherry/service/process-execution/publish-to-local-queue";
+const rpcPath = "/api/v1/xcherry/service/process-execution/rpc";
+
+const $ = (id) => document.getElementById(id);
+
+let nextPageToken = null;
+let currentProcess = null;
+
+async function request(method, path, body) {
+  const resp = await fetch(path, {
+    method: method,
+    headers: body ? {"Content-Type": "application/json"} : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  const text = await resp.text();
+  const data = text ? JSON.parse(text) : {};
+  if (!resp.ok) {
+    throw new Error((data && data.details) || resp.status + " " + resp.statusText);
+  }
+  return data;
+}
+
+function showError(err) {
+  $("error").textContent = err ? err.message : "";
+  $("error").hidden = !err;
+}
+
+function element(tag, text, className) {
+  const el = document.createElement(tag);
+  if (text !== undefined && text !== null) {
+    el.textContent = text;
+  }
+  if (className) {
+    el.className = className;
+  }
+  return el;
+}
+
+function row(cells) {
+  const tr = document.createElement("tr");
+  for (const cell of cells) {
+    const td = document.createElement("td");
+    if (cell instanceof Node) {
+      td.appendChild(cell);
+    } else {
+      td.textContent = cell === undefined || cell === null ? "" : cell;
+    }
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function formatTime(unixSeconds) {
+  return unixSeconds ? new Date(unixSeconds * 1000).toLocaleString() : "";
+}
+
+function formatJson(obj) {
+  return obj ? JSON.stringify(obj, null, 2) : "";
+}
+
+function executionLink(namespace, processExecutionId) {
+  const a = element("a", processExecutionId);
+  a.href = "#/execution?" + new URLSearchParams({namespace: namespace, processExecutionId: processExecutionId});
+  return a;
+}
+
+function parseHash() {
+  const hash = location.hash.replace(/^#/, "");
+  const [path, query] = hash.split("?");
+  return {path: path, params: new URLSearchParams(query || "")};
+}
+
+async function loadExecutions(namespace, query, append) {
+  const params = {namespace: namespace};
+  if (query) {
+    params.query = query;
+  }
+  if (append && nextPageToken) {
+    params.nextPageToken = nextPageToken;
+  }
+  const resp = await request("GET", listPath + "?" + new URLSearchParams(params));
+
+  const tbody = $("executions");
+  if (!append) {
+    tbody.replaceChildren();
+  }
+  for (const exe of resp.processExecutions || []) {
+    tbody.appendChild(row([
+      exe.processId,
+      exe.processType,
+      executionLink(namespace, exe.processExecutionId),
+      element("span", exe.status, "status-" + exe.status),
+      formatTime(exe.startTimestamp),
+      formatTime(exe.closeTimestamp),
+      exe.closeRecord ? exe.closeRecord.reasonType : "",
+    ]));
+  }
+  nextPageToken = resp.nextPageToken || null;
+  $("next-page").hidden = !nextPageToken;
+}
+
+async function loadExecution(namespace, processExecutionId) {
+  const resp = await request("GET", describePath + "?" + new URLSearchParams({
+    namespace: namespace,
+    processExecutionId: processExecutionId,
+  }));
+  const process = resp.process;
+  currentProcess = {namespace: namespace, processId: process.processId};
+
+  $("detail-title").textContent = process.processId + " / " + process.processExecutionId;
+  const info = $("process-info");
+  info.replaceChildren();
+  const fields = [
+    ["Namespace", namespace],
+    ["Process Type", process.processType],
+    ["Status", process.status],
+    ["Worker Url", process.workerUrl],
+    ["Start Time", formatTime(process.startTimestamp)],
+    ["Close Time", formatTime(process.closeTimestamp)],
+    ["Paused", process.paused ? "true" : "false"],
+  ];
+  for (const [name, value] of fields) {
+    info.appendChild(element("dt", name));
+    info.appendChild(element("dd", value));
+  }
+  for (const [name, id] of [
+    ["Continued From", process.continuedFromProcessExecutionId],
+    ["Continued As New", process.continuedAsNewProcessExecutionId],
+  ]) {
+    if (id) {
+      info.appendChild(element("dt", name));
+      const dd = element("dd");
+      dd.appendChild(executionLink(namespace, id));
+      info.appendChild(dd);
+    }
+  }
+  $("close-record").textContent = formatJson(process.closeRecord) || "the process execution is not closed";
+
+  const states = $("state-executions");
+  states.replaceChildren();
+  for (const state of resp.stateExecutions || []) {
+    states.appendChild(row([
+      state.stateId + "-" + state.stateIdSequence,
+      state.status,
+      state.pending ? "true" : "false",
+      element("pre", formatJson(state.lastFailure)),
+    ]));
+  }
+
+  const events = $("history-events");
+  events.replaceChildren();
+  for (const event of resp.historyEvents || []) {
+    events.appendChild(row([
+      event.eventId,
+      formatTime(event.timestamp),
+      event.eventType,
+      event.stateId ? event.stateId + "-" + event.stateIdSequence : "",
+      element("pre", formatJson(event.info)),
+    ]));
+  }
+  $("history-truncated").hidden = !resp.historyNextPageToken;
+  $("action-result").hidden = true;
+}
+
+async function render() {
+  showError(null);
+  const {path, params} = parseHash();
+  const namespace = params.get("namespace") || $("namespace").value;
+  $("namespace").value = namespace;
+  const isDetail = path === "/execution";
+  $("list-view").hidden = isDetail;
+  $("detail-view").hidden = !isDetail;
+  if (!namespace) {
+    return;
+  }
+  try {
+    if (isDetail) {
+      await loadExecution(namespace, params.get("processExecutionId"));
+    } else {
+      $("query").value = params.get("query") || "";
+      await loadExecutions(namespace, $("query").value, false);
+    }
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function encodedObject(encoding, data) {
+  return data || encoding ? {encoding: encoding, data: data} : undefined;
+}
+
+// the actions are applied on the latest process execution of the processId
+async function runAction(action) {
+  showError(null);
+  try {
+    const result = await action();
+    $("action-result").textContent = result === undefined ? "done" : formatJson(result);
+    $("action-result").hidden = false;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+$("search-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  const params = {namespace: $("namespace").value};
+  if ($("query").value) {
+    params.query = $("query").value;
+  }
+  const hash = "#/executions?" + new URLSearchParams(params);
+  if (location.hash === hash) {
+    render();
+  } else {
+    location.hash = hash;
+  }
+});
+
+$("next-page").addEventListener("click", async () => {
+  try {
+    await loadExecutions($("namespace").value, $("query").value, true);
+  } catch (err) {
+    showError(err);
+  }
+});
+
+$("stop-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  if (!confirm("Stop the latest execution of " + currentProcess.processId + "?")) {
+    return;
+  }
+  runAction(async () => {
+    await request("POST", stopPath, {
+      namespace: currentProcess.namespace,
+      processId: currentProcess.processId,
+      stopType: $("stop-type").value,
+      reason: $("stop-reason").value || undefined,
+    });
+    await render();
+  });
+});
+
+$("publish-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  runAction(() => request("POST", publishPath, {
+    namespace: currentProcess.namespace,
+    processId: currentProcess.processId,
+    messages: [{
+      queueName: $("publish-queue").value,
+      payload: encodedObject($("publish-encoding").value, $("publish-data").value),
+    }],
+  }).then(() => undefined));
+});
+
+$("rpc-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  runAction(() => request("POST", rpcPath, {
+    namespace: currentProcess.namespace,
+    processId: currentProcess.processId,
+    rpcName: $("rpc-name").value,
+    input: encodedObject($("rpc-encoding").value, $("rpc-data").value),
+  }));
+});
+
+window.addEventListener("hashchange", render);
+render();
@@ -18,6 +18,7 @@ import (
 const PathNotifyImmediateTasks = "/internal/api/v1/xcherry/notify-immediate-tasks"
 const PathNotifyTimerTasks = "/internal/api/v1/xcherry/notify-timer-tasks"
 const PathWaitForProcessCompletion = "/internal/api/v1/xcherry/wait-for-process-completion"
+const PathSubscribeProcessEvents = "/internal/api/v1/xcherry/subscribe-process-events"
 
 type defaultSever struct {
 	rootCtx context.Context
@@ -34,11 +35,12 @@ func NewDefaultAsyncServerWithGin(
 	cfg config.Config,
 	processStore persistence.ProcessStore,
 	visibilityStore persistence.VisibilityStore,
+	archiver persistence.Archiver,
 	logger log.Logger,
 ) Server {
 	engine := gin.Default()
 
-	svc := NewAsyncServiceImpl(rootCtx, processStore, visibilityStore, cfg, logger)
+	svc := NewAsyncServiceImpl(rootCtx, processStore, visibilityStore, archiver, cfg, logger)
 
 	membershipImpl := NewMembershipImpl(cfg, logger, &svc, ServerTypeAsync)
 
@@ -47,6 +49,7 @@ func NewDefaultAsyncServerWithGin(
 	engine.POST(PathNotifyImmediateTasks, handler.NotifyImmediateTasks)
 	engine.POST(PathNotifyTimerTasks, handler.NotifyTimerTasks)
 	engine.POST(PathWaitForProcessCompletion, handler.WaitForProcessCompletion)
+	engine.POST(PathSubscribeProcessEvents, handler.SubscribeProcessEvents)
 
 	svrCfg := cfg.AsyncService.InternalHttpServer
 	httpServer := &http.Server{
@@ -4,11 +4,14 @@
 package async
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/xcherryio/apis/goapi/xcapi"
 	"github.com/xcherryio/xcherry/common/log"
 	"github.com/xcherryio/xcherry/config"
+	"github.com/xcherryio/xcherry/engine"
+	"io"
 	"net/http"
 )
 
@@ -115,6 +118,41 @@ func (h *ginHandler) WaitForProcessCompletion(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// SubscribeProcessEvents streams the process events of this instance as newline delimited JSON.
+// It's not forwarded in the cluster mode, because the API service subscribes to every async server.
+func (h *ginHandler) SubscribeProcessEvents(c *gin.Context) {
+	var filter engine.ProcessEventFilter
+	if err := c.ShouldBindJSON(&filter); err != nil {
+		invalidRequestSchema(c)
+		return
+	}
+	if filter.Namespace == "" {
+		invalidRequestForError(c, fmt.Errorf("namespace is required"))
+		return
+	}
+
+	events, unsubscribe := h.svc.SubscribeProcessEvents(filter)
+	defer unsubscribe()
+
+	ctx, cancel := WithProcessEventStreamDeadline(c.Request.Context(), h.config.AsyncService.InternalHttpServer.WriteTimeout)
+	defer cancel()
+
+	// respond the headers right away, so that the subscriber doesn't wait for the first event
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-events:
+			return json.NewEncoder(w).Encode(event) == nil
+		}
+	})
+}
+
 func successRespond(c *gin.Context) {
 	c.JSON(http.StatusOK, map[string]string{
 		"message": "success",
@@ -6,6 +6,7 @@ package async
 import (
 	"context"
 	"github.com/xcherryio/apis/goapi/xcapi"
+	"github.com/xcherryio/xcherry/engine"
 )
 
 type Server interface {
@@ -25,6 +26,9 @@ type Service interface {
 	Stop(ctx context.Context) error
 	ReBalance(assignedShardIds []int32)
 	WaitForProcessCompletion(ctx context.Context, req xcapi.WaitForProcessCompletionRequest) (*xcapi.WaitForProcessCompletionResponse, error)
+	// SubscribeProcessEvents subscribes to the events of the process executions processed by this instance,
+	// the returned function must be called to unsubscribe
+	SubscribeProcessEvents(filter engine.ProcessEventFilter) (<-chan engine.ProcessEvent, func())
 }
 
 type Membership interface {
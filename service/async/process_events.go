@@ -0,0 +1,78 @@
+// Copyright (c) 2023 xCherryIO Organization
+// SPDX-License-Identifier: Apache-2.0
+
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/xcherryio/xcherry/engine"
+)
+
+// processEventStreamMargin is how long before the WriteTimeout of the http server a stream of process events is ended
+const processEventStreamMargin = time.Second
+
+// WithProcessEventStreamDeadline returns the context to end a stream of process events gracefully,
+// before the WriteTimeout of the http server cuts the connection. The clients are expected to subscribe again.
+func WithProcessEventStreamDeadline(
+	ctx context.Context, writeTimeout time.Duration,
+) (context.Context, context.CancelFunc) {
+	if writeTimeout <= processEventStreamMargin {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, writeTimeout-processEventStreamMargin)
+}
+
+// SubscribeRemoteProcessEvents subscribes to the process events of the async server.
+// The returned channel is closed when the context is done, or the async server ends the stream.
+func SubscribeRemoteProcessEvents(
+	ctx context.Context, serverAddress string, filter engine.ProcessEventFilter,
+) (<-chan engine.ProcessEvent, error) {
+	body, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serverAddress+PathSubscribeProcessEvents, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return nil, fmt.Errorf("failed to subscribe to process events of %v, status: %v, body: %v",
+			serverAddress, httpResp.StatusCode, string(respBody))
+	}
+
+	events := make(chan engine.ProcessEvent)
+	go func() {
+		defer close(events)
+		defer httpResp.Body.Close()
+
+		decoder := json.NewDecoder(httpResp.Body)
+		for {
+			var event engine.ProcessEvent
+			if err := decoder.Decode(&event); err != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -31,6 +31,8 @@ type asyncService struct {
 
 	immediateTaskProcessor engine.ImmediateTaskProcessor
 
+	processEventHub engine.ProcessEventHub
+
 	// shardId: queue
 	timerTaskQueueMap  map[int32]engine.TimerTaskQueue
 	timerTaskProcessor engine.TimerTaskProcessor
@@ -45,14 +47,15 @@ type asyncService struct {
 
 func NewAsyncServiceImpl(
 	rootCtx context.Context, processStore persistence.ProcessStore,
-	visibilityStore persistence.VisibilityStore,
+	visibilityStore persistence.VisibilityStore, archiver persistence.Archiver,
 	cfg config.Config, logger log.Logger,
 ) Service {
 	notifier := newTaskNotifierImpl()
+	processEventHub := engine.NewProcessEventHub()
 
 	immediateTaskProcessor := engine.NewImmediateTaskConcurrentProcessor(
-		rootCtx, cfg, notifier, processStore, visibilityStore, logger)
-	timerTaskProcessor := engine.NewTimerTaskConcurrentProcessor(rootCtx, cfg, notifier, processStore, logger)
+		rootCtx, cfg, notifier, processEventHub, processStore, visibilityStore, logger)
+	timerTaskProcessor := engine.NewTimerTaskConcurrentProcessor(rootCtx, cfg, notifier, processStore, archiver, logger)
 
 	return &asyncService{
 		// to be dynamically initialized later
@@ -63,7 +66,8 @@ func NewAsyncServiceImpl(
 		immediateTaskProcessor: immediateTaskProcessor,
 		timerTaskProcessor:     timerTaskProcessor,
 
-		taskNotifier: notifier,
+		taskNotifier:    notifier,
+		processEventHub: processEventHub,
 
 		processStore: processStore,
 
@@ -387,3 +391,7 @@ func (a *asyncService) WaitForProcessCompletion(ctx context.Context, req xcapi.W
 		}, nil
 	}
 }
+
+func (a *asyncService) SubscribeProcessEvents(filter engine.ProcessEventFilter) (<-chan engine.ProcessEvent, func()) {
+	return a.processEventHub.Subscribe(filter)
+}